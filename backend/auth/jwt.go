@@ -12,5 +12,8 @@ var JWTSecret = []byte(os.Getenv("JWT_SECRET"))
 // CustomClaims embeds the standard RegisteredClaims and adds own fields
 type CustomClaims struct {
 	UserID string `jsong:"sub"`
+	// Role is the user's authorization role; tokens issued before roles
+	// existed carry none and are treated as regular users
+	Role string `json:"role,omitempty"`
 	jwt.RegisteredClaims
 }
@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/daedal00/muse/backend/internal/logging"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// unexported type to avoid collisions with other packages
+type ctxKey string
+
+// UserIDKey is the context key JWTMiddleware stores the authenticated user ID under
+const UserIDKey ctxKey = "userID"
+
+// SessionIDKey is the context key JWTMiddleware stores the token's session
+// ID (its jti claim) under, for tokens issued with one
+const SessionIDKey ctxKey = "sessionID"
+
+// RoleKey is the context key JWTMiddleware stores the token's role under
+const RoleKey ctxKey = "role"
+
+// SessionValidator reports whether the session a token was issued for is
+// still active
+type SessionValidator func(ctx context.Context, sessionID string) bool
+
+// JWTMiddleware authenticates requests carrying an "Authorization: Bearer
+// <token>" header signed with secret. A valid token puts its user ID into the
+// request context under UserIDKey, its role under RoleKey, and its session ID
+// under SessionIDKey if it has one; anonymous, malformed and invalid requests
+// are passed through without one so resolvers decide what needs a user.
+func JWTMiddleware(secret string) func(http.Handler) http.Handler {
+	return JWTMiddlewareWithSessions(secret, nil)
+}
+
+// JWTMiddlewareWithSessions is JWTMiddleware that also rejects tokens whose
+// session sessionActive reports as ended, so logging out or deleting a
+// session revokes its tokens. Tokens without a session ID are not checked.
+func JWTMiddlewareWithSessions(secret string, sessionActive SessionValidator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, authStatus := authenticate(r.Header.Get("Authorization"), secret)
+			if claims != nil && claims.ID != "" && sessionActive != nil && !sessionActive(r.Context(), claims.ID) {
+				log.Printf("[AUTH] ❌ Session %s has ended", claims.ID)
+				claims, authStatus = nil, "revoked"
+			}
+
+			var userID string
+			if claims != nil {
+				userID = claims.UserID
+			}
+			log.Printf("[AUTH] Request status: %s, UserID: %s", authStatus, userID)
+			logging.AddAttrs(r.Context(), slog.String("auth_status", authStatus))
+
+			if claims != nil {
+				ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
+				ctx = context.WithValue(ctx, RoleKey, claims.Role)
+				if claims.ID != "" {
+					ctx = context.WithValue(ctx, SessionIDKey, claims.ID)
+				}
+				r = r.WithContext(ctx)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// authenticate validates an Authorization header, returning the claims of a
+// valid token (if any) and one of "authenticated", "invalid", "malformed" or
+// "anonymous"
+func authenticate(authHeader, secret string) (*CustomClaims, string) {
+	if authHeader == "" {
+		log.Printf("[AUTH] No authorization header - anonymous request")
+		return nil, "anonymous"
+	}
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		log.Printf("[AUTH] ❌ Invalid authorization header format")
+		return nil, "malformed"
+	}
+
+	tokStr := strings.TrimSpace(strings.TrimPrefix(authHeader, "Bearer "))
+	log.Printf("[AUTH] Processing JWT token (length: %d)", len(tokStr))
+
+	token, err := jwt.ParseWithClaims(tokStr, &CustomClaims{}, func(t *jwt.Token) (interface{}, error) {
+		// Ensure HMAC is used
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method")
+		}
+		return []byte(secret), nil
+	})
+	if err != nil {
+		log.Printf("[AUTH] ❌ JWT validation failed: %v", err)
+		return nil, "invalid"
+	}
+
+	claims, ok := token.Claims.(*CustomClaims)
+	if !ok || !token.Valid || claims.UserID == "" {
+		log.Printf("[AUTH] ❌ Invalid token")
+		return nil, "invalid"
+	}
+
+	log.Printf("[AUTH] ✅ User authenticated: %s", claims.UserID)
+	return claims, "authenticated"
+}
@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func signTestToken(t *testing.T, method jwt.SigningMethod, key interface{}, userID string, expiresAt time.Time) string {
+	t.Helper()
+
+	claims := &CustomClaims{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	tokenString, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	require.NoError(t, err)
+	return tokenString
+}
+
+func TestJWTMiddleware(t *testing.T) {
+	userID := uuid.New().String()
+	secret := []byte(testJWTSecret)
+	later := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name       string
+		header     string
+		wantUserID string
+	}{
+		{name: "anonymous", header: ""},
+		{name: "malformed header", header: "Token abc"},
+		{name: "garbage token", header: "Bearer invalid.token.here"},
+		{name: "invalid signature", header: "Bearer " + signTestToken(t, jwt.SigningMethodHS256, []byte("wrong-secret"), userID, later)},
+		{name: "expired", header: "Bearer " + signTestToken(t, jwt.SigningMethodHS256, secret, userID, time.Now().Add(-time.Hour))},
+		{name: "non-HMAC signing method", header: "Bearer " + signTestToken(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, userID, later)},
+		{name: "valid", header: "Bearer " + signTestToken(t, jwt.SigningMethodHS256, secret, userID, later), wantUserID: userID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var called bool
+			var gotUserID interface{}
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				gotUserID = r.Context().Value(UserIDKey)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/query", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			JWTMiddleware(testJWTSecret)(next).ServeHTTP(httptest.NewRecorder(), req)
+
+			// Unauthenticated requests still reach the handler, just without a user
+			require.True(t, called)
+			if tt.wantUserID == "" {
+				assert.Nil(t, gotUserID)
+			} else {
+				assert.Equal(t, tt.wantUserID, gotUserID)
+			}
+		})
+	}
+}
+
+func TestJWTMiddleware_SessionID(t *testing.T) {
+	userID := uuid.New().String()
+	sessionID := uuid.New().String()
+
+	claims := &CustomClaims{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        sessionID,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+	withSession, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testJWTSecret))
+	require.NoError(t, err)
+	withoutSession := signTestToken(t, jwt.SigningMethodHS256, []byte(testJWTSecret), userID, time.Now().Add(time.Hour))
+
+	for header, want := range map[string]interface{}{
+		"Bearer " + withSession:    sessionID,
+		"Bearer " + withoutSession: nil,
+	} {
+		var gotSessionID interface{}
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotSessionID = r.Context().Value(SessionIDKey)
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/query", nil)
+		req.Header.Set("Authorization", header)
+		JWTMiddleware(testJWTSecret)(next).ServeHTTP(httptest.NewRecorder(), req)
+
+		assert.Equal(t, want, gotSessionID)
+	}
+}
+
+func TestJWTMiddlewareWithSessions(t *testing.T) {
+	userID := uuid.New().String()
+	signWithSession := func(sessionID string) string {
+		claims := &CustomClaims{
+			UserID: userID,
+			RegisteredClaims: jwt.RegisteredClaims{
+				ID:        sessionID,
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			},
+		}
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testJWTSecret))
+		require.NoError(t, err)
+		return token
+	}
+	active := func(ctx context.Context, sessionID string) bool { return sessionID == "active" }
+
+	tests := []struct {
+		name       string
+		token      string
+		wantUserID interface{}
+	}{
+		{name: "active session", token: signWithSession("active"), wantUserID: userID},
+		{name: "ended session", token: signWithSession("ended"), wantUserID: nil},
+		{name: "no session", token: signWithSession(""), wantUserID: userID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUserID interface{}
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotUserID = r.Context().Value(UserIDKey)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/query", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			JWTMiddlewareWithSessions(testJWTSecret, active)(next).ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tt.wantUserID, gotUserID)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PasswordRequirement identifies a single rule of a PasswordPolicy
+type PasswordRequirement string
+
+const (
+	RequirementMinLength PasswordRequirement = "min_length"
+	RequirementMixedCase PasswordRequirement = "mixed_case"
+	RequirementDigit     PasswordRequirement = "digit"
+)
+
+// PasswordPolicy describes the strength rules a new password must satisfy
+type PasswordPolicy struct {
+	MinLength        int
+	RequireMixedCase bool
+	RequireDigit     bool
+}
+
+// DefaultPasswordPolicy returns the policy used when nothing is configured
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:        8,
+		RequireMixedCase: true,
+		RequireDigit:     true,
+	}
+}
+
+// PasswordPolicyError lists every requirement a password failed to meet
+type PasswordPolicyError struct {
+	Unmet  []PasswordRequirement
+	Policy PasswordPolicy
+}
+
+func (e *PasswordPolicyError) Error() string {
+	descriptions := make([]string, 0, len(e.Unmet))
+	for _, requirement := range e.Unmet {
+		switch requirement {
+		case RequirementMinLength:
+			descriptions = append(descriptions, fmt.Sprintf("at least %d characters", e.Policy.MinLength))
+		case RequirementMixedCase:
+			descriptions = append(descriptions, "both upper and lower case letters")
+		case RequirementDigit:
+			descriptions = append(descriptions, "at least one digit")
+		}
+	}
+	return "password must contain " + strings.Join(descriptions, ", ")
+}
+
+// Validate checks a password against the policy, returning a *PasswordPolicyError
+// listing all unmet requirements, or nil if the password is acceptable
+func (p PasswordPolicy) Validate(password string) error {
+	var unmet []PasswordRequirement
+
+	if utf8.RuneCountInString(password) < p.MinLength {
+		unmet = append(unmet, RequirementMinLength)
+	}
+
+	var hasUpper, hasLower, hasDigit bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+
+	if p.RequireMixedCase && (!hasUpper || !hasLower) {
+		unmet = append(unmet, RequirementMixedCase)
+	}
+	if p.RequireDigit && !hasDigit {
+		unmet = append(unmet, RequirementDigit)
+	}
+
+	if len(unmet) > 0 {
+		return &PasswordPolicyError{Unmet: unmet, Policy: p}
+	}
+
+	return nil
+}
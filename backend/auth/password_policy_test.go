@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordPolicy_Validate(t *testing.T) {
+	policy := DefaultPasswordPolicy()
+
+	tests := []struct {
+		name     string
+		password string
+		unmet    []PasswordRequirement
+	}{
+		{"too short", "Ab1", []PasswordRequirement{RequirementMinLength}},
+		{"no upper case", "lowercase123", []PasswordRequirement{RequirementMixedCase}},
+		{"no lower case", "UPPERCASE123", []PasswordRequirement{RequirementMixedCase}},
+		{"no digit", "NoDigitsHere", []PasswordRequirement{RequirementDigit}},
+		{"fails everything", "abc", []PasswordRequirement{RequirementMinLength, RequirementMixedCase, RequirementDigit}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Validate(tt.password)
+			require.Error(t, err)
+
+			var policyErr *PasswordPolicyError
+			require.True(t, errors.As(err, &policyErr))
+			assert.Equal(t, tt.unmet, policyErr.Unmet)
+		})
+	}
+}
+
+func TestPasswordPolicy_Validate_Compliant(t *testing.T) {
+	policy := DefaultPasswordPolicy()
+
+	assert.NoError(t, policy.Validate("Compliant123"))
+}
+
+func TestPasswordPolicy_Validate_Configurable(t *testing.T) {
+	// A relaxed policy only enforces length
+	policy := PasswordPolicy{MinLength: 4}
+
+	assert.NoError(t, policy.Validate("abcd"))
+	assert.Error(t, policy.Validate("abc"))
+}
+
+func TestPasswordPolicyError_Message(t *testing.T) {
+	err := DefaultPasswordPolicy().Validate("abc")
+	require.Error(t, err)
+
+	assert.Contains(t, err.Error(), "at least 8 characters")
+	assert.Contains(t, err.Error(), "both upper and lower case letters")
+	assert.Contains(t, err.Error(), "at least one digit")
+}
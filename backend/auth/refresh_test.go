@@ -0,0 +1,28 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRefreshToken(t *testing.T) {
+	first, err := NewRefreshToken()
+	require.NoError(t, err)
+	second, err := NewRefreshToken()
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, first)
+	assert.NotEqual(t, first, second)
+}
+
+func TestHashRefreshToken(t *testing.T) {
+	token, err := NewRefreshToken()
+	require.NoError(t, err)
+
+	// The hash is stable so stored tokens can be looked up, and never the token itself
+	assert.Equal(t, HashRefreshToken(token), HashRefreshToken(token))
+	assert.NotEqual(t, token, HashRefreshToken(token))
+	assert.Len(t, HashRefreshToken(token), 64)
+}
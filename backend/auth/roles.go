@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/daedal00/muse/backend/internal/models"
+)
+
+var (
+	// ErrUnauthenticated is returned by RequireRole for anonymous requests
+	ErrUnauthenticated = errors.New("unauthenticated")
+
+	// ErrForbidden is returned by RequireRole when the user lacks every
+	// allowed role
+	ErrForbidden = errors.New("forbidden")
+)
+
+// RoleFromContext returns the role of the request's authenticated user.
+// Authenticated tokens without a role count as models.RoleUser; anonymous
+// requests have no role.
+func RoleFromContext(ctx context.Context) string {
+	if _, ok := ctx.Value(UserIDKey).(string); !ok {
+		return ""
+	}
+	role, _ := ctx.Value(RoleKey).(string)
+	if role == "" {
+		return models.RoleUser
+	}
+	return role
+}
+
+// RequireRole checks that the request's user has one of roles, for resolvers
+// such as admin queries:
+//
+//	if err := auth.RequireRole(ctx, models.RoleAdmin); err != nil {
+//		return nil, err
+//	}
+func RequireRole(ctx context.Context, roles ...string) error {
+	role := RoleFromContext(ctx)
+	if role == "" {
+		return ErrUnauthenticated
+	}
+	if !slices.Contains(roles, role) {
+		return ErrForbidden
+	}
+	return nil
+}
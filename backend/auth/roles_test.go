@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/daedal00/muse/backend/internal/models"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// contextForToken runs a request bearing a token with the given role through
+// JWTMiddleware and returns the context the handler saw
+func contextForToken(t *testing.T, role string) context.Context {
+	t.Helper()
+
+	claims := &CustomClaims{
+		UserID: uuid.New().String(),
+		Role:   role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testJWTSecret))
+	require.NoError(t, err)
+
+	var ctx context.Context
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx = r.Context()
+	})
+	req := httptest.NewRequest(http.MethodPost, "/query", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	JWTMiddleware(testJWTSecret)(next).ServeHTTP(httptest.NewRecorder(), req)
+
+	require.NotNil(t, ctx)
+	return ctx
+}
+
+func TestRoleFromContext(t *testing.T) {
+	assert.Equal(t, models.RoleAdmin, RoleFromContext(contextForToken(t, models.RoleAdmin)))
+	assert.Equal(t, models.RoleUser, RoleFromContext(contextForToken(t, models.RoleUser)))
+
+	// Tokens issued before roles existed are regular users
+	assert.Equal(t, models.RoleUser, RoleFromContext(contextForToken(t, "")))
+
+	assert.Empty(t, RoleFromContext(context.Background()))
+}
+
+func TestRequireRole(t *testing.T) {
+	assert.NoError(t, RequireRole(contextForToken(t, models.RoleAdmin), models.RoleAdmin))
+	assert.NoError(t, RequireRole(contextForToken(t, models.RoleUser), models.RoleUser, models.RoleAdmin))
+
+	assert.ErrorIs(t, RequireRole(contextForToken(t, models.RoleUser), models.RoleAdmin), ErrForbidden)
+	assert.ErrorIs(t, RequireRole(context.Background(), models.RoleAdmin), ErrUnauthenticated)
+}
@@ -2,18 +2,22 @@ package main
 
 import (
 	"fmt"
+	"io/fs"
 	"log"
 	"os"
+	"sort"
 
 	"github.com/daedal00/muse/backend/internal/config"
+	"github.com/daedal00/muse/backend/migrations"
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
 )
 
 func main() {
 	if len(os.Args) < 2 {
-		log.Fatal("Usage: go run cmd/migrate/main.go [up|down|drop|version|force <version>]")
+		log.Fatal("Usage: go run cmd/migrate/main.go [up|down|drop|version|pending|goto <version>|steps <n>|force <version>]")
 	}
 
 	command := os.Args[1]
@@ -25,10 +29,11 @@ func main() {
 	}
 
 	// Create migration instance
-	m, err := migrate.New(
-		"file://migrations",
-		cfg.GetDatabaseDSN(),
-	)
+	src, err := migrationSource(cfg.MigrationsPath)
+	if err != nil {
+		log.Fatalf("Failed to open migrations: %v", err)
+	}
+	m, err := migrate.NewWithSourceInstance("migrations", src, cfg.GetDatabaseDSN())
 	if err != nil {
 		log.Fatalf("Failed to create migration instance: %v", err)
 	}
@@ -60,6 +65,57 @@ func main() {
 		}
 		fmt.Printf("Current migration version: %d, dirty: %t\n", version, dirty)
 
+	case "pending":
+		// Only reads the current version; nothing is applied
+		version, dirty, err := m.Version()
+		if err != nil && err != migrate.ErrNilVersion {
+			log.Fatalf("Failed to get migration version: %v", err)
+		}
+		pending, err := pendingMigrations(migrationFS(cfg.MigrationsPath), version)
+		if err != nil {
+			log.Fatalf("Failed to list pending migrations: %v", err)
+		}
+		if dirty {
+			fmt.Printf("⚠️  Database is dirty at version %d; fix it with force before applying more\n", version)
+		}
+		if len(pending) == 0 {
+			fmt.Println("✅ No pending migrations")
+			break
+		}
+		fmt.Printf("%d pending migrations:\n", len(pending))
+		for _, name := range pending {
+			fmt.Printf("  %s\n", name)
+		}
+
+	case "goto":
+		if len(os.Args) < 3 {
+			log.Fatal("Usage: go run cmd/migrate/main.go goto <version>")
+		}
+		version := os.Args[2]
+		var v int
+		if _, err := fmt.Sscanf(version, "%d", &v); err != nil || v < 0 {
+			log.Fatalf("Invalid version number: %s", version)
+		}
+		if err := m.Migrate(uint(v)); err != nil && err != migrate.ErrNoChange {
+			log.Fatalf("Failed to migrate to version %d: %v", v, err)
+		}
+		fmt.Printf("✅ Migrated to version %d\n", v)
+
+	case "steps":
+		if len(os.Args) < 3 {
+			log.Fatal("Usage: go run cmd/migrate/main.go steps <n>")
+		}
+		steps := os.Args[2]
+		var n int
+		if _, err := fmt.Sscanf(steps, "%d", &n); err != nil || n == 0 {
+			log.Fatalf("Invalid step count: %s", steps)
+		}
+		// Positive counts apply migrations, negative ones roll them back
+		if err := m.Steps(n); err != nil && err != migrate.ErrNoChange {
+			log.Fatalf("Failed to apply %d migration steps: %v", n, err)
+		}
+		fmt.Printf("✅ Applied %d migration steps\n", n)
+
 	case "force":
 		if len(os.Args) < 3 {
 			log.Fatal("Usage: go run cmd/migrate/main.go force <version>")
@@ -75,6 +131,52 @@ func main() {
 		fmt.Printf("✅ Forced migration to version %d\n", v)
 
 	default:
-		log.Fatal("Unknown command. Use: up, down, drop, version, or force")
+		log.Fatal("Unknown command. Use: up, down, drop, version, pending, goto, steps, or force")
+	}
+}
+
+// migrationFS returns the directory at path, or the migrations embedded in
+// the binary when path is empty so the tool works from any working directory
+func migrationFS(path string) fs.FS {
+	if path != "" {
+		return os.DirFS(path)
+	}
+	return migrations.FS
+}
+
+// migrationSource reads migrations from migrationFS(path)
+func migrationSource(path string) (source.Driver, error) {
+	return iofs.New(migrationFS(path), ".")
+}
+
+// pendingMigrations lists, in order, the up migrations in fsys newer than
+// current, the version the database is at (0 for none applied)
+func pendingMigrations(fsys fs.FS, current uint) ([]string, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	type pending struct {
+		version uint
+		name    string
+	}
+	var ups []pending
+	for _, entry := range entries {
+		m, err := source.DefaultParse(entry.Name())
+		if err != nil || m.Direction != source.Up {
+			continue // not a migration file, or a down migration
+		}
+		if m.Version > current {
+			ups = append(ups, pending{version: m.Version, name: entry.Name()})
+		}
+	}
+
+	sort.Slice(ups, func(i, j int) bool { return ups[i].version < ups[j].version })
+
+	names := make([]string, len(ups))
+	for i, up := range ups {
+		names[i] = up.name
 	}
+	return names, nil
 }
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -136,6 +138,12 @@ func TestCommandLineArguments(t *testing.T) {
 		{"down command", []string{"migrate", "down"}, false},
 		{"drop command", []string{"migrate", "drop"}, false},
 		{"version command", []string{"migrate", "version"}, false},
+		{"pending command", []string{"migrate", "pending"}, false},
+		{"goto command with version", []string{"migrate", "goto", "5"}, false},
+		{"goto command without version", []string{"migrate", "goto"}, true},
+		{"steps command up", []string{"migrate", "steps", "2"}, false},
+		{"steps command down", []string{"migrate", "steps", "-1"}, false},
+		{"steps command without count", []string{"migrate", "steps"}, true},
 		{"force command with version", []string{"migrate", "force", "123"}, false},
 		{"force command without version", []string{"migrate", "force"}, true},
 		{"invalid command", []string{"migrate", "invalid"}, true},
@@ -157,7 +165,7 @@ func TestCommandLineArguments(t *testing.T) {
 			command := os.Args[1]
 
 			// Test command validation
-			validCommands := []string{"up", "down", "drop", "version", "force"}
+			validCommands := []string{"up", "down", "drop", "version", "pending", "goto", "steps", "force"}
 			isValid := false
 			for _, validCmd := range validCommands {
 				if command == validCmd {
@@ -167,15 +175,15 @@ func TestCommandLineArguments(t *testing.T) {
 			}
 
 			if tt.shouldError {
-				if command == "force" && len(os.Args) < 3 {
-					assert.True(t, true, "Force command requires version argument")
+				if (command == "force" || command == "goto" || command == "steps") && len(os.Args) < 3 {
+					assert.True(t, true, "Force, goto and steps commands require an argument")
 				} else if !isValid {
 					assert.False(t, isValid, "Expected invalid command")
 				}
 			} else {
 				assert.True(t, isValid, "Expected valid command")
-				if command == "force" {
-					assert.GreaterOrEqual(t, len(os.Args), 3, "Force command should have version argument")
+				if command == "force" || command == "goto" || command == "steps" {
+					assert.GreaterOrEqual(t, len(os.Args), 3, "Force, goto and steps commands should have an argument")
 				}
 			}
 		})
@@ -212,12 +220,103 @@ func TestForceVersionParsing(t *testing.T) {
 	}
 }
 
-func TestMigrationFileSource(t *testing.T) {
-	// Test that the migration file source path is correct
-	migrationSource := "file://migrations"
+func TestStepsCountParsing(t *testing.T) {
+	tests := []struct {
+		name        string
+		steps       string
+		shouldError bool
+		expected    int
+	}{
+		{"up two", "2", false, 2},
+		{"down one", "-1", false, -1},
+		{"zero", "0", true, 0},
+		{"invalid string", "abc", true, 0},
+		{"empty string", "", true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var n int
+			_, err := fmt.Sscanf(tt.steps, "%d", &n)
+
+			// main rejects a zero count as well as unparsable input
+			if tt.shouldError {
+				assert.True(t, err != nil || n == 0)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, n)
+			}
+		})
+	}
+}
+
+func TestMigrationSource_Embedded(t *testing.T) {
+	src, err := migrationSource("")
+	require.NoError(t, err)
+	defer src.Close()
+
+	// The embedded migrations start at a valid version and can be walked in order
+	first, err := src.First()
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, first, uint(0))
+
+	count := 1
+	for version := first; ; count++ {
+		next, err := src.Next(version)
+		if errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		require.NoError(t, err)
+		assert.Greater(t, next, version)
+		version = next
+	}
+
+	upFiles, err := filepath.Glob("../../migrations/*.up.sql")
+	require.NoError(t, err)
+	assert.Equal(t, len(upFiles), count, "Expected every migration on disk to be embedded")
+}
+
+func TestPendingMigrations(t *testing.T) {
+	dir := t.TempDir()
+	files := []string{
+		"001_initial.up.sql", "001_initial.down.sql",
+		"002_add_reviews.up.sql", "002_add_reviews.down.sql",
+		"010_add_tags.up.sql", "010_add_tags.down.sql",
+		"003_add_follows.up.sql", "003_add_follows.down.sql",
+		"README.md",
+	}
+	for _, name := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("SELECT 1;"), 0o644))
+	}
+
+	// Up migrations newer than the database's version, in version order
+	pending, err := pendingMigrations(os.DirFS(dir), 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"003_add_follows.up.sql", "010_add_tags.up.sql"}, pending)
+
+	// A fresh database has every migration pending
+	pending, err = pendingMigrations(os.DirFS(dir), 0)
+	require.NoError(t, err)
+	assert.Len(t, pending, 4)
+	assert.Equal(t, "001_initial.up.sql", pending[0])
+
+	// An up-to-date database has none
+	pending, err = pendingMigrations(os.DirFS(dir), 10)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
+
+func TestMigrationSource_Path(t *testing.T) {
+	src, err := migrationSource("../../migrations")
+	require.NoError(t, err)
+	defer src.Close()
+
+	first, err := src.First()
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), first)
 
-	assert.True(t, strings.HasPrefix(migrationSource, "file://"))
-	assert.True(t, strings.HasSuffix(migrationSource, "migrations"))
+	_, err = migrationSource("does-not-exist")
+	assert.Error(t, err)
 }
 
 func TestEnvironmentVariableHandling(t *testing.T) {
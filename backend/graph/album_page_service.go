@@ -0,0 +1,97 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/daedal00/muse/backend/internal/models"
+	"github.com/daedal00/muse/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// Album page limits
+const (
+	albumPageTrackLimit  = 100
+	albumPageReviewLimit = 20
+)
+
+// albumPageService assembles the album page with a fixed number of batched
+// repository calls, however many tracks or reviews the album has
+type albumPageService struct {
+	repos *repository.Repositories
+}
+
+func newAlbumPageService(repos *repository.Repositories) *albumPageService {
+	return &albumPageService{repos: repos}
+}
+
+// GetAlbumPageData returns the album with its artist, its tracks, the first
+// page of reviews with their authors and rating stats, and the viewer's own
+// review if they wrote one. A nil viewerID skips the viewer's review.
+func (s *albumPageService) GetAlbumPageData(ctx context.Context, viewerID uuid.UUID, albumSpotifyID string) (*models.AlbumPageData, error) {
+	album, err := s.repos.Album.GetBySpotifyID(ctx, albumSpotifyID)
+	if err != nil {
+		return nil, err
+	}
+
+	artist, err := s.repos.Artist.GetByID(ctx, album.ArtistID)
+	if err != nil {
+		return nil, err
+	}
+	album.Artist = artist
+
+	tracks, err := s.repos.Track.GetByAlbumID(ctx, album.ID, albumPageTrackLimit, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := s.repos.Review.GetReviewSummary(ctx, album.ID, albumPageReviewLimit, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var viewerReview *models.Review
+	if viewerID != uuid.Nil {
+		// ListFiltered reports no review as an empty page rather than an error
+		reviews, err := s.repos.Review.ListFiltered(ctx, repository.ReviewFilter{
+			UserID:  &viewerID,
+			AlbumID: &album.ID,
+		}, 1, 0)
+		if err != nil {
+			return nil, err
+		}
+		if len(reviews) > 0 {
+			viewerReview = reviews[0]
+		}
+	}
+
+	// Load every author on the page, viewer included, in one lookup
+	var userIDs []uuid.UUID
+	for _, review := range summary.Reviews {
+		userIDs = append(userIDs, review.UserID)
+	}
+	if viewerReview != nil {
+		userIDs = append(userIDs, viewerReview.UserID)
+	}
+
+	users, err := s.repos.User.GetByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load review authors: %w", err)
+	}
+
+	for _, review := range summary.Reviews {
+		review.User = users[review.UserID]
+		review.Album = album
+	}
+	if viewerReview != nil {
+		viewerReview.User = users[viewerReview.UserID]
+		viewerReview.Album = album
+	}
+
+	return &models.AlbumPageData{
+		Album:        album,
+		Tracks:       tracks,
+		Reviews:      summary,
+		ViewerReview: viewerReview,
+	}, nil
+}
@@ -0,0 +1,199 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/daedal00/muse/backend/internal/models"
+	"github.com/daedal00/muse/backend/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// albumPageStore is the in-memory data behind the album page fakes; calls
+// counts repository calls by method
+type albumPageStore struct {
+	artist  *models.Artist
+	album   *models.Album
+	tracks  []*models.Track
+	reviews []*models.Review
+	users   map[uuid.UUID]*models.User
+	calls   map[string]int
+}
+
+type pageAlbumRepository struct {
+	repository.AlbumRepository
+	store *albumPageStore
+}
+
+func (r *pageAlbumRepository) GetBySpotifyID(ctx context.Context, spotifyID string) (*models.Album, error) {
+	r.store.calls["Album.GetBySpotifyID"]++
+	if r.store.album.SpotifyID == nil || *r.store.album.SpotifyID != spotifyID {
+		return nil, fmt.Errorf("album not found")
+	}
+	album := *r.store.album
+	return &album, nil
+}
+
+type pageArtistRepository struct {
+	repository.ArtistRepository
+	store *albumPageStore
+}
+
+func (r *pageArtistRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Artist, error) {
+	r.store.calls["Artist.GetByID"]++
+	return r.store.artist, nil
+}
+
+type pageTrackRepository struct {
+	repository.TrackRepository
+	store *albumPageStore
+}
+
+func (r *pageTrackRepository) GetByAlbumID(ctx context.Context, albumID uuid.UUID, limit, offset int) ([]*models.Track, error) {
+	r.store.calls["Track.GetByAlbumID"]++
+	return r.store.tracks, nil
+}
+
+type pageReviewRepository struct {
+	repository.ReviewRepository
+	store *albumPageStore
+}
+
+func (r *pageReviewRepository) GetReviewSummary(ctx context.Context, albumID uuid.UUID, limit, offset int) (*models.ReviewSummary, error) {
+	r.store.calls["Review.GetReviewSummary"]++
+	summary := &models.ReviewSummary{Distribution: map[int]int{1: 0, 2: 0, 3: 0, 4: 0, 5: 0}}
+	sum := 0
+	for _, review := range r.store.reviews {
+		copied := *review
+		summary.Reviews = append(summary.Reviews, &copied)
+		summary.Distribution[review.Rating]++
+		summary.Count++
+		sum += review.Rating
+	}
+	if summary.Count > 0 {
+		summary.AverageRating = float64(sum) / float64(summary.Count)
+	}
+	return summary, nil
+}
+
+func (r *pageReviewRepository) ListFiltered(ctx context.Context, filter repository.ReviewFilter, limit, offset int) ([]*models.Review, error) {
+	r.store.calls["Review.ListFiltered"]++
+	var reviews []*models.Review
+	for _, review := range r.store.reviews {
+		if review.UserID == *filter.UserID && review.AlbumID == *filter.AlbumID {
+			copied := *review
+			reviews = append(reviews, &copied)
+		}
+	}
+	return reviews, nil
+}
+
+type pageUserRepository struct {
+	repository.UserRepository
+	store *albumPageStore
+}
+
+func (r *pageUserRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.User, error) {
+	r.store.calls["User.GetByIDs"]++
+	users := make(map[uuid.UUID]*models.User)
+	for _, id := range ids {
+		if user, ok := r.store.users[id]; ok {
+			users[id] = user
+		}
+	}
+	return users, nil
+}
+
+func newAlbumPageFixture(reviewers int) (*albumPageService, *albumPageStore) {
+	spotifyID := "album-spotify-id"
+	artist := &models.Artist{ID: uuid.New(), Name: "Artist"}
+	store := &albumPageStore{
+		artist: artist,
+		album:  &models.Album{ID: uuid.New(), SpotifyID: &spotifyID, Title: "Album", ArtistID: artist.ID},
+		users:  make(map[uuid.UUID]*models.User),
+		calls:  make(map[string]int),
+	}
+
+	for i := 0; i < 12; i++ {
+		store.tracks = append(store.tracks, &models.Track{ID: uuid.New(), Title: fmt.Sprintf("Track %d", i+1), AlbumID: store.album.ID})
+	}
+	for i := 0; i < reviewers; i++ {
+		user := &models.User{ID: uuid.New(), Name: fmt.Sprintf("Reviewer %d", i+1)}
+		store.users[user.ID] = user
+		store.reviews = append(store.reviews, &models.Review{
+			ID: uuid.New(), UserID: user.ID, AlbumID: store.album.ID, Rating: i%5 + 1,
+		})
+	}
+
+	repos := &repository.Repositories{
+		User:   &pageUserRepository{store: store},
+		Artist: &pageArtistRepository{store: store},
+		Album:  &pageAlbumRepository{store: store},
+		Track:  &pageTrackRepository{store: store},
+		Review: &pageReviewRepository{store: store},
+	}
+	return newAlbumPageService(repos), store
+}
+
+func TestGetAlbumPageData_AssemblesPageInBatchedCalls(t *testing.T) {
+	service, store := newAlbumPageFixture(8)
+	viewer := store.reviews[3]
+
+	page, err := service.GetAlbumPageData(context.Background(), viewer.UserID, *store.album.SpotifyID)
+	require.NoError(t, err)
+
+	assert.Equal(t, store.album.ID, page.Album.ID)
+	require.NotNil(t, page.Album.Artist)
+	assert.Equal(t, store.artist.Name, page.Album.Artist.Name)
+	assert.Equal(t, store.tracks, page.Tracks)
+
+	require.Len(t, page.Reviews.Reviews, len(store.reviews))
+	assert.Equal(t, len(store.reviews), page.Reviews.Count)
+	for i, review := range page.Reviews.Reviews {
+		assert.Equal(t, store.reviews[i].ID, review.ID)
+		require.NotNil(t, review.User)
+		assert.Equal(t, store.users[review.UserID].Name, review.User.Name)
+		assert.Equal(t, page.Album, review.Album)
+	}
+
+	require.NotNil(t, page.ViewerReview)
+	assert.Equal(t, viewer.ID, page.ViewerReview.ID)
+	assert.Equal(t, store.users[viewer.UserID], page.ViewerReview.User)
+
+	// One call per repository method regardless of track and review counts
+	for method, count := range store.calls {
+		assert.Equal(t, 1, count, "expected %s to be called once", method)
+	}
+	assert.Len(t, store.calls, 6)
+}
+
+func TestGetAlbumPageData_AnonymousViewer(t *testing.T) {
+	service, store := newAlbumPageFixture(3)
+
+	page, err := service.GetAlbumPageData(context.Background(), uuid.Nil, *store.album.SpotifyID)
+	require.NoError(t, err)
+
+	assert.Nil(t, page.ViewerReview)
+	assert.Len(t, page.Reviews.Reviews, 3)
+	assert.Zero(t, store.calls["Review.ListFiltered"])
+}
+
+func TestGetAlbumPageData_ViewerWithoutReview(t *testing.T) {
+	service, store := newAlbumPageFixture(3)
+
+	page, err := service.GetAlbumPageData(context.Background(), uuid.New(), *store.album.SpotifyID)
+	require.NoError(t, err)
+
+	assert.Nil(t, page.ViewerReview)
+}
+
+func TestGetAlbumPageData_UnknownAlbum(t *testing.T) {
+	service, store := newAlbumPageFixture(1)
+
+	_, err := service.GetAlbumPageData(context.Background(), uuid.Nil, "unknown")
+	assert.Error(t, err)
+	assert.Zero(t, store.calls["Track.GetByAlbumID"])
+}
@@ -0,0 +1,85 @@
+package graph
+
+// Complexity weights for list fields. A connection costs its child
+// complexity once per node it can return, so nesting lists multiplies cost
+// and deep fan-out queries are rejected before any resolver runs.
+const (
+	// defaultPageSize matches the limit the connection resolvers use when
+	// first is not given
+	defaultPageSize = 10
+	// maxPageSize bounds first so a huge page cannot overflow the estimate
+	maxPageSize = 100
+	// defaultRecentlyPlayedLimit matches the schema default for recentlyPlayed
+	defaultRecentlyPlayedLimit = 20
+	// audioStatsCost weights Playlist.audioStats, which may fetch audio
+	// features from Spotify for every track in the playlist
+	audioStatsCost = 50
+)
+
+// NewConfig returns the executable schema config for resolver, with list
+// fields weighted for extension.FixedComplexityLimit
+func NewConfig(resolver *Resolver) Config {
+	cfg := Config{Resolvers: resolver}
+
+	cfg.Complexity.Query.Albums = connectionComplexity
+	cfg.Complexity.Query.Tracks = connectionComplexity
+	cfg.Complexity.Query.Playlists = connectionComplexity
+	cfg.Complexity.Query.Reviews = func(childComplexity int, first *int32, after *string, _ *bool) int {
+		return connectionComplexity(childComplexity, first, after)
+	}
+	cfg.Complexity.Query.MyReviewsByGenre = func(childComplexity int, _ string, first *int32, _ *int32) int {
+		return listComplexity(childComplexity, first, defaultPageSize)
+	}
+	cfg.Complexity.Query.RecentlyPlayed = func(childComplexity int, limit *int32) int {
+		return listComplexity(childComplexity, limit, defaultRecentlyPlayedLimit)
+	}
+
+	// The album page lists are capped by the service, not by arguments
+	cfg.Complexity.AlbumPage.Tracks = func(childComplexity int) int {
+		return listComplexity(childComplexity, nil, albumPageTrackLimit)
+	}
+	cfg.Complexity.AlbumPage.Reviews = func(childComplexity int) int {
+		return listComplexity(childComplexity, nil, albumPageReviewLimit)
+	}
+
+	cfg.Complexity.Playlist.AudioStats = func(childComplexity int) int {
+		return audioStatsCost + childComplexity
+	}
+
+	// Nested connections take no arguments and return a default-sized page
+	cfg.Complexity.Album.Tracks = nestedConnectionComplexity
+	cfg.Complexity.Album.Reviews = nestedConnectionComplexity
+	cfg.Complexity.Artist.Albums = nestedConnectionComplexity
+	cfg.Complexity.Playlist.Tracks = nestedConnectionComplexity
+	cfg.Complexity.User.Playlists = nestedConnectionComplexity
+	cfg.Complexity.User.Reviews = nestedConnectionComplexity
+
+	return cfg
+}
+
+func connectionComplexity(childComplexity int, first *int32, _ *string) int {
+	return listComplexity(childComplexity, first, defaultPageSize)
+}
+
+func nestedConnectionComplexity(childComplexity int) int {
+	return listComplexity(childComplexity, nil, defaultPageSize)
+}
+
+// pageSize returns the number of items a connection resolver fetches for
+// first: defaultPageSize when unset, clamped to [1, maxPageSize] otherwise, so
+// a page is never larger than the complexity estimate assumed
+func pageSize(first *int32) int {
+	if first == nil {
+		return defaultPageSize
+	}
+	return min(max(int(*first), 1), maxPageSize)
+}
+
+// listComplexity weights a list field by the number of items it may return
+func listComplexity(childComplexity int, requested *int32, defaultSize int) int {
+	size := defaultSize
+	if requested != nil {
+		size = min(max(int(*requested), 1), maxPageSize)
+	}
+	return 1 + childComplexity*size
+}
@@ -0,0 +1,60 @@
+package graph
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/daedal00/muse/backend/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListComplexity(t *testing.T) {
+	first := func(n int32) *int32 { return &n }
+
+	assert.Equal(t, 1+3*defaultPageSize, listComplexity(3, nil, defaultPageSize))
+	assert.Equal(t, 1+3*50, listComplexity(3, first(50), defaultPageSize))
+	assert.Equal(t, 1+3*maxPageSize, listComplexity(3, first(1_000_000), defaultPageSize))
+	assert.Equal(t, 1+3, listComplexity(3, first(-5), defaultPageSize))
+}
+
+// serveLimited runs query against a schema with no repositories and a
+// complexity limit of 1000, returning the response body
+func serveLimited(t *testing.T, query string) string {
+	t.Helper()
+
+	// With no repositories configured any resolver that ran would panic, so
+	// a clean complexity error shows the query was rejected up front
+	resolver := &Resolver{repos: &repository.Repositories{}}
+
+	srv := handler.New(NewExecutableSchema(NewConfig(resolver)))
+	srv.AddTransport(transport.POST{})
+	srv.SetRecoverFunc(RecoverFunc)
+	srv.Use(extension.FixedComplexityLimit(1000))
+
+	req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(query))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+
+	return rec.Body.String()
+}
+
+func TestComplexityLimit_RejectsDeepQueries(t *testing.T) {
+	body := serveLimited(t, `{"query":"{ reviews(first: 100) { edges { node { user { playlists { edges { node { tracks { edges { node { id } } } } } } } } } } }"}`)
+	assert.Contains(t, body, "exceeds the limit of 1000")
+	assert.Contains(t, body, "COMPLEXITY_LIMIT_EXCEEDED")
+	assert.NotContains(t, body, internalErrorMessage)
+	assert.Contains(t, body, `"data":null`)
+}
+
+func TestComplexityLimit_WeightsAudioStats(t *testing.T) {
+	// A page of 100 playlists costs a few hundred, but asking each for its
+	// audio stats puts it over the limit
+	body := serveLimited(t, `{"query":"{ playlists(first: 100) { edges { node { id audioStats { energy } } } } }"}`)
+	assert.Contains(t, body, "COMPLEXITY_LIMIT_EXCEEDED")
+}
@@ -3,13 +3,24 @@ package graph
 
 import (
 	"context"
+
+	"github.com/daedal00/muse/backend/auth"
 )
 
-// unexported type to avoid collisions with other packages
-type ctxKey string
+// UserIDKey stores userID, resolvers can access it directly now. It is the
+// key auth.JWTMiddleware sets.
+const UserIDKey = auth.UserIDKey
+
+// SessionIDKey stores the session ID of the request's token, if it has one
+const SessionIDKey = auth.SessionIDKey
 
-// UserIDKey stores userID, resolvers can access it directly now
-const UserIDKey ctxKey = "userID"
+type clientInfoKey struct{}
+
+// ClientInfo describes the client a request came from
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
 
 // helper function to pull userID from context
 func ForContext(ctx context.Context) (string, bool) {
@@ -20,3 +31,22 @@ func ForContext(ctx context.Context) (string, bool) {
 	id, ok := raw.(string)
 	return id, ok
 }
+
+// SessionForContext returns the session ID of the request's token
+func SessionForContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(SessionIDKey).(string)
+	return id, ok && id != ""
+}
+
+// WithClientInfo returns a copy of ctx carrying info, so resolvers can record
+// where a request came from
+func WithClientInfo(ctx context.Context, info ClientInfo) context.Context {
+	return context.WithValue(ctx, clientInfoKey{}, info)
+}
+
+// ClientInfoForContext returns the client info stored by WithClientInfo, or
+// the zero value if there is none
+func ClientInfoForContext(ctx context.Context) ClientInfo {
+	info, _ := ctx.Value(clientInfoKey{}).(ClientInfo)
+	return info
+}
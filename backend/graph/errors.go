@@ -0,0 +1,58 @@
+package graph
+
+import (
+	"context"
+	"errors"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/daedal00/muse/backend/auth"
+	"github.com/daedal00/muse/backend/graph/loaders"
+	"github.com/daedal00/muse/backend/internal/repository"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Codes set in the "code" extension of errors caused by repository and
+// authorization sentinels
+const (
+	errCodeNotFound        = "NOT_FOUND"
+	errCodeAlreadyExists   = "ALREADY_EXISTS"
+	errCodeConflict        = "CONFLICT"
+	errCodeUnauthenticated = "UNAUTHENTICATED"
+	errCodeForbidden       = "FORBIDDEN"
+)
+
+// ErrorPresenter formats resolver errors like gqlgen's default presenter,
+// adding a "code" extension when the error wraps a repository sentinel so
+// clients can tell a missing or duplicate record from a failure
+func ErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+
+	code := errorCode(err)
+	if code == "" {
+		return gqlErr
+	}
+	if gqlErr.Extensions == nil {
+		gqlErr.Extensions = map[string]interface{}{}
+	}
+	if _, ok := gqlErr.Extensions["code"]; !ok {
+		gqlErr.Extensions["code"] = code
+	}
+	return gqlErr
+}
+
+func errorCode(err error) string {
+	switch {
+	case errors.Is(err, repository.ErrNotFound), errors.Is(err, loaders.ErrNotFound):
+		return errCodeNotFound
+	case errors.Is(err, repository.ErrDuplicate):
+		return errCodeAlreadyExists
+	case errors.Is(err, repository.ErrConflict):
+		return errCodeConflict
+	case errors.Is(err, auth.ErrUnauthenticated):
+		return errCodeUnauthenticated
+	case errors.Is(err, auth.ErrForbidden):
+		return errCodeForbidden
+	default:
+		return ""
+	}
+}
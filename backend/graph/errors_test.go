@@ -0,0 +1,72 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/daedal00/muse/backend/auth"
+	"github.com/daedal00/muse/backend/internal/models"
+	"github.com/daedal00/muse/backend/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type missingUserRepository struct {
+	repository.UserRepository
+}
+
+func (missingUserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	return nil, fmt.Errorf("user %w", repository.ErrNotFound)
+}
+
+func TestErrorPresenter_SetsCodeForRepositoryErrors(t *testing.T) {
+	resolver := &Resolver{repos: &repository.Repositories{User: missingUserRepository{}}}
+
+	srv := handler.New(NewExecutableSchema(Config{Resolvers: resolver}))
+	srv.AddTransport(transport.POST{})
+	srv.SetErrorPresenter(ErrorPresenter)
+
+	query := `{"query":"{ user(id: \"7f3ad1a4-6a4f-4f5e-9d55-8f1a2b3c4d5e\") { id } }"}`
+	req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(query))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+
+	var resp struct {
+		Errors []struct {
+			Message    string                 `json:"message"`
+			Extensions map[string]interface{} `json:"extensions"`
+		} `json:"errors"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Len(t, resp.Errors, 1)
+	assert.Contains(t, resp.Errors[0].Message, "user not found")
+	assert.Equal(t, errCodeNotFound, resp.Errors[0].Extensions["code"])
+}
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{fmt.Errorf("playlist %w", repository.ErrNotFound), errCodeNotFound},
+		{fmt.Errorf("failed to create review: %w", repository.ErrDuplicateReview), errCodeAlreadyExists},
+		{repository.ErrAlreadyImported, errCodeAlreadyExists},
+		{repository.ErrSelfFollow, errCodeConflict},
+		{auth.ErrUnauthenticated, errCodeUnauthenticated},
+		{auth.ErrForbidden, errCodeForbidden},
+		{fmt.Errorf("failed to get user: connection refused"), ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, errorCode(tt.err), tt.err.Error())
+	}
+}
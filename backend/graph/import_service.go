@@ -0,0 +1,217 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/daedal00/muse/backend/internal/models"
+	"github.com/daedal00/muse/backend/internal/repository"
+	"github.com/daedal00/muse/backend/internal/spotify"
+	"github.com/google/uuid"
+	spotifyapi "github.com/zmb3/spotify/v2"
+)
+
+// importFetcher loads the Spotify catalog entries an import copies from
+type importFetcher interface {
+	FetchAlbum(ctx context.Context, id string) (*spotifyapi.FullAlbum, error)
+	FetchTrack(ctx context.Context, id string) (*spotifyapi.FullTrack, error)
+}
+
+// importService saves Spotify albums and tracks as Muse records, creating the
+// artist and album rows they hang off as needed. Imports are idempotent:
+// items already saved are returned as they are without asking Spotify again.
+type importService struct {
+	artists repository.ArtistRepository
+	albums  repository.AlbumRepository
+	tracks  repository.TrackRepository
+	fetcher importFetcher
+}
+
+func newImportService(repos *repository.Repositories, fetcher importFetcher) *importService {
+	return &importService{
+		artists: repos.Artist,
+		albums:  repos.Album,
+		tracks:  repos.Track,
+		fetcher: fetcher,
+	}
+}
+
+// ImportAlbum returns the Muse album for a Spotify album, with its artist
+// populated, importing both on first use
+func (s *importService) ImportAlbum(ctx context.Context, spotifyAlbumID string) (*models.Album, error) {
+	album, err := s.albums.GetBySpotifyID(ctx, spotifyAlbumID)
+	if err == nil {
+		return s.withArtist(ctx, album)
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("failed to get album: %w", err)
+	}
+
+	full, err := s.fetcher.FetchAlbum(ctx, spotifyAlbumID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch spotify album %s: %w", spotifyAlbumID, err)
+	}
+	return s.ensureAlbum(ctx, full.SimpleAlbum)
+}
+
+// ImportTrack returns the Muse track for a Spotify track, with its album and
+// artist populated, importing all three on first use
+func (s *importService) ImportTrack(ctx context.Context, spotifyTrackID string) (*models.Track, error) {
+	track, err := s.tracks.GetBySpotifyID(ctx, spotifyTrackID)
+	if err == nil {
+		album, err := s.albums.GetByID(ctx, track.AlbumID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get album: %w", err)
+		}
+		if track.Album, err = s.withArtist(ctx, album); err != nil {
+			return nil, err
+		}
+		return track, nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("failed to get track: %w", err)
+	}
+
+	full, err := s.fetcher.FetchTrack(ctx, spotifyTrackID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch spotify track %s: %w", spotifyTrackID, err)
+	}
+
+	album, err := s.ensureAlbum(ctx, full.Album)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	spotifyID := string(full.ID)
+	duration := int(full.Duration)
+	trackNumber := int(full.TrackNumber)
+	track = &models.Track{
+		ID:          uuid.New(),
+		SpotifyID:   &spotifyID,
+		Title:       full.Name,
+		AlbumID:     album.ID,
+		DurationMs:  &duration,
+		TrackNumber: &trackNumber,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.tracks.Create(ctx, track); err != nil {
+		if !errors.Is(err, repository.ErrDuplicate) {
+			return nil, fmt.Errorf("failed to create track: %w", err)
+		}
+		// Imported concurrently; use the row that won
+		if track, err = s.tracks.GetBySpotifyID(ctx, spotifyID); err != nil {
+			return nil, fmt.Errorf("failed to get track: %w", err)
+		}
+	}
+
+	track.Album = album
+	return track, nil
+}
+
+// ensureAlbum returns the Muse album for a Spotify album, creating it and
+// its first listed artist if they are not saved yet
+func (s *importService) ensureAlbum(ctx context.Context, src spotifyapi.SimpleAlbum) (*models.Album, error) {
+	spotifyID := string(src.ID)
+	album, err := s.albums.GetBySpotifyID(ctx, spotifyID)
+	if err == nil {
+		return s.withArtist(ctx, album)
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("failed to get album: %w", err)
+	}
+
+	if len(src.Artists) == 0 {
+		return nil, fmt.Errorf("spotify album %s has no artist", spotifyID)
+	}
+	artist, err := s.ensureArtist(ctx, src.Artists[0])
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	album = &models.Album{
+		ID:        uuid.New(),
+		SpotifyID: &spotifyID,
+		Title:     src.Name,
+		ArtistID:  artist.ID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if released := src.ReleaseDateTime(); !released.IsZero() {
+		album.ReleaseDate = &released
+	}
+	if len(src.Images) > 0 {
+		album.CoverImage = &src.Images[0].URL
+	}
+
+	if err := s.albums.Create(ctx, album); err != nil {
+		if !errors.Is(err, repository.ErrDuplicate) {
+			return nil, fmt.Errorf("failed to create album: %w", err)
+		}
+		if album, err = s.albums.GetBySpotifyID(ctx, spotifyID); err != nil {
+			return nil, fmt.Errorf("failed to get album: %w", err)
+		}
+	}
+
+	album.Artist = artist
+	return album, nil
+}
+
+// ensureArtist returns the Muse artist for a Spotify artist, creating it if
+// it is not saved yet
+func (s *importService) ensureArtist(ctx context.Context, src spotifyapi.SimpleArtist) (*models.Artist, error) {
+	spotifyID := string(src.ID)
+	artist, err := s.artists.GetBySpotifyID(ctx, spotifyID)
+	if err == nil {
+		return artist, nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("failed to get artist: %w", err)
+	}
+
+	now := time.Now()
+	artist = &models.Artist{
+		ID:        uuid.New(),
+		SpotifyID: &spotifyID,
+		Name:      src.Name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.artists.Create(ctx, artist); err != nil {
+		if !errors.Is(err, repository.ErrDuplicate) {
+			return nil, fmt.Errorf("failed to create artist: %w", err)
+		}
+		if artist, err = s.artists.GetBySpotifyID(ctx, spotifyID); err != nil {
+			return nil, fmt.Errorf("failed to get artist: %w", err)
+		}
+	}
+
+	return artist, nil
+}
+
+// withArtist populates an album's artist
+func (s *importService) withArtist(ctx context.Context, album *models.Album) (*models.Album, error) {
+	artist, err := s.artists.GetByID(ctx, album.ArtistID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get artist: %w", err)
+	}
+	album.Artist = artist
+	return album, nil
+}
+
+// spotifyImportFetcher fetches import sources from the Spotify API
+type spotifyImportFetcher struct {
+	services *spotify.Services
+}
+
+func (f *spotifyImportFetcher) FetchAlbum(ctx context.Context, id string) (*spotifyapi.FullAlbum, error) {
+	return f.services.Album.GetAlbum(ctx, spotifyapi.ID(id))
+}
+
+func (f *spotifyImportFetcher) FetchTrack(ctx context.Context, id string) (*spotifyapi.FullTrack, error) {
+	return f.services.Track.GetTrack(ctx, spotifyapi.ID(id))
+}
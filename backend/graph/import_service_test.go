@@ -0,0 +1,228 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/daedal00/muse/backend/internal/models"
+	"github.com/daedal00/muse/backend/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	spotifyapi "github.com/zmb3/spotify/v2"
+)
+
+// importStore is the in-memory catalog behind the import fakes, keyed by
+// Spotify ID
+type importStore struct {
+	artists map[string]*models.Artist
+	albums  map[string]*models.Album
+	tracks  map[string]*models.Track
+}
+
+func newImportStore() *importStore {
+	return &importStore{
+		artists: make(map[string]*models.Artist),
+		albums:  make(map[string]*models.Album),
+		tracks:  make(map[string]*models.Track),
+	}
+}
+
+type importArtistRepository struct {
+	repository.ArtistRepository
+	store *importStore
+}
+
+func (r importArtistRepository) Create(ctx context.Context, artist *models.Artist) error {
+	if _, ok := r.store.artists[*artist.SpotifyID]; ok {
+		return fmt.Errorf("artist %w", repository.ErrDuplicate)
+	}
+	r.store.artists[*artist.SpotifyID] = artist
+	return nil
+}
+
+func (r importArtistRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Artist, error) {
+	for _, artist := range r.store.artists {
+		if artist.ID == id {
+			return artist, nil
+		}
+	}
+	return nil, fmt.Errorf("artist %w", repository.ErrNotFound)
+}
+
+func (r importArtistRepository) GetBySpotifyID(ctx context.Context, spotifyID string) (*models.Artist, error) {
+	if artist, ok := r.store.artists[spotifyID]; ok {
+		return artist, nil
+	}
+	return nil, fmt.Errorf("artist %w", repository.ErrNotFound)
+}
+
+type importAlbumRepository struct {
+	repository.AlbumRepository
+	store *importStore
+}
+
+func (r importAlbumRepository) Create(ctx context.Context, album *models.Album) error {
+	if _, ok := r.store.albums[*album.SpotifyID]; ok {
+		return fmt.Errorf("album %w", repository.ErrDuplicate)
+	}
+	stored := *album
+	stored.Artist = nil
+	r.store.albums[*album.SpotifyID] = &stored
+	return nil
+}
+
+func (r importAlbumRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Album, error) {
+	for _, album := range r.store.albums {
+		if album.ID == id {
+			found := *album
+			return &found, nil
+		}
+	}
+	return nil, fmt.Errorf("album %w", repository.ErrNotFound)
+}
+
+func (r importAlbumRepository) GetBySpotifyID(ctx context.Context, spotifyID string) (*models.Album, error) {
+	if album, ok := r.store.albums[spotifyID]; ok {
+		found := *album
+		return &found, nil
+	}
+	return nil, fmt.Errorf("album %w", repository.ErrNotFound)
+}
+
+type importTrackRepository struct {
+	repository.TrackRepository
+	store *importStore
+}
+
+func (r importTrackRepository) Create(ctx context.Context, track *models.Track) error {
+	if _, ok := r.store.tracks[*track.SpotifyID]; ok {
+		return fmt.Errorf("track %w", repository.ErrDuplicate)
+	}
+	stored := *track
+	r.store.tracks[*track.SpotifyID] = &stored
+	return nil
+}
+
+func (r importTrackRepository) GetBySpotifyID(ctx context.Context, spotifyID string) (*models.Track, error) {
+	if track, ok := r.store.tracks[spotifyID]; ok {
+		found := *track
+		return &found, nil
+	}
+	return nil, fmt.Errorf("track %w", repository.ErrNotFound)
+}
+
+// fakeImportFetcher serves one album with one track and counts requests
+type fakeImportFetcher struct {
+	album *spotifyapi.FullAlbum
+	track *spotifyapi.FullTrack
+	calls int
+}
+
+func newFakeImportFetcher() *fakeImportFetcher {
+	album := spotifyapi.SimpleAlbum{
+		ID:                   "album-1",
+		Name:                 "Blue Train",
+		Artists:              []spotifyapi.SimpleArtist{{ID: "artist-1", Name: "John Coltrane"}},
+		ReleaseDate:          "1957-09-15",
+		ReleaseDatePrecision: "day",
+		Images:               []spotifyapi.Image{{URL: "https://example.com/blue-train.jpg"}},
+	}
+	return &fakeImportFetcher{
+		album: &spotifyapi.FullAlbum{SimpleAlbum: album},
+		track: &spotifyapi.FullTrack{
+			SimpleTrack: spotifyapi.SimpleTrack{ID: "track-1", Name: "Moment's Notice", Duration: 548000, TrackNumber: 2},
+			Album:       album,
+		},
+	}
+}
+
+func (f *fakeImportFetcher) FetchAlbum(ctx context.Context, id string) (*spotifyapi.FullAlbum, error) {
+	f.calls++
+	if id != string(f.album.ID) {
+		return nil, fmt.Errorf("album %s not found", id)
+	}
+	return f.album, nil
+}
+
+func (f *fakeImportFetcher) FetchTrack(ctx context.Context, id string) (*spotifyapi.FullTrack, error) {
+	f.calls++
+	if id != string(f.track.ID) {
+		return nil, fmt.Errorf("track %s not found", id)
+	}
+	return f.track, nil
+}
+
+func newTestImportService(store *importStore, fetcher importFetcher) *importService {
+	return newImportService(&repository.Repositories{
+		Artist: importArtistRepository{store: store},
+		Album:  importAlbumRepository{store: store},
+		Track:  importTrackRepository{store: store},
+	}, fetcher)
+}
+
+func TestImportService_ImportTrack(t *testing.T) {
+	store := newImportStore()
+	fetcher := newFakeImportFetcher()
+	service := newTestImportService(store, fetcher)
+
+	track, err := service.ImportTrack(context.Background(), "track-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Moment's Notice", track.Title)
+	require.NotNil(t, track.DurationMs)
+	assert.Equal(t, 548000, *track.DurationMs)
+	require.NotNil(t, track.Album)
+	assert.Equal(t, "Blue Train", track.Album.Title)
+	require.NotNil(t, track.Album.ReleaseDate)
+	assert.Equal(t, 1957, track.Album.ReleaseDate.Year())
+	require.NotNil(t, track.Album.Artist)
+	assert.Equal(t, "John Coltrane", track.Album.Artist.Name)
+
+	// Importing again returns the same rows without asking Spotify
+	again, err := service.ImportTrack(context.Background(), "track-1")
+	require.NoError(t, err)
+	assert.Equal(t, track.ID, again.ID)
+	assert.Equal(t, track.Album.ID, again.Album.ID)
+	assert.Equal(t, track.Album.Artist.ID, again.Album.Artist.ID)
+	assert.Equal(t, 1, fetcher.calls)
+	assert.Len(t, store.artists, 1)
+	assert.Len(t, store.albums, 1)
+	assert.Len(t, store.tracks, 1)
+}
+
+func TestImportService_ImportAlbum(t *testing.T) {
+	store := newImportStore()
+	fetcher := newFakeImportFetcher()
+	service := newTestImportService(store, fetcher)
+
+	album, err := service.ImportAlbum(context.Background(), "album-1")
+	require.NoError(t, err)
+	require.NotNil(t, album.CoverImage)
+	assert.Equal(t, "https://example.com/blue-train.jpg", *album.CoverImage)
+
+	again, err := service.ImportAlbum(context.Background(), "album-1")
+	require.NoError(t, err)
+	assert.Equal(t, album.ID, again.ID)
+	require.NotNil(t, again.Artist)
+	assert.Equal(t, album.ArtistID, again.Artist.ID)
+	assert.Equal(t, 1, fetcher.calls)
+
+	// A track on the saved album reuses its album and artist
+	track, err := service.ImportTrack(context.Background(), "track-1")
+	require.NoError(t, err)
+	assert.Equal(t, album.ID, track.AlbumID)
+	assert.Len(t, store.albums, 1)
+	assert.Len(t, store.artists, 1)
+}
+
+func TestImportService_UnknownItem(t *testing.T) {
+	store := newImportStore()
+	service := newTestImportService(store, newFakeImportFetcher())
+
+	_, err := service.ImportAlbum(context.Background(), "missing")
+	assert.Error(t, err)
+	assert.Empty(t, store.albums)
+	assert.Empty(t, store.artists)
+}
@@ -0,0 +1,247 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/daedal00/muse/backend/graph/model"
+	"github.com/daedal00/muse/backend/internal/repository"
+	"github.com/daedal00/muse/backend/internal/spotify"
+	spotifyapi "github.com/zmb3/spotify/v2"
+)
+
+// maxResolveItemRefs bounds how many references one resolveItems call accepts
+const maxResolveItemRefs = 100
+
+// Spotify batch endpoint limits
+const (
+	spotifyTrackBatchSize  = 50
+	spotifyAlbumBatchSize  = 20
+	spotifyArtistBatchSize = 50
+)
+
+// itemFetcher loads item metadata that is not in the cache
+type itemFetcher interface {
+	FetchTracks(ctx context.Context, ids []string) (map[string]*model.TrackSearchResult, error)
+	FetchAlbums(ctx context.Context, ids []string) (map[string]*model.AlbumSearchResult, error)
+	FetchArtists(ctx context.Context, ids []string) (map[string]*model.ArtistSearchResult, error)
+}
+
+// itemService hydrates Spotify item references from the music cache, falling
+// back to Spotify for misses and writing what it fetched back to the cache
+type itemService struct {
+	cache   repository.MusicCacheRepository
+	fetcher itemFetcher
+}
+
+func newItemService(cache repository.MusicCacheRepository, fetcher itemFetcher) *itemService {
+	return &itemService{cache: cache, fetcher: fetcher}
+}
+
+// Resolve returns the items behind refs in input order. Duplicate refs are
+// looked up once; refs Spotify does not know are left out.
+func (s *itemService) Resolve(ctx context.Context, refs []*model.ItemRefInput) ([]model.ResolvedItem, error) {
+	// Partition the refs by type, dropping duplicates
+	ids := make(map[model.ItemType][]string)
+	seen := make(map[model.ItemRefInput]bool)
+	for _, ref := range refs {
+		if !ref.Type.IsValid() {
+			return nil, fmt.Errorf("invalid item type: %s", ref.Type)
+		}
+		if seen[*ref] {
+			continue
+		}
+		seen[*ref] = true
+		ids[ref.Type] = append(ids[ref.Type], ref.ID)
+	}
+
+	tracks, err := hydrateItems(ctx, s.cache, itemCacheType(model.ItemTypeTrack), ids[model.ItemTypeTrack], s.fetcher.FetchTracks)
+	if err != nil {
+		return nil, err
+	}
+	albums, err := hydrateItems(ctx, s.cache, itemCacheType(model.ItemTypeAlbum), ids[model.ItemTypeAlbum], s.fetcher.FetchAlbums)
+	if err != nil {
+		return nil, err
+	}
+	artists, err := hydrateItems(ctx, s.cache, itemCacheType(model.ItemTypeArtist), ids[model.ItemTypeArtist], s.fetcher.FetchArtists)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]model.ResolvedItem, 0, len(refs))
+	for _, ref := range refs {
+		switch ref.Type {
+		case model.ItemTypeTrack:
+			if track, ok := tracks[ref.ID]; ok {
+				items = append(items, track)
+			}
+		case model.ItemTypeAlbum:
+			if album, ok := albums[ref.ID]; ok {
+				items = append(items, album)
+			}
+		case model.ItemTypeArtist:
+			if artist, ok := artists[ref.ID]; ok {
+				items = append(items, artist)
+			}
+		}
+	}
+
+	return items, nil
+}
+
+// itemCacheType is the cache item type Spotify metadata of itemType is kept under
+func itemCacheType(itemType model.ItemType) string {
+	return strings.ToLower(itemType.String())
+}
+
+// hydrateItems looks ids up in the cache under cacheType in one batch and
+// fetches the rest, remembering IDs Spotify did not return so they are not
+// fetched again for a while. Cache failures are logged and treated as misses.
+func hydrateItems[T any](ctx context.Context, cache repository.MusicCacheRepository, cacheType string, ids []string, fetch func(context.Context, []string) (map[string]T, error)) (map[string]T, error) {
+	items := make(map[string]T, len(ids))
+	if len(ids) == 0 {
+		return items, nil
+	}
+
+	cached, err := cache.GetSpotifyItems(ctx, cacheType, ids)
+	if err != nil {
+		log.Printf("[CACHE] Warning: Failed to read cached %s items: %v", cacheType, err)
+	}
+
+	var misses []string
+	for _, id := range ids {
+		if data, ok := cached[id]; ok {
+			// An empty value is a tombstone for an ID Spotify does not know
+			if len(data) == 0 {
+				continue
+			}
+			var item T
+			if err := json.Unmarshal(data, &item); err == nil {
+				items[id] = item
+				continue
+			}
+		}
+		misses = append(misses, id)
+	}
+
+	if len(misses) == 0 {
+		return items, nil
+	}
+	log.Printf("[CACHE] Cache miss for %d %s items", len(misses), cacheType)
+
+	fetched, err := fetch(ctx, misses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s items: %w", cacheType, err)
+	}
+
+	backfill := make(map[string]interface{}, len(fetched))
+	for id, item := range fetched {
+		items[id] = item
+		backfill[id] = item
+	}
+
+	if err := cache.SetSpotifyItems(ctx, cacheType, backfill); err != nil {
+		log.Printf("[CACHE] Warning: Failed to cache %s items: %v", cacheType, err)
+	}
+
+	var notFound []string
+	for _, id := range misses {
+		if _, ok := fetched[id]; !ok {
+			notFound = append(notFound, id)
+		}
+	}
+
+	if err := cache.SetSpotifyItemsNotFound(ctx, cacheType, notFound); err != nil {
+		log.Printf("[CACHE] Warning: Failed to cache missing %s items: %v", cacheType, err)
+	}
+
+	return items, nil
+}
+
+// chunkIDs splits ids into batches of at most size Spotify IDs
+func chunkIDs(ids []string, size int) [][]spotifyapi.ID {
+	var chunks [][]spotifyapi.ID
+	for len(ids) > 0 {
+		n := min(size, len(ids))
+		chunk := make([]spotifyapi.ID, n)
+		for i, id := range ids[:n] {
+			chunk[i] = spotifyapi.ID(id)
+		}
+		chunks = append(chunks, chunk)
+		ids = ids[n:]
+	}
+	return chunks
+}
+
+// spotifyItemFetcher fetches items from the Spotify batch endpoints
+type spotifyItemFetcher struct {
+	services *spotify.Services
+}
+
+func (f *spotifyItemFetcher) FetchTracks(ctx context.Context, ids []string) (map[string]*model.TrackSearchResult, error) {
+	results := make(map[string]*model.TrackSearchResult, len(ids))
+	for _, chunk := range chunkIDs(ids, spotifyTrackBatchSize) {
+		tracks, err := f.services.Track.GetTracks(ctx, chunk)
+		if err != nil {
+			return nil, err
+		}
+		for _, track := range tracks {
+			if track != nil {
+				results[string(track.ID)] = spotifyTrackToSearchResult(track)
+			}
+		}
+	}
+	return results, nil
+}
+
+func (f *spotifyItemFetcher) FetchAlbums(ctx context.Context, ids []string) (map[string]*model.AlbumSearchResult, error) {
+	results := make(map[string]*model.AlbumSearchResult, len(ids))
+	for _, chunk := range chunkIDs(ids, spotifyAlbumBatchSize) {
+		albums, err := f.services.Album.GetAlbums(ctx, chunk)
+		if err != nil {
+			return nil, err
+		}
+		for _, album := range albums {
+			if album != nil {
+				results[string(album.ID)] = spotifyAlbumToSearchResult(album.SimpleAlbum)
+			}
+		}
+	}
+	return results, nil
+}
+
+// FetchArtistGenres returns the genres Spotify lists for each artist
+func (f *spotifyItemFetcher) FetchArtistGenres(ctx context.Context, ids []string) (map[string][]string, error) {
+	genres := make(map[string][]string, len(ids))
+	for _, chunk := range chunkIDs(ids, spotifyArtistBatchSize) {
+		artists, err := f.services.Artist.GetArtists(ctx, chunk...)
+		if err != nil {
+			return nil, err
+		}
+		for _, artist := range artists {
+			if artist != nil {
+				genres[string(artist.ID)] = append([]string{}, artist.Genres...)
+			}
+		}
+	}
+	return genres, nil
+}
+
+func (f *spotifyItemFetcher) FetchArtists(ctx context.Context, ids []string) (map[string]*model.ArtistSearchResult, error) {
+	results := make(map[string]*model.ArtistSearchResult, len(ids))
+	for _, chunk := range chunkIDs(ids, spotifyArtistBatchSize) {
+		artists, err := f.services.Artist.GetArtists(ctx, chunk...)
+		if err != nil {
+			return nil, err
+		}
+		for _, artist := range artists {
+			if artist != nil {
+				results[string(artist.ID)] = spotifyArtistToSearchResult(artist.SimpleArtist)
+			}
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,166 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/daedal00/muse/backend/graph/model"
+	"github.com/daedal00/muse/backend/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryItemCache keeps Spotify items in memory, keyed by type then ID
+type memoryItemCache struct {
+	repository.MusicCacheRepository
+	items map[string]map[string][]byte
+}
+
+func newMemoryItemCache() *memoryItemCache {
+	return &memoryItemCache{items: make(map[string]map[string][]byte)}
+}
+
+func (c *memoryItemCache) put(t *testing.T, itemType, id string, item interface{}) {
+	data, err := json.Marshal(item)
+	require.NoError(t, err)
+	if c.items[itemType] == nil {
+		c.items[itemType] = make(map[string][]byte)
+	}
+	c.items[itemType][id] = data
+}
+
+func (c *memoryItemCache) GetSpotifyItems(ctx context.Context, itemType string, ids []string) (map[string][]byte, error) {
+	result := make(map[string][]byte)
+	for _, id := range ids {
+		if data, ok := c.items[itemType][id]; ok {
+			result[id] = data
+		}
+	}
+	return result, nil
+}
+
+func (c *memoryItemCache) SetSpotifyItems(ctx context.Context, itemType string, items map[string]interface{}) error {
+	for id, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		if c.items[itemType] == nil {
+			c.items[itemType] = make(map[string][]byte)
+		}
+		c.items[itemType][id] = data
+	}
+	return nil
+}
+
+func (c *memoryItemCache) SetSpotifyItemsNotFound(ctx context.Context, itemType string, ids []string) error {
+	for _, id := range ids {
+		if c.items[itemType] == nil {
+			c.items[itemType] = make(map[string][]byte)
+		}
+		c.items[itemType][id] = []byte{}
+	}
+	return nil
+}
+
+// fakeItemFetcher serves a fixed catalogue and records what was requested
+type fakeItemFetcher struct {
+	tracks    map[string]*model.TrackSearchResult
+	albums    map[string]*model.AlbumSearchResult
+	artists   map[string]*model.ArtistSearchResult
+	requested map[model.ItemType][]string
+}
+
+func pick[T any](catalogue map[string]T, ids []string) map[string]T {
+	result := make(map[string]T)
+	for _, id := range ids {
+		if item, ok := catalogue[id]; ok {
+			result[id] = item
+		}
+	}
+	return result
+}
+
+func (f *fakeItemFetcher) FetchTracks(ctx context.Context, ids []string) (map[string]*model.TrackSearchResult, error) {
+	f.requested[model.ItemTypeTrack] = append(f.requested[model.ItemTypeTrack], ids...)
+	return pick(f.tracks, ids), nil
+}
+
+func (f *fakeItemFetcher) FetchAlbums(ctx context.Context, ids []string) (map[string]*model.AlbumSearchResult, error) {
+	f.requested[model.ItemTypeAlbum] = append(f.requested[model.ItemTypeAlbum], ids...)
+	return pick(f.albums, ids), nil
+}
+
+func (f *fakeItemFetcher) FetchArtists(ctx context.Context, ids []string) (map[string]*model.ArtistSearchResult, error) {
+	f.requested[model.ItemTypeArtist] = append(f.requested[model.ItemTypeArtist], ids...)
+	return pick(f.artists, ids), nil
+}
+
+func TestResolveItems_MixedTypesPartiallyWarmCache(t *testing.T) {
+	artist := &model.ArtistSearchResult{ID: "artist-1", Name: "Radiohead", ExternalSource: model.ExternalSourceSpotify}
+	album := &model.AlbumSearchResult{ID: "album-1", Title: "OK Computer", Artist: []*model.ArtistSearchResult{artist}, ExternalSource: model.ExternalSourceSpotify}
+	warmTrack := &model.TrackSearchResult{ID: "track-1", Title: "Airbag", Artists: []*model.ArtistSearchResult{artist}, ExternalSource: model.ExternalSourceSpotify}
+	coldTrack := &model.TrackSearchResult{ID: "track-2", Title: "Paranoid Android", Artists: []*model.ArtistSearchResult{artist}, ExternalSource: model.ExternalSourceSpotify}
+
+	cache := newMemoryItemCache()
+	cache.put(t, "track", warmTrack.ID, warmTrack)
+	cache.put(t, "artist", artist.ID, artist)
+
+	fetcher := &fakeItemFetcher{
+		tracks:    map[string]*model.TrackSearchResult{warmTrack.ID: warmTrack, coldTrack.ID: coldTrack},
+		albums:    map[string]*model.AlbumSearchResult{album.ID: album},
+		artists:   map[string]*model.ArtistSearchResult{artist.ID: artist},
+		requested: make(map[model.ItemType][]string),
+	}
+
+	resolver := &Resolver{itemService: newItemService(cache, fetcher)}
+
+	refs := []*model.ItemRefInput{
+		{Type: model.ItemTypeTrack, ID: coldTrack.ID},
+		{Type: model.ItemTypeArtist, ID: artist.ID},
+		{Type: model.ItemTypeAlbum, ID: album.ID},
+		{Type: model.ItemTypeTrack, ID: "unknown"},
+		{Type: model.ItemTypeTrack, ID: warmTrack.ID},
+		{Type: model.ItemTypeTrack, ID: coldTrack.ID},
+	}
+
+	items, err := resolver.Query().ResolveItems(context.Background(), refs)
+	require.NoError(t, err)
+
+	// Results follow input order, keep duplicates and drop unknown refs
+	require.Len(t, items, 5)
+	assert.Equal(t, coldTrack, items[0])
+	assert.Equal(t, artist, items[1])
+	assert.Equal(t, album, items[2])
+	assert.Equal(t, warmTrack, items[3])
+	assert.Equal(t, coldTrack, items[4])
+
+	// Only cache misses reach Spotify, once each
+	assert.ElementsMatch(t, []string{coldTrack.ID, "unknown"}, fetcher.requested[model.ItemTypeTrack])
+	assert.Equal(t, []string{album.ID}, fetcher.requested[model.ItemTypeAlbum])
+	assert.Empty(t, fetcher.requested[model.ItemTypeArtist])
+
+	// Fetched items are backfilled into the cache
+	assert.Contains(t, cache.items["track"], coldTrack.ID)
+	assert.Contains(t, cache.items["album"], album.ID)
+
+	// The unknown ref is remembered as missing and not fetched again
+	require.Contains(t, cache.items["track"], "unknown")
+	assert.Empty(t, cache.items["track"]["unknown"])
+
+	fetcher.requested = make(map[model.ItemType][]string)
+	items, err = resolver.Query().ResolveItems(context.Background(), refs)
+	require.NoError(t, err)
+	assert.Len(t, items, 5)
+	assert.Empty(t, fetcher.requested)
+}
+
+func TestResolveItems_WithoutSpotify(t *testing.T) {
+	resolver := &Resolver{}
+
+	_, err := resolver.Query().ResolveItems(context.Background(), []*model.ItemRefInput{
+		{Type: model.ItemTypeAlbum, ID: "album-1"},
+	})
+	assert.Error(t, err)
+}
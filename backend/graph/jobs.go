@@ -0,0 +1,62 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/daedal00/muse/backend/internal/jobs"
+	"github.com/daedal00/muse/backend/internal/models"
+	"github.com/daedal00/muse/backend/internal/repository"
+	redisrepo "github.com/daedal00/muse/backend/internal/repository/redis"
+)
+
+const (
+	// popularAlbumsWindow is how far back reviews count towards popularity
+	popularAlbumsWindow = 7 * 24 * time.Hour
+	popularAlbumsLimit  = 20
+)
+
+// BackgroundJobs returns the scheduler for periodic maintenance, with each
+// job locked in Redis so only one server instance runs it at a time
+func (r *Resolver) BackgroundJobs() *jobs.Scheduler {
+	scheduler := jobs.NewScheduler(redisrepo.NewRedisLock(r.redis))
+	scheduler.Register(jobs.Job{
+		Name:     "session_cleanup",
+		Interval: r.config.SessionCleanupInterval,
+		Run:      r.repos.Session.DeleteExpired,
+	})
+	scheduler.Register(jobs.Job{
+		Name:     "popular_albums",
+		Interval: r.config.CacheWarmInterval,
+		Run:      r.warmPopularAlbums,
+	})
+	return scheduler
+}
+
+// warmPopularAlbums caches the albums reviewed most over the past week
+func (r *Resolver) warmPopularAlbums(ctx context.Context) error {
+	aggregates, err := r.repos.Review.GetMostReviewed(ctx, "album", time.Now().Add(-popularAlbumsWindow), popularAlbumsLimit)
+	if err != nil {
+		return fmt.Errorf("failed to find popular albums: %w", err)
+	}
+
+	albums := make([]*models.Album, 0, len(aggregates))
+	for _, aggregate := range aggregates {
+		album, err := r.repos.Album.GetBySpotifyID(ctx, aggregate.SpotifyID)
+		if errors.Is(err, repository.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load popular album: %w", err)
+		}
+		albums = append(albums, album)
+	}
+
+	if err := r.repos.MusicCache.SetPopularAlbums(ctx, albums); err != nil {
+		return fmt.Errorf("failed to cache popular albums: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package loaders
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned by Load when the fetch function did not return a
+// value for the requested key
+var ErrNotFound = errors.New("not found")
+
+// FetchFunc loads values for a batch of keys. Keys without a value are simply
+// left out of the returned map.
+type FetchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// Loader collects the keys requested within a short window and resolves them
+// with a single call to its fetch function, caching the results. A Loader is
+// meant to live for a single request.
+type Loader[K comparable, V any] struct {
+	fetch    FetchFunc[K, V]
+	wait     time.Duration
+	maxBatch int
+
+	mu    sync.Mutex
+	cache map[K]V
+	batch *loaderBatch[K, V]
+}
+
+type loaderBatch[K comparable, V any] struct {
+	keys    []K
+	data    map[K]V
+	err     error
+	closing bool
+	done    chan struct{}
+}
+
+// NewLoader creates a Loader that waits up to wait for more keys before
+// fetching, or fetches immediately once maxBatch keys are pending
+func NewLoader[K comparable, V any](fetch FetchFunc[K, V], wait time.Duration, maxBatch int) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:    fetch,
+		wait:     wait,
+		maxBatch: maxBatch,
+		cache:    make(map[K]V),
+	}
+}
+
+// Load returns the value for key, batching the lookup with any other keys
+// requested concurrently
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	if value, ok := l.cache[key]; ok {
+		l.mu.Unlock()
+		return value, nil
+	}
+
+	if l.batch == nil {
+		l.batch = &loaderBatch[K, V]{done: make(chan struct{})}
+	}
+	batch := l.batch
+	batch.add(ctx, l, key)
+	l.mu.Unlock()
+
+	<-batch.done
+
+	var zero V
+	if batch.err != nil {
+		return zero, batch.err
+	}
+
+	value, ok := batch.data[key]
+	if !ok {
+		return zero, ErrNotFound
+	}
+
+	return value, nil
+}
+
+// add queues key on the batch; the caller must hold the loader's lock
+func (b *loaderBatch[K, V]) add(ctx context.Context, l *Loader[K, V], key K) {
+	for _, existing := range b.keys {
+		if existing == key {
+			return
+		}
+	}
+
+	b.keys = append(b.keys, key)
+	if len(b.keys) == 1 {
+		go b.startTimer(ctx, l)
+	}
+
+	if l.maxBatch > 0 && len(b.keys) >= l.maxBatch {
+		b.closing = true
+		l.batch = nil
+		go b.end(ctx, l)
+	}
+}
+
+func (b *loaderBatch[K, V]) startTimer(ctx context.Context, l *Loader[K, V]) {
+	time.Sleep(l.wait)
+
+	l.mu.Lock()
+	if b.closing {
+		l.mu.Unlock()
+		return
+	}
+	b.closing = true
+	l.batch = nil
+	l.mu.Unlock()
+
+	b.end(ctx, l)
+}
+
+func (b *loaderBatch[K, V]) end(ctx context.Context, l *Loader[K, V]) {
+	b.data, b.err = l.fetch(ctx, b.keys)
+
+	if b.err == nil {
+		l.mu.Lock()
+		for key, value := range b.data {
+			l.cache[key] = value
+		}
+		l.mu.Unlock()
+	}
+
+	close(b.done)
+}
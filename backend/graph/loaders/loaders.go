@@ -0,0 +1,53 @@
+package loaders
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/daedal00/muse/backend/internal/models"
+	"github.com/daedal00/muse/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+type ctxKey string
+
+const loadersKey ctxKey = "dataloaders"
+
+const (
+	batchWait    = 2 * time.Millisecond
+	maxBatchSize = 100
+)
+
+// Loaders holds the request-scoped dataloaders used by field resolvers
+type Loaders struct {
+	UserByID     *Loader[uuid.UUID, *models.User]
+	PlaylistByID *Loader[uuid.UUID, *models.Playlist]
+}
+
+// NewLoaders creates a fresh set of loaders backed by the repositories
+func NewLoaders(repos *repository.Repositories) *Loaders {
+	return &Loaders{
+		UserByID: NewLoader(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.User, error) {
+			return repos.User.GetByIDs(ctx, ids)
+		}, batchWait, maxBatchSize),
+		PlaylistByID: NewLoader(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.Playlist, error) {
+			return repos.Playlist.GetByIDs(ctx, ids)
+		}, batchWait, maxBatchSize),
+	}
+}
+
+// Middleware installs a new set of loaders into each request's context
+func Middleware(repos *repository.Repositories, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), loadersKey, NewLoaders(repos))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// For returns the loaders for the current request, or nil if the request did
+// not pass through Middleware
+func For(ctx context.Context) *Loaders {
+	loaders, _ := ctx.Value(loadersKey).(*Loaders)
+	return loaders
+}
@@ -0,0 +1,131 @@
+package loaders
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/daedal00/muse/backend/internal/models"
+	"github.com/daedal00/muse/backend/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingUserRepository serves users from memory and counts batch lookups
+type countingUserRepository struct {
+	repository.UserRepository
+	users map[uuid.UUID]*models.User
+	calls atomic.Int32
+}
+
+func (r *countingUserRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.User, error) {
+	r.calls.Add(1)
+
+	result := make(map[uuid.UUID]*models.User)
+	for _, id := range ids {
+		if user, ok := r.users[id]; ok {
+			result[id] = user
+		}
+	}
+	return result, nil
+}
+
+func newTestLoaders(users ...*models.User) (*Loaders, *countingUserRepository) {
+	repo := &countingUserRepository{users: make(map[uuid.UUID]*models.User)}
+	for _, user := range users {
+		repo.users[user.ID] = user
+	}
+	return NewLoaders(&repository.Repositories{User: repo}), repo
+}
+
+func TestUserByID_SameAuthorBatchesIntoOneCall(t *testing.T) {
+	author := &models.User{ID: uuid.New(), Name: "Author"}
+	l, repo := newTestLoaders(author)
+	ctx := context.Background()
+
+	// Resolving the author of 20 reviews concurrently, as gqlgen does for
+	// field resolvers on a list, must hit the repository exactly once
+	var wg sync.WaitGroup
+	results := make([]*models.User, 20)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			user, err := l.UserByID.Load(ctx, author.ID)
+			assert.NoError(t, err)
+			results[i] = user
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), repo.calls.Load())
+	for _, user := range results {
+		assert.Equal(t, author, user)
+	}
+
+	// Later loads are served from the request cache
+	_, err := l.UserByID.Load(ctx, author.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), repo.calls.Load())
+}
+
+func TestUserByID_DistinctKeysShareBatch(t *testing.T) {
+	first := &models.User{ID: uuid.New()}
+	second := &models.User{ID: uuid.New()}
+	l, repo := newTestLoaders(first, second)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for _, id := range []uuid.UUID{first.ID, second.ID} {
+		wg.Add(1)
+		go func(id uuid.UUID) {
+			defer wg.Done()
+			user, err := l.UserByID.Load(ctx, id)
+			assert.NoError(t, err)
+			assert.Equal(t, id, user.ID)
+		}(id)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), repo.calls.Load())
+}
+
+func TestUserByID_MissingKey(t *testing.T) {
+	l, _ := newTestLoaders()
+
+	_, err := l.UserByID.Load(context.Background(), uuid.New())
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestLoader_MaxBatchSplitsBatches(t *testing.T) {
+	var calls atomic.Int32
+	loader := NewLoader(func(ctx context.Context, keys []int) (map[int]int, error) {
+		calls.Add(1)
+		result := make(map[int]int, len(keys))
+		for _, key := range keys {
+			result[key] = key * 2
+		}
+		return result, nil
+	}, batchWait, 2)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			value, err := loader.Load(context.Background(), i)
+			assert.NoError(t, err)
+			assert.Equal(t, i*2, value)
+		}(i)
+	}
+	wg.Wait()
+
+	assert.GreaterOrEqual(t, calls.Load(), int32(2))
+}
+
+func TestFor_WithoutMiddleware(t *testing.T) {
+	assert.Nil(t, For(context.Background()))
+}
@@ -0,0 +1,25 @@
+package model
+
+// Review is bound by hand rather than generated so it can carry the author's
+// ID; the user field itself is resolved through the user dataloader
+type Review struct {
+	ID         string  `json:"id"`
+	UserID     string  `json:"-"`
+	Album      *Album  `json:"album"`
+	Rating     int32   `json:"rating"`
+	ReviewText *string `json:"reviewText,omitempty"`
+	HasSpoiler bool    `json:"hasSpoiler"`
+	CreatedAt  string  `json:"createdAt"`
+}
+
+// Playlist is bound by hand rather than generated so it can carry the
+// creator's ID; the creator field is resolved through the user dataloader
+type Playlist struct {
+	ID          string           `json:"id"`
+	Title       string           `json:"title"`
+	Description *string          `json:"description,omitempty"`
+	CoverImage  *string          `json:"coverImage,omitempty"`
+	Tracks      *TrackConnection `json:"tracks"`
+	CreatorID   string           `json:"-"`
+	CreatedAt   string           `json:"createdAt"`
+}
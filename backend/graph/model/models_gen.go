@@ -9,6 +9,10 @@ import (
 	"strconv"
 )
 
+type ResolvedItem interface {
+	IsResolvedItem()
+}
+
 type Album struct {
 	ID          string            `json:"id"`
 	SpotifyID   *string           `json:"spotifyID,omitempty"`
@@ -31,6 +35,15 @@ type AlbumEdge struct {
 	Node   *Album `json:"node"`
 }
 
+type AlbumPage struct {
+	Album         *Album    `json:"album"`
+	Tracks        []*Track  `json:"tracks"`
+	Reviews       []*Review `json:"reviews"`
+	ReviewCount   int32     `json:"reviewCount"`
+	AverageRating float64   `json:"averageRating"`
+	ViewerReview  *Review   `json:"viewerReview,omitempty"`
+}
+
 type AlbumSearchInput struct {
 	Query  string          `json:"query"`
 	Limit  *int32          `json:"limit,omitempty"`
@@ -47,6 +60,8 @@ type AlbumSearchResult struct {
 	ExternalSource ExternalSource        `json:"externalSource"`
 }
 
+func (AlbumSearchResult) IsResolvedItem() {}
+
 type Artist struct {
 	ID        string           `json:"id"`
 	SpotifyID *string          `json:"spotifyID,omitempty"`
@@ -67,6 +82,13 @@ type ArtistSearchResult struct {
 	ExternalSource ExternalSource `json:"externalSource"`
 }
 
+func (ArtistSearchResult) IsResolvedItem() {}
+
+type AuthPayload struct {
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken"`
+}
+
 type CreatePlaylistInput struct {
 	Title       string  `json:"title"`
 	Description *string `json:"description,omitempty"`
@@ -77,6 +99,12 @@ type CreateReviewInput struct {
 	AlbumID    string  `json:"albumId"`
 	Rating     int32   `json:"rating"`
 	ReviewText *string `json:"reviewText,omitempty"`
+	HasSpoiler *bool   `json:"hasSpoiler,omitempty"`
+}
+
+type ItemRefInput struct {
+	Type ItemType `json:"type"`
+	ID   string   `json:"id"`
 }
 
 type Mutation struct {
@@ -87,14 +115,13 @@ type PageInfo struct {
 	HasNextPage bool    `json:"hasNextPage"`
 }
 
-type Playlist struct {
-	ID          string           `json:"id"`
-	Title       string           `json:"title"`
-	Description *string          `json:"description,omitempty"`
-	CoverImage  *string          `json:"coverImage,omitempty"`
-	Tracks      *TrackConnection `json:"tracks"`
-	Creator     *User            `json:"creator"`
-	CreatedAt   string           `json:"createdAt"`
+type PlaylistAudioStats struct {
+	TrackCount   int32   `json:"trackCount"`
+	Energy       float64 `json:"energy"`
+	Danceability float64 `json:"danceability"`
+	Valence      float64 `json:"valence"`
+	Tempo        float64 `json:"tempo"`
+	ComputedAt   string  `json:"computedAt"`
 }
 
 type PlaylistConnection struct {
@@ -111,15 +138,6 @@ type PlaylistEdge struct {
 type Query struct {
 }
 
-type Review struct {
-	ID         string  `json:"id"`
-	User       *User   `json:"user"`
-	Album      *Album  `json:"album"`
-	Rating     int32   `json:"rating"`
-	ReviewText *string `json:"reviewText,omitempty"`
-	CreatedAt  string  `json:"createdAt"`
-}
-
 type ReviewConnection struct {
 	TotalCount int32         `json:"totalCount"`
 	Edges      []*ReviewEdge `json:"edges"`
@@ -164,6 +182,8 @@ type TrackSearchResult struct {
 	ExternalSource ExternalSource        `json:"externalSource"`
 }
 
+func (TrackSearchResult) IsResolvedItem() {}
+
 type User struct {
 	ID        string              `json:"id"`
 	Name      string              `json:"name"`
@@ -228,3 +248,60 @@ func (e ExternalSource) MarshalJSON() ([]byte, error) {
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
+
+type ItemType string
+
+const (
+	ItemTypeTrack  ItemType = "TRACK"
+	ItemTypeAlbum  ItemType = "ALBUM"
+	ItemTypeArtist ItemType = "ARTIST"
+)
+
+var AllItemType = []ItemType{
+	ItemTypeTrack,
+	ItemTypeAlbum,
+	ItemTypeArtist,
+}
+
+func (e ItemType) IsValid() bool {
+	switch e {
+	case ItemTypeTrack, ItemTypeAlbum, ItemTypeArtist:
+		return true
+	}
+	return false
+}
+
+func (e ItemType) String() string {
+	return string(e)
+}
+
+func (e *ItemType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ItemType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ItemType", str)
+	}
+	return nil
+}
+
+func (e ItemType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ItemType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ItemType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
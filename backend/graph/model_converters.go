@@ -6,6 +6,8 @@ import (
 
 	"github.com/daedal00/muse/backend/graph/model"
 	"github.com/daedal00/muse/backend/internal/models"
+	"github.com/daedal00/muse/backend/internal/spotify"
+	spotifyapi "github.com/zmb3/spotify/v2"
 )
 
 // Helper functions to convert between database models and GraphQL models
@@ -107,14 +109,32 @@ func dbReviewToGraphQL(dbReview *models.Review) *model.Review {
 
 	return &model.Review{
 		ID:         dbReview.ID.String(),
-		User:       dbUserToGraphQL(dbReview.User),
+		UserID:     dbReview.UserID.String(),
 		Album:      dbAlbumToGraphQL(dbReview.Album),
 		Rating:     safeIntToInt32(dbReview.Rating),
 		ReviewText: dbReview.ReviewText,
+		HasSpoiler: dbReview.HasSpoiler,
 		CreatedAt:  dbReview.CreatedAt.Format(time.RFC3339),
 	}
 }
 
+// listedReviewToGraphQL converts a review shown in a listing, leaving out the
+// text of reviews flagged as spoilers unless includeSpoilers is set
+func listedReviewToGraphQL(dbReview *models.Review, includeSpoilers bool) *model.Review {
+	return redactSpoiler(dbReviewToGraphQL(dbReview), includeSpoilers)
+}
+
+// redactSpoiler returns review with its text left out if it is flagged as a
+// spoiler and includeSpoilers is not set. The review itself is not modified.
+func redactSpoiler(review *model.Review, includeSpoilers bool) *model.Review {
+	if review == nil || !review.HasSpoiler || includeSpoilers {
+		return review
+	}
+	redacted := *review
+	redacted.ReviewText = nil
+	return &redacted
+}
+
 func dbPlaylistToGraphQL(dbPlaylist *models.Playlist) *model.Playlist {
 	if dbPlaylist == nil {
 		return nil
@@ -125,7 +145,120 @@ func dbPlaylistToGraphQL(dbPlaylist *models.Playlist) *model.Playlist {
 		Title:       dbPlaylist.Title,
 		Description: dbPlaylist.Description,
 		CoverImage:  dbPlaylist.CoverImage,
-		Creator:     dbUserToGraphQL(dbPlaylist.Creator),
+		CreatorID:   dbPlaylist.CreatorID.String(),
 		CreatedAt:   dbPlaylist.CreatedAt.Format(time.RFC3339),
 	}
 }
+
+// albumPageToGraphQL converts the assembled album page, redacting spoilers in
+// its review listing unless includeSpoilers is set. The viewer's own review is
+// always shown in full.
+func albumPageToGraphQL(page *models.AlbumPageData, includeSpoilers bool) *model.AlbumPage {
+	album := dbAlbumToGraphQL(page.Album)
+
+	tracks := make([]*model.Track, len(page.Tracks))
+	for i, dbTrack := range page.Tracks {
+		tracks[i] = dbTrackToGraphQL(dbTrack)
+		tracks[i].Album = album
+	}
+
+	reviews := make([]*model.Review, len(page.Reviews.Reviews))
+	for i, dbReview := range page.Reviews.Reviews {
+		reviews[i] = listedReviewToGraphQL(dbReview, includeSpoilers)
+	}
+
+	return &model.AlbumPage{
+		Album:         album,
+		Tracks:        tracks,
+		Reviews:       reviews,
+		ReviewCount:   safeIntToInt32(page.Reviews.Count),
+		AverageRating: page.Reviews.AverageRating,
+		ViewerReview:  dbReviewToGraphQL(page.ViewerReview),
+	}
+}
+
+func playlistAudioStatsToGraphQL(stats *models.PlaylistAudioStats) *model.PlaylistAudioStats {
+	if stats == nil {
+		return nil
+	}
+
+	return &model.PlaylistAudioStats{
+		TrackCount:   safeIntToInt32(stats.TrackCount),
+		Energy:       stats.Energy,
+		Danceability: stats.Danceability,
+		Valence:      stats.Valence,
+		Tempo:        stats.Tempo,
+		ComputedAt:   stats.ComputedAt.Format(time.RFC3339),
+	}
+}
+
+func spotifyArtistToSearchResult(artist spotifyapi.SimpleArtist) *model.ArtistSearchResult {
+	return cachedArtistToSearchResult(spotify.ToModelArtist(artist))
+}
+
+func spotifyAlbumToSearchResult(album spotifyapi.SimpleAlbum) *model.AlbumSearchResult {
+	return cachedAlbumToSearchResult(spotify.ToModelAlbum(album))
+}
+
+// Spotify search results are cached as the plain models in internal/models,
+// converted with the spotify package mappers
+
+func cachedArtistToSearchResult(artist models.SpotifyArtist) *model.ArtistSearchResult {
+	return &model.ArtistSearchResult{
+		ID:             artist.ID,
+		Name:           artist.Name,
+		ExternalSource: model.ExternalSourceSpotify,
+	}
+}
+
+func cachedAlbumToSearchResult(album models.SpotifyAlbum) *model.AlbumSearchResult {
+	artists := make([]*model.ArtistSearchResult, 0, len(album.Artists))
+	for _, artist := range album.Artists {
+		artists = append(artists, cachedArtistToSearchResult(artist))
+	}
+
+	var releaseDate *string
+	if album.ReleaseDate != "" {
+		releaseDate = &album.ReleaseDate
+	}
+
+	var coverImage *string
+	if album.ImageURL != "" {
+		coverImage = &album.ImageURL
+	}
+
+	return &model.AlbumSearchResult{
+		ID:             album.ID,
+		Title:          album.Name,
+		Artist:         artists,
+		ReleaseDate:    releaseDate,
+		CoverImage:     coverImage,
+		ExternalSource: model.ExternalSourceSpotify,
+	}
+}
+
+func spotifyTrackToSearchResult(track *spotifyapi.FullTrack) *model.TrackSearchResult {
+	artists := make([]*model.ArtistSearchResult, 0, len(track.Artists))
+	for _, artist := range track.Artists {
+		artists = append(artists, spotifyArtistToSearchResult(artist))
+	}
+
+	// Spotify reports milliseconds; the schema uses seconds
+	duration := safeIntToInt32(int(track.Duration) / 1000)
+	trackNumber := safeIntToInt32(int(track.TrackNumber))
+
+	var album *model.AlbumSearchResult
+	if track.Album.ID != "" {
+		album = spotifyAlbumToSearchResult(track.Album)
+	}
+
+	return &model.TrackSearchResult{
+		ID:             string(track.ID),
+		Title:          track.Name,
+		Duration:       &duration,
+		TrackNumber:    &trackNumber,
+		Album:          album,
+		Artists:        artists,
+		ExternalSource: model.ExternalSourceSpotify,
+	}
+}
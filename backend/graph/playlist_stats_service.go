@@ -0,0 +1,85 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/daedal00/muse/backend/internal/models"
+	"github.com/daedal00/muse/backend/internal/repository"
+	"github.com/google/uuid"
+	spotifyapi "github.com/zmb3/spotify/v2"
+)
+
+// spotifyAudioFeaturesBatchSize is the most IDs the audio features endpoint accepts
+const spotifyAudioFeaturesBatchSize = 100
+
+// audioFeaturesFetcher loads audio features; TrackService implements it
+type audioFeaturesFetcher interface {
+	GetAudioFeatures(ctx context.Context, trackIDs ...spotifyapi.ID) ([]*spotifyapi.AudioFeatures, error)
+}
+
+// playlistStatsService averages the audio features of a playlist's tracks,
+// caching the result until it expires
+type playlistStatsService struct {
+	playlists repository.PlaylistRepository
+	cache     repository.MusicCacheRepository
+	features  audioFeaturesFetcher
+}
+
+func newPlaylistStatsService(playlists repository.PlaylistRepository, cache repository.MusicCacheRepository, features audioFeaturesFetcher) *playlistStatsService {
+	return &playlistStatsService{playlists: playlists, cache: cache, features: features}
+}
+
+// Compute returns the playlist's average energy, danceability, valence and
+// tempo. Tracks Spotify has no features for are left out of the averages; a
+// playlist with none averages to zero. Cache failures are logged and treated
+// as misses.
+func (s *playlistStatsService) Compute(ctx context.Context, playlistID uuid.UUID) (*models.PlaylistAudioStats, error) {
+	cached, err := s.cache.GetPlaylistAudioStats(ctx, playlistID)
+	if err != nil {
+		log.Printf("[CACHE] Warning: Failed to read cached stats for playlist %s: %v", playlistID, err)
+	}
+	if cached != nil {
+		return cached, nil
+	}
+
+	spotifyIDs, err := s.playlists.GetTrackSpotifyIDs(ctx, playlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &models.PlaylistAudioStats{PlaylistID: playlistID}
+	for _, chunk := range chunkIDs(spotifyIDs, spotifyAudioFeaturesBatchSize) {
+		features, err := s.features.GetAudioFeatures(ctx, chunk...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get audio features: %w", err)
+		}
+		for _, f := range features {
+			if f == nil {
+				continue
+			}
+			stats.TrackCount++
+			stats.Energy += float64(f.Energy)
+			stats.Danceability += float64(f.Danceability)
+			stats.Valence += float64(f.Valence)
+			stats.Tempo += float64(f.Tempo)
+		}
+	}
+
+	if stats.TrackCount > 0 {
+		n := float64(stats.TrackCount)
+		stats.Energy /= n
+		stats.Danceability /= n
+		stats.Valence /= n
+		stats.Tempo /= n
+	}
+	stats.ComputedAt = time.Now()
+
+	if err := s.cache.SetPlaylistAudioStats(ctx, stats); err != nil {
+		log.Printf("[CACHE] Warning: Failed to cache stats for playlist %s: %v", playlistID, err)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,143 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/daedal00/muse/backend/graph/model"
+	"github.com/daedal00/muse/backend/internal/models"
+	"github.com/daedal00/muse/backend/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	spotifyapi "github.com/zmb3/spotify/v2"
+)
+
+// memoryPlaylistTracks serves fixed Spotify track IDs per playlist
+type memoryPlaylistTracks struct {
+	repository.PlaylistRepository
+	spotifyIDs map[uuid.UUID][]string
+}
+
+func (p *memoryPlaylistTracks) GetTrackSpotifyIDs(ctx context.Context, playlistID uuid.UUID) ([]string, error) {
+	return p.spotifyIDs[playlistID], nil
+}
+
+// memoryStatsCache keeps computed playlist stats in memory
+type memoryStatsCache struct {
+	repository.MusicCacheRepository
+	stats map[uuid.UUID]*models.PlaylistAudioStats
+}
+
+func (c *memoryStatsCache) SetPlaylistAudioStats(ctx context.Context, stats *models.PlaylistAudioStats) error {
+	c.stats[stats.PlaylistID] = stats
+	return nil
+}
+
+func (c *memoryStatsCache) GetPlaylistAudioStats(ctx context.Context, playlistID uuid.UUID) (*models.PlaylistAudioStats, error) {
+	return c.stats[playlistID], nil
+}
+
+// fakeAudioFeatures serves fixed features and records each batch requested
+type fakeAudioFeatures struct {
+	features map[spotifyapi.ID]*spotifyapi.AudioFeatures
+	batches  [][]spotifyapi.ID
+}
+
+func (f *fakeAudioFeatures) GetAudioFeatures(ctx context.Context, trackIDs ...spotifyapi.ID) ([]*spotifyapi.AudioFeatures, error) {
+	f.batches = append(f.batches, trackIDs)
+	result := make([]*spotifyapi.AudioFeatures, len(trackIDs))
+	for i, id := range trackIDs {
+		result[i] = f.features[id]
+	}
+	return result, nil
+}
+
+func newTestPlaylistStatsService(playlistID uuid.UUID, spotifyIDs []string, features map[spotifyapi.ID]*spotifyapi.AudioFeatures) (*playlistStatsService, *memoryStatsCache, *fakeAudioFeatures) {
+	playlists := &memoryPlaylistTracks{spotifyIDs: map[uuid.UUID][]string{playlistID: spotifyIDs}}
+	cache := &memoryStatsCache{stats: make(map[uuid.UUID]*models.PlaylistAudioStats)}
+	fetcher := &fakeAudioFeatures{features: features}
+	return newPlaylistStatsService(playlists, cache, fetcher), cache, fetcher
+}
+
+func TestPlaylistStatsService_Compute_Averages(t *testing.T) {
+	playlistID := uuid.New()
+	service, cache, fetcher := newTestPlaylistStatsService(playlistID, []string{"t1", "t2", "no-features"}, map[spotifyapi.ID]*spotifyapi.AudioFeatures{
+		"t1": {ID: "t1", Energy: 0.5, Danceability: 0.25, Valence: 1, Tempo: 120},
+		"t2": {ID: "t2", Energy: 1, Danceability: 0.75, Valence: 0, Tempo: 90},
+	})
+	ctx := context.Background()
+
+	stats, err := service.Compute(ctx, playlistID)
+	require.NoError(t, err)
+
+	// The track without features is left out of the averages
+	assert.Equal(t, playlistID, stats.PlaylistID)
+	assert.Equal(t, 2, stats.TrackCount)
+	assert.InDelta(t, 0.75, stats.Energy, 1e-9)
+	assert.InDelta(t, 0.5, stats.Danceability, 1e-9)
+	assert.InDelta(t, 0.5, stats.Valence, 1e-9)
+	assert.InDelta(t, 105, stats.Tempo, 1e-9)
+	assert.False(t, stats.ComputedAt.IsZero())
+
+	assert.Same(t, stats, cache.stats[playlistID])
+
+	// Cached stats are reused without calling Spotify
+	again, err := service.Compute(ctx, playlistID)
+	require.NoError(t, err)
+	assert.Same(t, stats, again)
+	assert.Len(t, fetcher.batches, 1)
+}
+
+func TestPlaylistStatsService_Compute_EmptyPlaylist(t *testing.T) {
+	playlistID := uuid.New()
+	service, cache, fetcher := newTestPlaylistStatsService(playlistID, nil, nil)
+
+	stats, err := service.Compute(context.Background(), playlistID)
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, stats.TrackCount)
+	assert.Zero(t, stats.Energy)
+	assert.Zero(t, stats.Tempo)
+	assert.Empty(t, fetcher.batches, "an empty playlist needs no API call")
+	assert.NotNil(t, cache.stats[playlistID])
+}
+
+func TestPlaylistStatsService_Compute_BatchesLargePlaylists(t *testing.T) {
+	playlistID := uuid.New()
+	ids := make([]string, spotifyAudioFeaturesBatchSize+20)
+	features := make(map[spotifyapi.ID]*spotifyapi.AudioFeatures, len(ids))
+	for i := range ids {
+		ids[i] = fmt.Sprintf("track-%03d", i)
+		features[spotifyapi.ID(ids[i])] = &spotifyapi.AudioFeatures{Energy: 0.5, Tempo: 100}
+	}
+	service, _, fetcher := newTestPlaylistStatsService(playlistID, ids, features)
+
+	stats, err := service.Compute(context.Background(), playlistID)
+	require.NoError(t, err)
+
+	assert.Equal(t, len(ids), stats.TrackCount)
+	assert.InDelta(t, 0.5, stats.Energy, 1e-9)
+	require.Len(t, fetcher.batches, 2)
+	assert.Len(t, fetcher.batches[0], spotifyAudioFeaturesBatchSize)
+	assert.Len(t, fetcher.batches[1], 20)
+}
+
+func TestPlaylistAudioStatsField(t *testing.T) {
+	playlistID := uuid.New()
+	service, _, _ := newTestPlaylistStatsService(playlistID, []string{"t1"}, map[spotifyapi.ID]*spotifyapi.AudioFeatures{
+		"t1": {ID: "t1", Energy: 0.5, Danceability: 0.25, Valence: 1, Tempo: 120},
+	})
+	playlist := &model.Playlist{ID: playlistID.String()}
+
+	stats, err := (&Resolver{playlistStats: service}).Playlist().AudioStats(context.Background(), playlist)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), stats.TrackCount)
+	assert.InDelta(t, 120, stats.Tempo, 1e-9)
+	assert.NotEmpty(t, stats.ComputedAt)
+
+	// Without Spotify the field errors instead of reporting zeroes
+	_, err = (&Resolver{}).Playlist().AudioStats(context.Background(), playlist)
+	assert.Error(t, err)
+}
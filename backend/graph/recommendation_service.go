@@ -0,0 +1,112 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/daedal00/muse/backend/internal/models"
+	"github.com/daedal00/muse/backend/internal/repository"
+	"github.com/daedal00/muse/backend/internal/spotify"
+	"github.com/google/uuid"
+	spotifyapi "github.com/zmb3/spotify/v2"
+)
+
+// Spotify recommendation limits
+const (
+	maxRecommendationSeeds = 5
+	recommendationLimit    = 20
+)
+
+// errNoRecommendationSeeds is returned for users with neither favorite
+// artists nor preferred genres to seed recommendations from
+var errNoRecommendationSeeds = errors.New("no favorite artists or preferred genres to seed recommendations")
+
+// trackRecommender asks Spotify for recommendations; TrackService implements it
+type trackRecommender interface {
+	GetRecommendations(ctx context.Context, seeds spotifyapi.Seeds, trackAttributes *spotifyapi.TrackAttributes, options ...spotifyapi.RequestOption) (*spotifyapi.Recommendations, error)
+}
+
+// recommendationService generates track recommendations seeded from a user's
+// favorite artists and preferred genres, caching them until they expire
+type recommendationService struct {
+	preferences repository.UserPreferencesRepository
+	cache       repository.MusicCacheRepository
+	recommender trackRecommender
+}
+
+func newRecommendationService(preferences repository.UserPreferencesRepository, cache repository.MusicCacheRepository, recommender trackRecommender) *recommendationService {
+	return &recommendationService{preferences: preferences, cache: cache, recommender: recommender}
+}
+
+// Generate returns the user's recommendations, reusing cached ones while they
+// are fresh. Cache failures are logged and treated as misses.
+func (s *recommendationService) Generate(ctx context.Context, userID uuid.UUID) (*models.CachedRecommendations, error) {
+	cached, err := s.cache.GetRecommendations(ctx, userID)
+	if err != nil {
+		log.Printf("[CACHE] Warning: Failed to read cached recommendations for user %s: %v", userID, err)
+	}
+	if cached != nil {
+		return cached, nil
+	}
+
+	prefs, err := s.preferences.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	seeds := recommendationSeeds(prefs)
+	if len(seeds.Artists) == 0 && len(seeds.Genres) == 0 {
+		return nil, errNoRecommendationSeeds
+	}
+
+	result, err := s.recommender.GetRecommendations(ctx, seeds, nil, spotifyapi.Limit(recommendationLimit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recommendations: %w", err)
+	}
+
+	tracks := make([]models.SpotifyTrack, 0, len(result.Tracks))
+	for _, track := range result.Tracks {
+		tracks = append(tracks, *spotify.ToModelTrack(&spotifyapi.FullTrack{SimpleTrack: track, Album: track.Album}))
+	}
+
+	seedArtists := make([]string, len(seeds.Artists))
+	for i, id := range seeds.Artists {
+		seedArtists[i] = string(id)
+	}
+
+	recommendations := &models.CachedRecommendations{
+		UserID:      userID,
+		Tracks:      tracks,
+		SeedArtists: seedArtists,
+		SeedGenres:  seeds.Genres,
+		GeneratedAt: time.Now(),
+	}
+
+	if err := s.cache.SetRecommendations(ctx, recommendations); err != nil {
+		log.Printf("[CACHE] Warning: Failed to cache recommendations for user %s: %v", userID, err)
+	}
+
+	return recommendations, nil
+}
+
+// recommendationSeeds picks up to Spotify's five seeds, favorite artists
+// first and preferred genres filling the rest
+func recommendationSeeds(prefs *models.UserPreferences) spotifyapi.Seeds {
+	var seeds spotifyapi.Seeds
+	for _, id := range prefs.FavoriteArtistIDs {
+		if len(seeds.Artists) == maxRecommendationSeeds {
+			break
+		}
+		seeds.Artists = append(seeds.Artists, spotifyapi.ID(id))
+	}
+	for _, genre := range prefs.PreferredGenres {
+		if len(seeds.Artists)+len(seeds.Genres) == maxRecommendationSeeds {
+			break
+		}
+		seeds.Genres = append(seeds.Genres, genre)
+	}
+	return seeds
+}
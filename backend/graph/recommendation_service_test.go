@@ -0,0 +1,136 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/daedal00/muse/backend/internal/models"
+	"github.com/daedal00/muse/backend/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	spotifyapi "github.com/zmb3/spotify/v2"
+)
+
+// memoryPreferences serves fixed preferences per user
+type memoryPreferences struct {
+	repository.UserPreferencesRepository
+	prefs map[uuid.UUID]*models.UserPreferences
+}
+
+func (p *memoryPreferences) GetByUserID(ctx context.Context, userID uuid.UUID) (*models.UserPreferences, error) {
+	prefs, ok := p.prefs[userID]
+	if !ok {
+		return nil, fmt.Errorf("user preferences not found")
+	}
+	return prefs, nil
+}
+
+// memoryRecommendationCache keeps generated recommendations in memory
+type memoryRecommendationCache struct {
+	repository.MusicCacheRepository
+	recommendations map[uuid.UUID]*models.CachedRecommendations
+}
+
+func (c *memoryRecommendationCache) SetRecommendations(ctx context.Context, recommendations *models.CachedRecommendations) error {
+	c.recommendations[recommendations.UserID] = recommendations
+	return nil
+}
+
+func (c *memoryRecommendationCache) GetRecommendations(ctx context.Context, userID uuid.UUID) (*models.CachedRecommendations, error) {
+	return c.recommendations[userID], nil
+}
+
+// fakeRecommender returns one track per call and records the seeds it was given
+type fakeRecommender struct {
+	seeds []spotifyapi.Seeds
+}
+
+func (f *fakeRecommender) GetRecommendations(ctx context.Context, seeds spotifyapi.Seeds, trackAttributes *spotifyapi.TrackAttributes, options ...spotifyapi.RequestOption) (*spotifyapi.Recommendations, error) {
+	f.seeds = append(f.seeds, seeds)
+	return &spotifyapi.Recommendations{
+		Tracks: []spotifyapi.SimpleTrack{{
+			ID:       "rec-track",
+			Name:     "Recommended",
+			Artists:  []spotifyapi.SimpleArtist{{ID: "artist-1", Name: "Artist One"}},
+			Album:    spotifyapi.SimpleAlbum{ID: "album-1", Name: "Album One"},
+			Duration: 180000,
+		}},
+	}, nil
+}
+
+func newTestRecommendationService(prefs ...*models.UserPreferences) (*recommendationService, *memoryRecommendationCache, *fakeRecommender) {
+	preferences := &memoryPreferences{prefs: make(map[uuid.UUID]*models.UserPreferences)}
+	for _, p := range prefs {
+		preferences.prefs[p.UserID] = p
+	}
+	cache := &memoryRecommendationCache{recommendations: make(map[uuid.UUID]*models.CachedRecommendations)}
+	recommender := &fakeRecommender{}
+	return newRecommendationService(preferences, cache, recommender), cache, recommender
+}
+
+func TestRecommendationService_Generate_SeedsAndCaches(t *testing.T) {
+	userID := uuid.New()
+	service, cache, recommender := newTestRecommendationService(&models.UserPreferences{
+		UserID:            userID,
+		FavoriteArtistIDs: []string{"a1", "a2", "a3"},
+		PreferredGenres:   []string{"jazz", "soul", "funk"},
+	})
+	ctx := context.Background()
+
+	recommendations, err := service.Generate(ctx, userID)
+	require.NoError(t, err)
+
+	// Artists seed first and genres fill the remaining two of five slots
+	require.Len(t, recommender.seeds, 1)
+	assert.Equal(t, []spotifyapi.ID{"a1", "a2", "a3"}, recommender.seeds[0].Artists)
+	assert.Equal(t, []string{"jazz", "soul"}, recommender.seeds[0].Genres)
+
+	assert.Equal(t, userID, recommendations.UserID)
+	assert.Equal(t, []string{"a1", "a2", "a3"}, recommendations.SeedArtists)
+	assert.Equal(t, []string{"jazz", "soul"}, recommendations.SeedGenres)
+	require.Len(t, recommendations.Tracks, 1)
+	assert.Equal(t, "rec-track", recommendations.Tracks[0].ID)
+	assert.Equal(t, 180000, recommendations.Tracks[0].DurationMs)
+	require.NotNil(t, recommendations.Tracks[0].Album)
+	assert.False(t, recommendations.GeneratedAt.IsZero())
+
+	assert.Same(t, recommendations, cache.recommendations[userID])
+
+	// A fresh cached result is reused without calling Spotify
+	again, err := service.Generate(ctx, userID)
+	require.NoError(t, err)
+	assert.Same(t, recommendations, again)
+	assert.Len(t, recommender.seeds, 1)
+}
+
+func TestRecommendationService_Generate_CapsArtistSeeds(t *testing.T) {
+	userID := uuid.New()
+	service, _, recommender := newTestRecommendationService(&models.UserPreferences{
+		UserID:            userID,
+		FavoriteArtistIDs: []string{"a1", "a2", "a3", "a4", "a5", "a6"},
+		PreferredGenres:   []string{"jazz"},
+	})
+
+	_, err := service.Generate(context.Background(), userID)
+	require.NoError(t, err)
+
+	require.Len(t, recommender.seeds, 1)
+	assert.Len(t, recommender.seeds[0].Artists, maxRecommendationSeeds)
+	assert.Empty(t, recommender.seeds[0].Genres)
+}
+
+func TestRecommendationService_Generate_NoSeeds(t *testing.T) {
+	userID := uuid.New()
+	service, cache, recommender := newTestRecommendationService(&models.UserPreferences{UserID: userID})
+
+	_, err := service.Generate(context.Background(), userID)
+	assert.ErrorIs(t, err, errNoRecommendationSeeds)
+	assert.Empty(t, recommender.seeds)
+	assert.Empty(t, cache.recommendations)
+
+	// Users without preferences cannot be seeded either
+	_, err = service.Generate(context.Background(), uuid.New())
+	assert.Error(t, err)
+}
@@ -0,0 +1,39 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/daedal00/muse/backend/internal/logging"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// internalErrorMessage is all clients see when a resolver panics
+const internalErrorMessage = "internal error"
+
+// RecoverFunc turns a resolver panic into a generic error for the client,
+// logging the panic value and stack trace server-side
+func RecoverFunc(ctx context.Context, err interface{}) error {
+	operation := "unknown"
+	if graphql.HasOperationContext(ctx) {
+		if opCtx := graphql.GetOperationContext(ctx); opCtx.OperationName != "" {
+			operation = opCtx.OperationName
+		}
+	}
+
+	path := ""
+	if fieldCtx := graphql.GetFieldContext(ctx); fieldCtx != nil {
+		path = fieldCtx.Path().String()
+	}
+
+	logging.FromContext(ctx).Error("resolver panic recovered",
+		"operation", operation,
+		"path", path,
+		"panic", fmt.Sprint(err),
+		"stack", string(debug.Stack()),
+	)
+
+	return gqlerror.Errorf("%s", internalErrorMessage)
+}
@@ -0,0 +1,40 @@
+package graph
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/daedal00/muse/backend/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecoverFunc_SanitizesResolverPanics(t *testing.T) {
+	// With no user repository configured the user resolver dereferences a nil interface and panics
+	resolver := &Resolver{repos: &repository.Repositories{}}
+
+	srv := handler.New(NewExecutableSchema(Config{Resolvers: resolver}))
+	srv.AddTransport(transport.POST{})
+	srv.SetRecoverFunc(RecoverFunc)
+
+	query := `{"query":"{ user(id: \"7f3ad1a4-6a4f-4f5e-9d55-8f1a2b3c4d5e\") { id } }"}`
+
+	// Send the same panicking query twice to show the handler keeps serving
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(query))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+
+		require.NotPanics(t, func() { srv.ServeHTTP(rec, req) })
+
+		body := rec.Body.String()
+		assert.Contains(t, body, internalErrorMessage)
+		assert.NotContains(t, body, "runtime error")
+		assert.NotContains(t, body, "nil pointer")
+		assert.NotContains(t, body, "goroutine")
+	}
+}
@@ -4,14 +4,22 @@ package graph
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"net/http"
 
+	"github.com/daedal00/muse/backend/auth"
+	"github.com/daedal00/muse/backend/graph/loaders"
+	"github.com/daedal00/muse/backend/graph/model"
 	"github.com/daedal00/muse/backend/internal/config"
 	"github.com/daedal00/muse/backend/internal/database"
+	"github.com/daedal00/muse/backend/internal/models"
 	"github.com/daedal00/muse/backend/internal/repository"
 	"github.com/daedal00/muse/backend/internal/repository/postgres"
 	redisrepo "github.com/daedal00/muse/backend/internal/repository/redis"
 	"github.com/daedal00/muse/backend/internal/spotify"
+	"github.com/google/uuid"
 )
 
 // This file will not be regenerated automatically.
@@ -21,16 +29,41 @@ import (
 // Resolver is the root resolver struct
 type Resolver struct {
 	repos            *repository.Repositories
+	txManager        repository.TxManager
 	spotifyServices  *spotify.Services
+	itemService      *itemService
+	albumPages       *albumPageService
+	imports          *importService
+	reviewGenres     *reviewGenreService
+	recommendations  *recommendationService
+	playlistStats    *playlistStatsService
 	subscriptionMgr  *SubscriptionManager
 	paginationHelper *PaginationHelper
+	passwordPolicy   auth.PasswordPolicy
 	config           *config.Config
+	redis            *database.RedisClient
+	closers          []resourceCloser
+}
+
+// resourceCloser shuts down one dependency of the resolver at exit
+type resourceCloser struct {
+	name   string
+	stats  func() string // optional pool report logged before closing
+	health func() error  // optional readiness probe
+	close  func() error
 }
 
 // NewResolver creates a new GraphQL resolver with all required dependencies
 func NewResolver(cfg *config.Config) (*Resolver, error) {
 	// Initialize PostgreSQL database
-	postgresDB, err := database.NewPostgresConnection(cfg.DatabaseURL)
+	postgresDB, err := database.NewPostgresConnectionWithOptions(cfg.DatabaseURL, database.PostgresOptions{
+		ReplicaURL:       cfg.DBReadReplicaURL,
+		StatementTimeout: cfg.DBStatementTimeout,
+		MaxConns:         safeIntToInt32(cfg.DBMaxConns),
+		MinConns:         safeIntToInt32(cfg.DBMinConns),
+		MaxConnLifetime:  cfg.DBMaxConnLifetime,
+		MaxConnIdleTime:  cfg.DBMaxConnIdleTime,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -43,16 +76,25 @@ func NewResolver(cfg *config.Config) (*Resolver, error) {
 	}
 	log.Printf("✅ Connected to Redis at %s", cfg.RedisURL)
 
+	return NewResolverWithConnections(cfg, postgresDB, redisClient), nil
+}
+
+// NewResolverWithConnections builds a resolver on already opened database
+// connections, which it takes ownership of
+func NewResolverWithConnections(cfg *config.Config, postgresDB *database.PostgresDB, redisClient *database.RedisClient) *Resolver {
 	// Initialize repositories (using Redis for sessions, PostgreSQL for others)
 	repos := &repository.Repositories{
-		User:       postgres.NewUserRepository(postgresDB),
-		Artist:     postgres.NewArtistRepository(postgresDB),
-		Album:      postgres.NewAlbumRepository(postgresDB),
-		Track:      postgres.NewTrackRepository(postgresDB),
-		Review:     postgres.NewReviewRepository(postgresDB),
-		Playlist:   postgres.NewPlaylistRepository(postgresDB),
-		Session:    redisrepo.NewSessionRepository(redisClient),    // Using Redis for sessions
-		MusicCache: redisrepo.NewMusicCacheRepository(redisClient), // Using Redis for music caching
+		User:            postgres.NewUserRepository(postgresDB),
+		UserPreferences: postgres.NewUserPreferencesRepository(postgresDB),
+		Follow:          postgres.NewFollowRepository(postgresDB),
+		Artist:          postgres.NewArtistRepository(postgresDB),
+		Album:           postgres.NewAlbumRepository(postgresDB),
+		Track:           postgres.NewTrackRepository(postgresDB),
+		Review:          postgres.NewReviewRepository(postgresDB),
+		ReviewVote:      postgres.NewReviewVoteRepository(postgresDB),
+		Playlist:        postgres.NewPlaylistRepository(postgresDB),
+		Session:         redisrepo.NewSessionRepository(redisClient),    // Using Redis for sessions
+		MusicCache:      redisrepo.NewMusicCacheRepository(redisClient), // Using Redis for music caching
 	}
 
 	// Initialize Spotify services (optional)
@@ -64,6 +106,12 @@ func NewResolver(cfg *config.Config) (*Resolver, error) {
 			ClientSecret: cfg.SpotifyClientSecret,
 			RedirectURL:  "http://localhost:8080/callback", // Default redirect for client credentials
 			Scopes:       []string{},                       // No scopes needed for client credentials flow
+			HTTPClient: &http.Client{Transport: spotify.NewTransport(spotify.TransportConfig{
+				MaxIdleConns:        cfg.SpotifyHTTPMaxIdleConns,
+				MaxIdleConnsPerHost: cfg.SpotifyHTTPMaxIdleConnsPerHost,
+				IdleConnTimeout:     cfg.SpotifyHTTPIdleConnTimeout,
+			})},
+			MaxRateLimitRetries: cfg.SpotifyMaxRateLimitRetries,
 		})
 
 		// Get client credentials client for public API access
@@ -77,6 +125,19 @@ func NewResolver(cfg *config.Config) (*Resolver, error) {
 		}
 	}
 
+	var items *itemService
+	var imports *importService
+	var reviewGenres *reviewGenreService
+	var recommendations *recommendationService
+	var playlistStats *playlistStatsService
+	if spotifyServices != nil {
+		items = newItemService(repos.MusicCache, &spotifyItemFetcher{services: spotifyServices})
+		imports = newImportService(repos, &spotifyImportFetcher{services: spotifyServices})
+		reviewGenres = newReviewGenreService(repos, &spotifyItemFetcher{services: spotifyServices})
+		recommendations = newRecommendationService(repos.UserPreferences, repos.MusicCache, spotifyServices.Track)
+		playlistStats = newPlaylistStatsService(repos.Playlist, repos.MusicCache, spotifyServices.Track)
+	}
+
 	// Initialize subscription manager
 	subscriptionMgr := NewSubscriptionManager(redisClient)
 
@@ -85,16 +146,186 @@ func NewResolver(cfg *config.Config) (*Resolver, error) {
 
 	return &Resolver{
 		repos:            repos,
+		txManager:        postgres.NewTxManager(postgresDB, *repos),
 		spotifyServices:  spotifyServices,
+		itemService:      items,
+		albumPages:       newAlbumPageService(repos),
+		imports:          imports,
+		reviewGenres:     reviewGenres,
+		recommendations:  recommendations,
+		playlistStats:    playlistStats,
 		subscriptionMgr:  subscriptionMgr,
 		paginationHelper: paginationHelper,
-		config:           cfg,
-	}, nil
+		passwordPolicy: auth.PasswordPolicy{
+			MinLength:        cfg.PasswordMinLength,
+			RequireMixedCase: cfg.PasswordRequireMixedCase,
+			RequireDigit:     cfg.PasswordRequireDigit,
+		},
+		config:  cfg,
+		redis:   redisClient,
+		closers: newResourceClosers(postgresDB, redisClient),
+	}
 }
 
-// Close properly closes all database connections
+// newResourceClosers lists the resolver's database dependencies for
+// shutdown and readiness checks
+func newResourceClosers(postgresDB *database.PostgresDB, redisClient *database.RedisClient) []resourceCloser {
+	return []resourceCloser{
+		{
+			name: "postgres",
+			stats: func() string {
+				stat := postgresDB.Pool.Stat()
+				return fmt.Sprintf("total=%d acquired=%d idle=%d", stat.TotalConns(), stat.AcquiredConns(), stat.IdleConns())
+			},
+			health: postgresDB.Health,
+			close: func() error {
+				postgresDB.Close()
+				return nil
+			},
+		},
+		{
+			name: "redis",
+			stats: func() string {
+				stat := redisClient.Client.PoolStats()
+				return fmt.Sprintf("total=%d idle=%d stale=%d timeouts=%d", stat.TotalConns, stat.IdleConns, stat.StaleConns, stat.Timeouts)
+			},
+			health: redisClient.Health,
+			close:  redisClient.Close,
+		},
+	}
+}
+
+// Close closes every dependency, logging each one's pool stats first, and
+// returns the close errors joined so a failure is never swallowed
 func (r *Resolver) Close() error {
-	// Note: In a production system, you'd want to track both connections
-	// and close them properly. For now, we'll add this placeholder.
+	var errs []error
+	for _, closer := range r.closers {
+		if closer.stats != nil {
+			log.Printf("[SHUTDOWN] %s pool: %s", closer.name, closer.stats())
+		}
+		if err := closer.close(); err != nil {
+			log.Printf("[SHUTDOWN] Failed to close %s: %v", closer.name, err)
+			errs = append(errs, fmt.Errorf("failed to close %s: %w", closer.name, err))
+			continue
+		}
+		log.Printf("[SHUTDOWN] Closed %s", closer.name)
+	}
+	return errors.Join(errs...)
+}
+
+// CheckHealth probes every dependency that has a readiness check, keyed by
+// name; a nil error means the dependency is reachable
+func (r *Resolver) CheckHealth() map[string]error {
+	results := make(map[string]error, len(r.closers))
+	for _, closer := range r.closers {
+		if closer.health != nil {
+			results[closer.name] = closer.health()
+		}
+	}
+	return results
+}
+
+// RateLimiter returns the Redis client request rate limits are counted in
+func (r *Resolver) RateLimiter() *database.RedisClient {
+	return r.redis
+}
+
+// LoaderMiddleware installs request-scoped dataloaders backed by the resolver's repositories
+func (r *Resolver) LoaderMiddleware(next http.Handler) http.Handler {
+	return loaders.Middleware(r.repos, next)
+}
+
+// ActivityMiddleware records activity for requests authenticated upstream
+// by auth.JWTMiddleware
+func (r *Resolver) ActivityMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if userID, ok := ForContext(req.Context()); ok {
+			r.RecordActivity(req.Context(), userID)
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// RecordActivity marks an authenticated user as active. Failures are logged
+// rather than returned so they never fail the request.
+func (r *Resolver) RecordActivity(ctx context.Context, userID string) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return
+	}
+
+	if err := r.repos.User.TouchLastActive(ctx, id); err != nil {
+		log.Printf("[AUTH] Warning: Failed to record activity for user %s: %v", userID, err)
+	}
+}
+
+// SessionActive is an auth.SessionValidator that marks a token's session as
+// just used, reporting whether it still exists. Only a missing session
+// rejects the token; store failures are logged and let the request through.
+func (r *Resolver) SessionActive(ctx context.Context, sessionID string) bool {
+	err := r.repos.Session.UpdateLastSeen(ctx, sessionID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return false
+	}
+	if err != nil {
+		log.Printf("[AUTH] Warning: Failed to update last seen for session %s: %v", sessionID, err)
+	}
+	return true
+}
+
+// deleteAccount removes a user and all of their data from PostgreSQL, then
+// clears their sessions and cached music data from Redis. The account is gone
+// once the transaction commits, so Redis failures are logged, not returned.
+func (r *Resolver) deleteAccount(ctx context.Context, userID uuid.UUID) error {
+	if err := r.repos.User.DeleteAccount(ctx, userID); err != nil {
+		return fmt.Errorf("failed to delete account: %w", err)
+	}
+
+	if err := r.repos.Session.DeleteByUserID(ctx, userID); err != nil {
+		log.Printf("[AUTH] Warning: Failed to delete sessions for deleted user %s: %v", userID, err)
+	}
+	if err := r.repos.MusicCache.InvalidateUserCache(ctx, userID); err != nil {
+		log.Printf("[CACHE] Warning: Failed to clear cache for deleted user %s: %v", userID, err)
+	}
+
 	return nil
 }
+
+// withTx runs fn with repositories bound to one transaction, falling back to
+// the plain repositories when no transaction manager is configured (e.g. in
+// tests)
+func (r *Resolver) withTx(ctx context.Context, fn func(repository.Repositories) error) error {
+	if r.txManager == nil {
+		return fn(*r.repos)
+	}
+	return r.txManager.WithTx(ctx, fn)
+}
+
+// loadUser fetches a user through the request's dataloader, falling back to a
+// direct lookup when no loaders are installed (e.g. in tests)
+func (r *Resolver) loadUser(ctx context.Context, id string) (*model.User, error) {
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID")
+	}
+
+	var dbUser *models.User
+	if l := loaders.For(ctx); l != nil {
+		dbUser, err = l.UserByID.Load(ctx, userID)
+	} else {
+		dbUser, err = r.repos.User.GetByID(ctx, userID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+
+	return dbUserToGraphQL(dbUser), nil
+}
+
+// loadPlaylist fetches a playlist through the request's dataloader when available
+func (r *Resolver) loadPlaylist(ctx context.Context, id uuid.UUID) (*models.Playlist, error) {
+	if l := loaders.For(ctx); l != nil {
+		return l.PlaylistByID.Load(ctx, id)
+	}
+	return r.repos.Playlist.GetByID(ctx, id)
+}
@@ -0,0 +1,224 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/daedal00/muse/backend/internal/models"
+	"github.com/daedal00/muse/backend/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverClose_SurfacesDependencyErrors(t *testing.T) {
+	redisErr := errors.New("connection reset")
+	var closed []string
+
+	resolver := &Resolver{closers: []resourceCloser{
+		{
+			name: "redis",
+			close: func() error {
+				closed = append(closed, "redis")
+				return redisErr
+			},
+		},
+		{
+			name:  "postgres",
+			stats: func() string { return "total=1 acquired=0 idle=1" },
+			close: func() error {
+				closed = append(closed, "postgres")
+				return nil
+			},
+		},
+	}}
+
+	err := resolver.Close()
+
+	assert.ErrorIs(t, err, redisErr)
+	assert.Contains(t, err.Error(), "redis")
+	// A failing dependency does not stop the others from closing
+	assert.Equal(t, []string{"redis", "postgres"}, closed)
+}
+
+func TestResolverClose_NoErrors(t *testing.T) {
+	resolver := &Resolver{closers: []resourceCloser{
+		{name: "postgres", close: func() error { return nil }},
+	}}
+
+	assert.NoError(t, resolver.Close())
+}
+
+// accountCleanup records which stores were asked to forget a deleted user
+type accountCleanup struct {
+	deleteErr error
+	cacheErr  error
+	calls     []string
+}
+
+type cleanupUsers struct {
+	repository.UserRepository
+	*accountCleanup
+}
+
+func (u cleanupUsers) DeleteAccount(ctx context.Context, id uuid.UUID) error {
+	u.calls = append(u.calls, "account")
+	return u.deleteErr
+}
+
+type cleanupSessions struct {
+	repository.SessionRepository
+	*accountCleanup
+}
+
+func (s cleanupSessions) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	s.calls = append(s.calls, "sessions")
+	return nil
+}
+
+type cleanupCache struct {
+	repository.MusicCacheRepository
+	*accountCleanup
+}
+
+func (c cleanupCache) InvalidateUserCache(ctx context.Context, userID uuid.UUID) error {
+	c.calls = append(c.calls, "cache")
+	return c.cacheErr
+}
+
+func newAccountCleanupResolver(cleanup *accountCleanup) *Resolver {
+	return &Resolver{repos: &repository.Repositories{
+		User:       cleanupUsers{accountCleanup: cleanup},
+		Session:    cleanupSessions{accountCleanup: cleanup},
+		MusicCache: cleanupCache{accountCleanup: cleanup},
+	}}
+}
+
+func TestResolverDeleteAccount(t *testing.T) {
+	cleanup := &accountCleanup{}
+	require.NoError(t, newAccountCleanupResolver(cleanup).deleteAccount(context.Background(), uuid.New()))
+	assert.Equal(t, []string{"account", "sessions", "cache"}, cleanup.calls)
+}
+
+func TestResolverDeleteAccount_DatabaseFailureKeepsRedis(t *testing.T) {
+	cleanup := &accountCleanup{deleteErr: repository.ErrNotFound}
+
+	err := newAccountCleanupResolver(cleanup).deleteAccount(context.Background(), uuid.New())
+
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	assert.Equal(t, []string{"account"}, cleanup.calls)
+}
+
+func TestResolverDeleteAccount_RedisFailureIsNotFatal(t *testing.T) {
+	cleanup := &accountCleanup{cacheErr: errors.New("connection reset")}
+
+	// The account is already gone, so a cache failure only gets logged
+	assert.NoError(t, newAccountCleanupResolver(cleanup).deleteAccount(context.Background(), uuid.New()))
+	assert.Equal(t, []string{"account", "sessions", "cache"}, cleanup.calls)
+}
+
+// lastSeenSessions fails UpdateLastSeen with err
+type lastSeenSessions struct {
+	repository.SessionRepository
+	err error
+}
+
+func (s lastSeenSessions) UpdateLastSeen(ctx context.Context, id string) error {
+	return s.err
+}
+
+func TestResolverSessionActive(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "active", want: true},
+		{name: "ended", err: fmt.Errorf("session %w", repository.ErrNotFound), want: false},
+		// A store outage does not sign everyone out
+		{name: "store failure", err: errors.New("connection reset"), want: true},
+	}
+
+	for _, tt := range tests {
+		resolver := &Resolver{repos: &repository.Repositories{Session: lastSeenSessions{err: tt.err}}}
+		assert.Equal(t, tt.want, resolver.SessionActive(context.Background(), "session-1"), tt.name)
+	}
+}
+
+// txPlaylists is a playlist store reached only through a transaction
+type txPlaylists struct {
+	repository.PlaylistRepository
+	editors map[uuid.UUID]bool
+	added   *[]uuid.UUID
+}
+
+func (p txPlaylists) GetByID(ctx context.Context, id uuid.UUID) (*models.Playlist, error) {
+	return &models.Playlist{ID: id, CreatedAt: time.Now()}, nil
+}
+
+func (p txPlaylists) CanEdit(ctx context.Context, playlistID, userID uuid.UUID) (bool, error) {
+	return p.editors[userID], nil
+}
+
+func (p txPlaylists) AddTrack(ctx context.Context, playlistID, trackID uuid.UUID, position int) error {
+	*p.added = append(*p.added, trackID)
+	return nil
+}
+
+type txTracks struct {
+	repository.TrackRepository
+}
+
+func (txTracks) GetByID(ctx context.Context, id uuid.UUID) (*models.Track, error) {
+	return &models.Track{ID: id}, nil
+}
+
+// statsCache records which playlists had their cached audio stats dropped
+type statsCache struct {
+	repository.MusicCacheRepository
+	deleted []uuid.UUID
+}
+
+func (c *statsCache) DeletePlaylistAudioStats(ctx context.Context, playlistID uuid.UUID) error {
+	c.deleted = append(c.deleted, playlistID)
+	return nil
+}
+
+// fakeTxManager hands WithTx callbacks its own repositories and counts calls
+type fakeTxManager struct {
+	repos repository.Repositories
+	calls int
+}
+
+func (m *fakeTxManager) WithTx(ctx context.Context, fn func(repository.Repositories) error) error {
+	m.calls++
+	return fn(m.repos)
+}
+
+func TestAddTrackToPlaylist_RunsInTransaction(t *testing.T) {
+	editor, stranger := uuid.New(), uuid.New()
+	var added []uuid.UUID
+	tx := &fakeTxManager{repos: repository.Repositories{
+		Playlist: txPlaylists{editors: map[uuid.UUID]bool{editor: true}, added: &added},
+		Track:    txTracks{},
+	}}
+	// Outside the transaction only the cache is set, so any other use panics
+	cache := &statsCache{}
+	resolver := &Resolver{repos: &repository.Repositories{MusicCache: cache}, txManager: tx}
+	mutation := resolver.Mutation()
+	playlistID, trackID := uuid.New(), uuid.New()
+
+	_, err := mutation.AddTrackToPlaylist(context.WithValue(context.Background(), UserIDKey, stranger.String()), playlistID.String(), trackID.String())
+	require.ErrorContains(t, err, "unauthorized")
+	assert.Empty(t, added)
+
+	playlist, err := mutation.AddTrackToPlaylist(context.WithValue(context.Background(), UserIDKey, editor.String()), playlistID.String(), trackID.String())
+	require.NoError(t, err)
+	assert.Equal(t, playlistID.String(), playlist.ID)
+	assert.Equal(t, []uuid.UUID{trackID}, added)
+	assert.Equal(t, 2, tx.calls)
+	assert.Equal(t, []uuid.UUID{playlistID}, cache.deleted, "only the successful add drops cached stats")
+}
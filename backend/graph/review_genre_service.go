@@ -0,0 +1,134 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/daedal00/muse/backend/internal/models"
+	"github.com/daedal00/muse/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	// artistGenresCacheType is the Spotify item cache type artist genres are
+	// kept under, keyed by artist Spotify ID
+	artistGenresCacheType = "artist_genres"
+
+	// genreReviewPageSize is how many of a user's reviews are read per query
+	// while filtering them by genre
+	genreReviewPageSize = 100
+	// maxGenreFilterReviews bounds how many of a user's most recent reviews
+	// the genre filter looks at
+	maxGenreFilterReviews = 1000
+)
+
+// genreFetcher loads the genres Spotify lists for artists by Spotify ID
+type genreFetcher interface {
+	FetchArtistGenres(ctx context.Context, ids []string) (map[string][]string, error)
+}
+
+// reviewGenreService filters a user's reviews by the genres of the reviewed
+// albums' artists. Genres come from the Spotify item cache, with misses
+// fetched from Spotify and written back.
+type reviewGenreService struct {
+	repos   *repository.Repositories
+	fetcher genreFetcher
+}
+
+func newReviewGenreService(repos *repository.Repositories, fetcher genreFetcher) *reviewGenreService {
+	return &reviewGenreService{repos: repos, fetcher: fetcher}
+}
+
+// GetUserReviewsByGenre returns a page of a user's reviews, newest first, of
+// albums whose artist Spotify tags with genre. Genres match case-insensitively.
+func (s *reviewGenreService) GetUserReviewsByGenre(ctx context.Context, userID uuid.UUID, genre string, limit, offset int) ([]*models.Review, error) {
+	genre = strings.TrimSpace(genre)
+	if genre == "" {
+		return nil, fmt.Errorf("genre is required")
+	}
+	limit = min(max(limit, 1), maxPageSize)
+	offset = max(offset, 0)
+
+	var reviews []*models.Review
+	for len(reviews) < maxGenreFilterReviews {
+		page, err := s.repos.Review.GetByUserID(ctx, userID, genreReviewPageSize, len(reviews))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list user reviews: %w", err)
+		}
+		reviews = append(reviews, page...)
+		if len(page) < genreReviewPageSize {
+			break
+		}
+	}
+
+	artistsByAlbum, err := s.albumArtists(ctx, reviews)
+	if err != nil {
+		return nil, err
+	}
+
+	var spotifyIDs []string
+	for _, artist := range artistsByAlbum {
+		if artist.SpotifyID != nil && !slices.Contains(spotifyIDs, *artist.SpotifyID) {
+			spotifyIDs = append(spotifyIDs, *artist.SpotifyID)
+		}
+	}
+	genres, err := hydrateItems(ctx, s.repos.MusicCache, artistGenresCacheType, spotifyIDs, s.fetcher.FetchArtistGenres)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := []*models.Review{}
+	for _, review := range reviews {
+		artist, ok := artistsByAlbum[review.AlbumID]
+		if !ok || artist.SpotifyID == nil {
+			continue
+		}
+		if slices.ContainsFunc(genres[*artist.SpotifyID], func(g string) bool { return strings.EqualFold(g, genre) }) {
+			matches = append(matches, review)
+		}
+	}
+
+	if offset >= len(matches) {
+		return []*models.Review{}, nil
+	}
+	return matches[offset:min(offset+limit, len(matches))], nil
+}
+
+// albumArtists returns the artist of each album the reviews are of
+func (s *reviewGenreService) albumArtists(ctx context.Context, reviews []*models.Review) (map[uuid.UUID]*models.Artist, error) {
+	var albumIDs []uuid.UUID
+	for _, review := range reviews {
+		if !slices.Contains(albumIDs, review.AlbumID) {
+			albumIDs = append(albumIDs, review.AlbumID)
+		}
+	}
+	if len(albumIDs) == 0 {
+		return map[uuid.UUID]*models.Artist{}, nil
+	}
+
+	albums, err := s.repos.Album.GetByIDs(ctx, albumIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reviewed albums: %w", err)
+	}
+
+	var artistIDs []uuid.UUID
+	for _, album := range albums {
+		if !slices.Contains(artistIDs, album.ArtistID) {
+			artistIDs = append(artistIDs, album.ArtistID)
+		}
+	}
+	artists, err := s.repos.Artist.GetByIDs(ctx, artistIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get album artists: %w", err)
+	}
+
+	artistsByAlbum := make(map[uuid.UUID]*models.Artist, len(albums))
+	for id, album := range albums {
+		if artist, ok := artists[album.ArtistID]; ok {
+			artistsByAlbum[id] = artist
+		}
+	}
+	return artistsByAlbum, nil
+}
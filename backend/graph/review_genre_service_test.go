@@ -0,0 +1,140 @@
+package graph
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/daedal00/muse/backend/internal/models"
+	"github.com/daedal00/muse/backend/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// genreCatalog holds a user's reviews and the albums and artists behind them
+type genreCatalog struct {
+	reviews []*models.Review
+	albums  map[uuid.UUID]*models.Album
+	artists map[uuid.UUID]*models.Artist
+}
+
+type genreReviews struct {
+	repository.ReviewRepository
+	catalog *genreCatalog
+}
+
+func (r genreReviews) GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.Review, error) {
+	reviews := r.catalog.reviews[min(offset, len(r.catalog.reviews)):]
+	return reviews[:min(limit, len(reviews))], nil
+}
+
+type genreAlbums struct {
+	repository.AlbumRepository
+	catalog *genreCatalog
+}
+
+func (r genreAlbums) GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.Album, error) {
+	albums := make(map[uuid.UUID]*models.Album)
+	for _, id := range ids {
+		if album, ok := r.catalog.albums[id]; ok {
+			albums[id] = album
+		}
+	}
+	return albums, nil
+}
+
+type genreArtists struct {
+	repository.ArtistRepository
+	catalog *genreCatalog
+}
+
+func (r genreArtists) GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.Artist, error) {
+	artists := make(map[uuid.UUID]*models.Artist)
+	for _, id := range ids {
+		if artist, ok := r.catalog.artists[id]; ok {
+			artists[id] = artist
+		}
+	}
+	return artists, nil
+}
+
+// fakeGenreFetcher serves artist genres and records which artists it was asked for
+type fakeGenreFetcher struct {
+	genres    map[string][]string
+	requested []string
+}
+
+func (f *fakeGenreFetcher) FetchArtistGenres(ctx context.Context, ids []string) (map[string][]string, error) {
+	f.requested = append(f.requested, ids...)
+	return pick(f.genres, ids), nil
+}
+
+// addReview adds a review of a new album by the artist with spotifyID
+func (c *genreCatalog) addReview(spotifyID string, createdAt time.Time) *models.Review {
+	artist := &models.Artist{ID: uuid.New(), SpotifyID: &spotifyID}
+	album := &models.Album{ID: uuid.New(), ArtistID: artist.ID}
+	review := &models.Review{ID: uuid.New(), AlbumID: album.ID, Rating: 4, CreatedAt: createdAt}
+
+	c.artists[artist.ID] = artist
+	c.albums[album.ID] = album
+	c.reviews = append(c.reviews, review)
+	return review
+}
+
+func newGenreTestService(catalog *genreCatalog, cache repository.MusicCacheRepository, fetcher genreFetcher) *reviewGenreService {
+	return newReviewGenreService(&repository.Repositories{
+		Review:     genreReviews{catalog: catalog},
+		Album:      genreAlbums{catalog: catalog},
+		Artist:     genreArtists{catalog: catalog},
+		MusicCache: cache,
+	}, fetcher)
+}
+
+func TestReviewGenreService_FiltersByCachedGenres(t *testing.T) {
+	catalog := &genreCatalog{albums: map[uuid.UUID]*models.Album{}, artists: map[uuid.UUID]*models.Artist{}}
+	now := time.Now()
+	coltrane := catalog.addReview("coltrane", now)
+	catalog.addReview("radiohead", now.Add(-time.Hour))
+	davis := catalog.addReview("davis", now.Add(-2*time.Hour))
+
+	cache := newMemoryItemCache()
+	cache.put(t, artistGenresCacheType, "coltrane", []string{"Jazz", "hard bop"})
+	cache.put(t, artistGenresCacheType, "radiohead", []string{"art rock"})
+	fetcher := &fakeGenreFetcher{genres: map[string][]string{"davis": {"jazz", "cool jazz"}}}
+
+	service := newGenreTestService(catalog, cache, fetcher)
+
+	reviews, err := service.GetUserReviewsByGenre(context.Background(), uuid.New(), "jazz", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, reviews, 2)
+	assert.Equal(t, coltrane.ID, reviews[0].ID)
+	assert.Equal(t, davis.ID, reviews[1].ID)
+
+	// Only the uncached artist was fetched, and its genres are now cached
+	assert.Equal(t, []string{"davis"}, fetcher.requested)
+	assert.JSONEq(t, `["jazz","cool jazz"]`, string(cache.items[artistGenresCacheType]["davis"]))
+
+	fetcher.requested = nil
+	page, err := service.GetUserReviewsByGenre(context.Background(), uuid.New(), "jazz", 1, 1)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, davis.ID, page[0].ID)
+	assert.Empty(t, fetcher.requested)
+}
+
+func TestReviewGenreService_NoMatches(t *testing.T) {
+	catalog := &genreCatalog{albums: map[uuid.UUID]*models.Album{}, artists: map[uuid.UUID]*models.Artist{}}
+	catalog.addReview("radiohead", time.Now())
+
+	cache := newMemoryItemCache()
+	cache.put(t, artistGenresCacheType, "radiohead", []string{"art rock"})
+
+	reviews, err := newGenreTestService(catalog, cache, &fakeGenreFetcher{}).GetUserReviewsByGenre(context.Background(), uuid.New(), "jazz", 10, 0)
+	require.NoError(t, err)
+	assert.NotNil(t, reviews)
+	assert.Empty(t, reviews)
+
+	_, err = newGenreTestService(catalog, cache, &fakeGenreFetcher{}).GetUserReviewsByGenre(context.Background(), uuid.New(), " ", 10, 0)
+	assert.Error(t, err)
+}
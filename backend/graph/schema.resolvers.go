@@ -6,6 +6,7 @@ package graph
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -13,8 +14,8 @@ import (
 	"github.com/daedal00/muse/backend/auth"
 	"github.com/daedal00/muse/backend/graph/model"
 	"github.com/daedal00/muse/backend/internal/models"
-	redisrepo "github.com/daedal00/muse/backend/internal/repository/redis"
-	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/daedal00/muse/backend/internal/repository"
+	"github.com/daedal00/muse/backend/internal/spotify"
 	"github.com/google/uuid"
 	spotifyapi "github.com/zmb3/spotify/v2"
 )
@@ -24,14 +25,20 @@ func (r *mutationResolver) CreateUser(ctx context.Context, name string, email st
 	start := time.Now()
 	log.Printf("[MUTATION] CreateUser started - Email: %s, Name: %s", email, name)
 
-	// 1. Hash password
+	// 1. Enforce password policy before hashing
+	if err := r.passwordPolicy.Validate(password); err != nil {
+		log.Printf("[MUTATION] CreateUser failed - Password policy: %v", err)
+		return nil, err
+	}
+
+	// 2. Hash password
 	hash, err := auth.HashPassword(password)
 	if err != nil {
 		log.Printf("[MUTATION] CreateUser failed - Password hashing error: %v", err)
 		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	// 2. Create database user model
+	// 3. Create database user model
 	dbUser := &models.User{
 		ID:           uuid.New(),
 		Name:         name,
@@ -41,7 +48,7 @@ func (r *mutationResolver) CreateUser(ctx context.Context, name string, email st
 		UpdatedAt:    time.Now(),
 	}
 
-	// 3. Store in database
+	// 4. Store in database
 	if err := r.repos.User.Create(ctx, dbUser); err != nil {
 		log.Printf("[MUTATION] CreateUser failed - Database error: %v", err)
 		return nil, fmt.Errorf("failed to create user: %w", err)
@@ -50,7 +57,7 @@ func (r *mutationResolver) CreateUser(ctx context.Context, name string, email st
 	duration := time.Since(start)
 	log.Printf("[MUTATION] CreateUser completed - UserID: %s, Duration: %v", dbUser.ID, duration)
 
-	// 4. Convert to GraphQL model and return
+	// 5. Convert to GraphQL model and return
 	return dbUserToGraphQL(dbUser), nil
 }
 
@@ -59,33 +66,31 @@ func (r *mutationResolver) Login(ctx context.Context, email string, password str
 	start := time.Now()
 	log.Printf("[MUTATION] Login started - Email: %s", email)
 
-	// 1) Look up user in database
-	dbUser, err := r.repos.User.GetByEmail(ctx, email)
+	// 1) Look up user and verify password against stored hash
+	dbUser, err := r.verifyCredentials(ctx, email, password)
 	if err != nil {
-		log.Printf("[MUTATION] Login failed - User not found: %s", email)
-		return "", fmt.Errorf("invalid credentials")
+		return "", err
 	}
 
-	// 2) Verify password against stored hash
-	if !auth.VerifyPassword(password, dbUser.PasswordHash) {
-		log.Printf("[MUTATION] Login failed - Invalid password for user: %s", email)
-		return "", fmt.Errorf("invalid credentials")
+	// 2) Record a session for the client logging in. The login fails without
+	// one, since a token with no session could never be revoked.
+	now := time.Now()
+	expiresAt := now.Add(loginTokenTTL)
+	session := newSession(ctx, dbUser.ID, now, expiresAt)
+	if err := r.repos.Session.Create(ctx, session); err != nil {
+		log.Printf("[MUTATION] Login failed - Failed to create session for user %s: %v", dbUser.ID, err)
+		return "", fmt.Errorf("failed to create session: %w", err)
 	}
 
 	// 3) Sign JWT Token
-	claims := auth.CustomClaims{
-		UserID: dbUser.ID.String(),
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			Issuer:    "muse-backend",
-		},
-	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedToken, err := token.SignedString([]byte(r.config.JWTSecret))
+	signedToken, err := r.signAccessToken(dbUser, session.ID, now, expiresAt)
 	if err != nil {
 		log.Printf("[MUTATION] Login failed - Token signing error: %v", err)
-		return "", fmt.Errorf("could not sign token: %w", err)
+		return "", err
+	}
+
+	if err := r.repos.User.UpdateLastLogin(ctx, dbUser.ID); err != nil {
+		log.Printf("[MUTATION] Login warning - Failed to record last login for user %s: %v", dbUser.ID, err)
 	}
 
 	duration := time.Since(start)
@@ -95,6 +100,30 @@ func (r *mutationResolver) Login(ctx context.Context, email string, password str
 	return signedToken, nil
 }
 
+// LoginWithRefreshToken is the resolver for the loginWithRefreshToken field.
+func (r *mutationResolver) LoginWithRefreshToken(ctx context.Context, email string, password string) (*model.AuthPayload, error) {
+	start := time.Now()
+	log.Printf("[MUTATION] LoginWithRefreshToken started - Email: %s", email)
+
+	payload, err := r.loginWithRefreshToken(ctx, email, password)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[MUTATION] LoginWithRefreshToken completed - Duration: %v", time.Since(start))
+	return payload, nil
+}
+
+// RefreshToken is the resolver for the refreshToken field.
+func (r *mutationResolver) RefreshToken(ctx context.Context, refreshToken string) (*model.AuthPayload, error) {
+	payload, err := r.refreshTokens(ctx, refreshToken)
+	if err != nil {
+		log.Printf("[MUTATION] RefreshToken failed - %v", err)
+		return nil, err
+	}
+	return payload, nil
+}
+
 // CreateReview is the resolver for the createReview field.
 func (r *mutationResolver) CreateReview(ctx context.Context, input model.CreateReviewInput) (*model.Review, error) {
 	start := time.Now()
@@ -136,12 +165,17 @@ func (r *mutationResolver) CreateReview(ctx context.Context, input model.CreateR
 		AlbumID:    albumID,
 		Rating:     int(input.Rating),
 		ReviewText: input.ReviewText,
+		HasSpoiler: input.HasSpoiler != nil && *input.HasSpoiler,
 		CreatedAt:  time.Now(),
 		UpdatedAt:  time.Now(),
 	}
 
 	// Store in database using review repository
 	if err := r.repos.Review.Create(ctx, dbReview); err != nil {
+		if errors.Is(err, repository.ErrDuplicateReview) {
+			log.Printf("[MUTATION] CreateReview failed - Duplicate review for album: %s", albumID)
+			return nil, err
+		}
 		log.Printf("[MUTATION] CreateReview failed - Database error: %v", err)
 		return nil, fmt.Errorf("failed to create review: %w", err)
 	}
@@ -211,6 +245,7 @@ func (r *mutationResolver) CreatePlaylist(ctx context.Context, input model.Creat
 		Description: input.Description,
 		CoverImage:  input.CoverImage,
 		CreatorID:   userID,
+		IsPublic:    true,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
@@ -253,35 +288,145 @@ func (r *mutationResolver) AddTrackToPlaylist(ctx context.Context, playlistID st
 		return nil, fmt.Errorf("invalid user ID")
 	}
 
-	// Verify playlist exists and user has permission
-	dbPlaylist, err := r.repos.Playlist.GetByID(ctx, pID)
+	// Check permission, add the track and read the playlist back in one
+	// transaction on the primary, so a failure at any step leaves no trace
+	var updatedPlaylist *models.Playlist
+	err = r.withTx(ctx, func(repos repository.Repositories) error {
+		// Verify playlist exists and user has permission
+		if _, err := repos.Playlist.GetByID(ctx, pID); err != nil {
+			return fmt.Errorf("playlist not found: %w", err)
+		}
+
+		canEdit, err := repos.Playlist.CanEdit(ctx, pID, userID)
+		if err != nil {
+			return err
+		}
+		if !canEdit {
+			return fmt.Errorf("unauthorized: you can only modify playlists you own or collaborate on")
+		}
+
+		// Verify track exists
+		if _, err := repos.Track.GetByID(ctx, tID); err != nil {
+			return fmt.Errorf("track not found: %w", err)
+		}
+
+		// Add track to playlist (position 0 means append to end)
+		if err := repos.Playlist.AddTrack(ctx, pID, tID, 0); err != nil {
+			return fmt.Errorf("failed to add track to playlist: %w", err)
+		}
+
+		// Return updated playlist
+		if updatedPlaylist, err = repos.Playlist.GetByID(ctx, pID); err != nil {
+			return fmt.Errorf("failed to fetch updated playlist: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("playlist not found: %w", err)
+		return nil, err
+	}
+
+	// The cached audio stats no longer match the playlist's tracks
+	if err := r.repos.MusicCache.DeletePlaylistAudioStats(ctx, pID); err != nil {
+		log.Printf("[CACHE] Warning: Failed to clear cached stats for playlist %s: %v", pID, err)
 	}
 
-	if dbPlaylist.CreatorID != userID {
-		return nil, fmt.Errorf("unauthorized: you can only modify your own playlists")
+	return dbPlaylistToGraphQL(updatedPlaylist), nil
+}
+
+// DeleteAccount is the resolver for the deleteAccount field.
+func (r *mutationResolver) DeleteAccount(ctx context.Context) (bool, error) {
+	log.Printf("[MUTATION] DeleteAccount started")
+
+	currentUserID, ok := ForContext(ctx)
+	if !ok {
+		log.Printf("[MUTATION] DeleteAccount failed - Unauthenticated request")
+		return false, fmt.Errorf("unauthenticated")
 	}
 
-	// Verify track exists
-	_, err = r.repos.Track.GetByID(ctx, tID)
+	userID, err := uuid.Parse(currentUserID)
 	if err != nil {
-		return nil, fmt.Errorf("track not found: %w", err)
+		return false, fmt.Errorf("invalid user ID")
 	}
 
-	// Add track to playlist (position 0 means append to end)
-	err = r.repos.Playlist.AddTrack(ctx, pID, tID, 0)
+	if err := r.deleteAccount(ctx, userID); err != nil {
+		log.Printf("[MUTATION] DeleteAccount failed - UserID: %s, Error: %v", userID, err)
+		return false, err
+	}
+
+	log.Printf("[MUTATION] DeleteAccount completed - UserID: %s", userID)
+	return true, nil
+}
+
+// ImportAlbum is the resolver for the importAlbum field.
+func (r *mutationResolver) ImportAlbum(ctx context.Context, spotifyID string) (*model.Album, error) {
+	log.Printf("[MUTATION] ImportAlbum started - SpotifyID: %s", spotifyID)
+
+	if _, ok := ForContext(ctx); !ok {
+		log.Printf("[MUTATION] ImportAlbum failed - Unauthenticated request")
+		return nil, fmt.Errorf("unauthenticated")
+	}
+	if r.imports == nil {
+		log.Printf("[MUTATION] ImportAlbum failed - Spotify service not available")
+		return nil, fmt.Errorf("spotify service not available")
+	}
+
+	album, err := r.imports.ImportAlbum(ctx, spotifyID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to add track to playlist: %w", err)
+		log.Printf("[MUTATION] ImportAlbum failed - SpotifyID: %s, Error: %v", spotifyID, err)
+		return nil, err
+	}
+
+	log.Printf("[MUTATION] ImportAlbum completed - AlbumID: %s", album.ID)
+	return dbAlbumToGraphQL(album), nil
+}
+
+// ImportTrack is the resolver for the importTrack field.
+func (r *mutationResolver) ImportTrack(ctx context.Context, spotifyID string) (*model.Track, error) {
+	log.Printf("[MUTATION] ImportTrack started - SpotifyID: %s", spotifyID)
+
+	if _, ok := ForContext(ctx); !ok {
+		log.Printf("[MUTATION] ImportTrack failed - Unauthenticated request")
+		return nil, fmt.Errorf("unauthenticated")
+	}
+	if r.imports == nil {
+		log.Printf("[MUTATION] ImportTrack failed - Spotify service not available")
+		return nil, fmt.Errorf("spotify service not available")
 	}
 
-	// Return updated playlist
-	updatedPlaylist, err := r.repos.Playlist.GetByID(ctx, pID)
+	track, err := r.imports.ImportTrack(ctx, spotifyID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch updated playlist: %w", err)
+		log.Printf("[MUTATION] ImportTrack failed - SpotifyID: %s, Error: %v", spotifyID, err)
+		return nil, err
 	}
 
-	return dbPlaylistToGraphQL(updatedPlaylist), nil
+	log.Printf("[MUTATION] ImportTrack completed - TrackID: %s", track.ID)
+	return dbTrackToGraphQL(track), nil
+}
+
+// Creator is the resolver for the creator field.
+func (r *playlistResolver) Creator(ctx context.Context, obj *model.Playlist) (*model.User, error) {
+	return r.loadUser(ctx, obj.CreatorID)
+}
+
+// AudioStats is the resolver for the audioStats field.
+func (r *playlistResolver) AudioStats(ctx context.Context, obj *model.Playlist) (*model.PlaylistAudioStats, error) {
+	if r.playlistStats == nil {
+		log.Printf("[QUERY] Playlist audioStats failed - Spotify service not available")
+		return nil, fmt.Errorf("spotify service not available")
+	}
+
+	playlistID, err := uuid.Parse(obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid playlist ID")
+	}
+
+	stats, err := r.playlistStats.Compute(ctx, playlistID)
+	if err != nil {
+		log.Printf("[QUERY] Playlist audioStats failed - PlaylistID: %s, Error: %v", playlistID, err)
+		return nil, fmt.Errorf("failed to compute playlist audio stats: %w", err)
+	}
+
+	return playlistAudioStatsToGraphQL(stats), nil
 }
 
 // Me is the resolver for the me field.
@@ -346,14 +491,9 @@ func (r *queryResolver) User(ctx context.Context, id string) (*model.User, error
 // Albums is the resolver for the albums field.
 func (r *queryResolver) Albums(ctx context.Context, first *int32, after *string) (*model.AlbumConnection, error) {
 	start := time.Now()
-	var limit int32 = 10
-	if first != nil {
-		limit = *first
-	}
-	log.Printf("[QUERY] Albums started - Limit: %d, After: %v", limit, after)
-
-	// Set default limit
-	limitInt := int(limit)
+	// Default and cap the page size the same way the complexity estimate does
+	limitInt := pageSize(first)
+	log.Printf("[QUERY] Albums started - Limit: %d, After: %v", limitInt, after)
 
 	// Use pagination helper for improved cursor-based pagination
 	albums, hasNextPage, err := r.paginationHelper.GetAlbumsWithCursor(ctx, limitInt, after)
@@ -416,13 +556,33 @@ func (r *queryResolver) Album(ctx context.Context, id string) (*model.Album, err
 	return dbAlbumToGraphQL(dbAlbum), nil
 }
 
+// AlbumPage is the resolver for the albumPage field.
+func (r *queryResolver) AlbumPage(ctx context.Context, spotifyID string, includeSpoilers *bool) (*model.AlbumPage, error) {
+	start := time.Now()
+	log.Printf("[QUERY] AlbumPage started - SpotifyID: %s", spotifyID)
+
+	// Signing in is optional; it only adds the viewer's own review
+	viewerID := uuid.Nil
+	if currentUserID, ok := ForContext(ctx); ok {
+		if parsed, err := uuid.Parse(currentUserID); err == nil {
+			viewerID = parsed
+		}
+	}
+
+	page, err := r.albumPages.GetAlbumPageData(ctx, viewerID, spotifyID)
+	if err != nil {
+		log.Printf("[QUERY] AlbumPage failed - SpotifyID: %s, Error: %v", spotifyID, err)
+		return nil, fmt.Errorf("album not found: %w", err)
+	}
+
+	log.Printf("[QUERY] AlbumPage completed - AlbumID: %s, Duration: %v", page.Album.ID, time.Since(start))
+	return albumPageToGraphQL(page, includeSpoilers != nil && *includeSpoilers), nil
+}
+
 // Tracks is the resolver for the tracks field.
 func (r *queryResolver) Tracks(ctx context.Context, first *int32, after *string) (*model.TrackConnection, error) {
-	// Set default limit
-	limit := 10
-	if first != nil {
-		limit = int(*first)
-	}
+	// Default and cap the page size the same way the complexity estimate does
+	limit := pageSize(first)
 
 	// Use pagination helper for improved cursor-based pagination
 	tracks, hasNextPage, err := r.paginationHelper.GetTracksWithCursor(ctx, limit, after)
@@ -475,11 +635,8 @@ func (r *queryResolver) Track(ctx context.Context, id string) (*model.Track, err
 
 // Playlists is the resolver for the playlists field.
 func (r *queryResolver) Playlists(ctx context.Context, first *int32, after *string) (*model.PlaylistConnection, error) {
-	// Set default limit
-	limit := 10
-	if first != nil {
-		limit = int(*first)
-	}
+	// Default and cap the page size the same way the complexity estimate does
+	limit := pageSize(first)
 
 	// Use pagination helper for improved cursor-based pagination
 	playlists, hasNextPage, err := r.paginationHelper.GetPlaylistsWithCursor(ctx, limit, after)
@@ -522,7 +679,7 @@ func (r *queryResolver) Playlist(ctx context.Context, id string) (*model.Playlis
 		return nil, fmt.Errorf("invalid playlist ID")
 	}
 
-	dbPlaylist, err := r.repos.Playlist.GetByID(ctx, playlistID)
+	dbPlaylist, err := r.loadPlaylist(ctx, playlistID)
 	if err != nil {
 		return nil, fmt.Errorf("playlist not found: %w", err)
 	}
@@ -531,12 +688,9 @@ func (r *queryResolver) Playlist(ctx context.Context, id string) (*model.Playlis
 }
 
 // Reviews is the resolver for the reviews field.
-func (r *queryResolver) Reviews(ctx context.Context, first *int32, after *string) (*model.ReviewConnection, error) {
-	// Set default limit
-	limit := 10
-	if first != nil {
-		limit = int(*first)
-	}
+func (r *queryResolver) Reviews(ctx context.Context, first *int32, after *string, includeSpoilers *bool) (*model.ReviewConnection, error) {
+	// Default and cap the page size the same way the complexity estimate does
+	limit := pageSize(first)
 
 	// Use pagination helper for improved cursor-based pagination
 	reviews, hasNextPage, err := r.paginationHelper.GetReviewsWithCursor(ctx, limit, after)
@@ -549,7 +703,7 @@ func (r *queryResolver) Reviews(ctx context.Context, first *int32, after *string
 	for i, review := range reviews {
 		edges[i] = &model.ReviewEdge{
 			Cursor: r.paginationHelper.EncodeCursor(review.ID.String(), review.CreatedAt, i),
-			Node:   dbReviewToGraphQL(review),
+			Node:   listedReviewToGraphQL(review, includeSpoilers != nil && *includeSpoilers),
 		}
 	}
 
@@ -573,7 +727,7 @@ func (r *queryResolver) Reviews(ctx context.Context, first *int32, after *string
 }
 
 // Review is the resolver for the review field.
-func (r *queryResolver) Review(ctx context.Context, id string) (*model.Review, error) {
+func (r *queryResolver) Review(ctx context.Context, id string, includeSpoilers *bool) (*model.Review, error) {
 	reviewID, err := uuid.Parse(id)
 	if err != nil {
 		return nil, fmt.Errorf("invalid review ID")
@@ -584,7 +738,43 @@ func (r *queryResolver) Review(ctx context.Context, id string) (*model.Review, e
 		return nil, fmt.Errorf("review not found: %w", err)
 	}
 
-	return dbReviewToGraphQL(dbReview), nil
+	return listedReviewToGraphQL(dbReview, includeSpoilers != nil && *includeSpoilers), nil
+}
+
+// MyReviewsByGenre is the resolver for the myReviewsByGenre field.
+func (r *queryResolver) MyReviewsByGenre(ctx context.Context, genre string, first *int32, offset *int32) ([]*model.Review, error) {
+	currentUserID, ok := ForContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("unauthenticated")
+	}
+	userID, err := uuid.Parse(currentUserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID")
+	}
+	if r.reviewGenres == nil {
+		log.Printf("[QUERY] MyReviewsByGenre failed - Spotify service not available")
+		return nil, fmt.Errorf("spotify service not available")
+	}
+
+	limit, skip := defaultPageSize, 0
+	if first != nil {
+		limit = int(*first)
+	}
+	if offset != nil {
+		skip = int(*offset)
+	}
+
+	reviews, err := r.reviewGenres.GetUserReviewsByGenre(ctx, userID, genre, limit, skip)
+	if err != nil {
+		log.Printf("[QUERY] MyReviewsByGenre failed - UserID: %s, Genre: %s, Error: %v", userID, genre, err)
+		return nil, err
+	}
+
+	result := make([]*model.Review, len(reviews))
+	for i, review := range reviews {
+		result[i] = dbReviewToGraphQL(review)
+	}
+	return result, nil
 }
 
 // RecentlyPlayed is the resolver for the recentlyPlayed field.
@@ -602,27 +792,14 @@ func (r *queryResolver) RecentlyPlayed(ctx context.Context, limit *int32) ([]*mo
 		return nil, fmt.Errorf("invalid user ID")
 	}
 
-	// Get cached music data
-	cachedData, err := r.repos.MusicCache.GetUserMusicData(ctx, userID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get user music data: %w", err)
-	}
-
-	if cachedData == nil {
-		// No cached data, return empty list
-		return []*model.Track{}, nil
-	}
-
-	// Cast to MusicData
-	musicData, ok := cachedData.(*redisrepo.MusicData)
-	if !ok {
-		return nil, fmt.Errorf("invalid cached data format")
+	// Read the user's recently played list, newest first
+	n := 0
+	if limit != nil {
+		n = int(*limit)
 	}
-
-	// Apply limit if specified
-	recentlyPlayed := musicData.RecentlyPlayed
-	if limit != nil && len(recentlyPlayed) > int(*limit) {
-		recentlyPlayed = recentlyPlayed[:int(*limit)]
+	recentlyPlayed, err := r.repos.MusicCache.GetRecentlyPlayed(ctx, userID, n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recently played: %w", err)
 	}
 
 	// Convert database models to GraphQL models
@@ -641,7 +818,11 @@ func (r *queryResolver) SearchAlbums(ctx context.Context, input model.AlbumSearc
 	if input.Limit != nil {
 		limit = int(*input.Limit)
 	}
-	log.Printf("[QUERY] SearchAlbums started - Query: '%s', Limit: %d, Source: %s", input.Query, limit, input.Source)
+	offset := 0
+	if input.Offset != nil {
+		offset = int(*input.Offset)
+	}
+	log.Printf("[QUERY] SearchAlbums started - Query: '%s', Limit: %d, Offset: %d, Source: %s", input.Query, limit, offset, input.Source)
 
 	if r.spotifyServices == nil {
 		log.Printf("[QUERY] SearchAlbums failed - Spotify service not available")
@@ -649,24 +830,23 @@ func (r *queryResolver) SearchAlbums(ctx context.Context, input model.AlbumSearc
 	}
 
 	// Check cache first
-	cacheKey := fmt.Sprintf("%s:%d", input.Query, limit)
-	log.Printf("[CACHE] Checking cache for albums - Key: %s", cacheKey)
-
-	if cachedData, err := r.repos.MusicCache.GetSearchResults(ctx, cacheKey, "albums"); err == nil && cachedData != nil {
-		if searchData, ok := cachedData.(*redisrepo.SearchCacheData); ok {
-			if results, ok := searchData.Results.([]*model.AlbumSearchResult); ok {
-				duration := time.Since(start)
-				log.Printf("[QUERY] SearchAlbums completed (CACHE HIT) - Query: '%s', Count: %d, Duration: %v", input.Query, len(results), duration)
-				return results, nil
-			}
+	log.Printf("[CACHE] Checking cache for albums - Query: %s, Limit: %d, Offset: %d", input.Query, limit, offset)
+
+	if cached, err := r.repos.MusicCache.GetAlbumSearchResults(ctx, input.Query, limit, offset); err == nil && cached != nil {
+		results := make([]*model.AlbumSearchResult, len(cached))
+		for i, album := range cached {
+			results[i] = cachedAlbumToSearchResult(album)
 		}
+		duration := time.Since(start)
+		log.Printf("[QUERY] SearchAlbums completed (CACHE HIT) - Query: '%s', Count: %d, Duration: %v", input.Query, len(results), duration)
+		return results, nil
 	}
-	log.Printf("[CACHE] Cache miss for albums - Key: %s", cacheKey)
+	log.Printf("[CACHE] Cache miss for albums - Query: %s, Limit: %d, Offset: %d", input.Query, limit, offset)
 
 	// Use the new Spotify client to search for albums
 	log.Printf("[SPOTIFY] Calling Spotify API for albums - Query: '%s', Limit: %d", input.Query, limit)
 	results, err := r.spotifyServices.Search.SearchAlbums(ctx, input.Query,
-		spotifyapi.Limit(limit))
+		spotifyapi.Limit(limit), spotifyapi.Offset(offset))
 	if err != nil {
 		log.Printf("[SPOTIFY] SearchAlbums failed - API error: %v", err)
 		return nil, fmt.Errorf("failed to search albums: %w", err)
@@ -675,45 +855,19 @@ func (r *queryResolver) SearchAlbums(ctx context.Context, input model.AlbumSearc
 	log.Printf("[SPOTIFY] Spotify API response received - Albums found: %d", len(results.Albums.Albums))
 
 	// Convert Spotify results to GraphQL model
+	albums := []models.SpotifyAlbum{}
 	var albumResults []*model.AlbumSearchResult
 	if results.Albums != nil {
 		for _, album := range results.Albums.Albums {
-			// Convert artists
-			var artists []*model.ArtistSearchResult
-			for _, artist := range album.Artists {
-				artists = append(artists, &model.ArtistSearchResult{
-					ID:             string(artist.ID),
-					Name:           artist.Name,
-					ExternalSource: model.ExternalSourceSpotify,
-				})
-			}
-
-			// Convert release date
-			var releaseDate *string
-			if album.ReleaseDate != "" {
-				releaseDate = &album.ReleaseDate
-			}
-
-			// Convert cover image
-			var coverImage *string
-			if len(album.Images) > 0 {
-				coverImage = &album.Images[0].URL
-			}
-
-			albumResults = append(albumResults, &model.AlbumSearchResult{
-				ID:             string(album.ID),
-				Title:          album.Name,
-				Artist:         artists,
-				ReleaseDate:    releaseDate,
-				CoverImage:     coverImage,
-				ExternalSource: model.ExternalSourceSpotify,
-			})
+			cached := spotify.ToModelAlbum(album)
+			albums = append(albums, cached)
+			albumResults = append(albumResults, cachedAlbumToSearchResult(cached))
 		}
 	}
 
 	// Cache the results for faster future searches
-	log.Printf("[CACHE] Caching album search results - Key: %s, Count: %d", cacheKey, len(albumResults))
-	if err := r.repos.MusicCache.SetSearchResults(ctx, cacheKey, "albums", albumResults); err != nil {
+	log.Printf("[CACHE] Caching album search results - Query: %s, Offset: %d, Count: %d", input.Query, offset, len(albumResults))
+	if err := r.repos.MusicCache.SetAlbumSearchResults(ctx, input.Query, limit, offset, albums); err != nil {
 		// Log the error but don't fail the request
 		log.Printf("[CACHE] Warning: Failed to cache album search results: %v", err)
 	}
@@ -731,7 +885,11 @@ func (r *queryResolver) SearchArtists(ctx context.Context, input model.ArtistSea
 	if input.Limit != nil {
 		limit = int(*input.Limit)
 	}
-	log.Printf("[QUERY] SearchArtists started - Query: '%s', Limit: %d, Source: %s", input.Query, limit, input.Source)
+	offset := 0
+	if input.Offset != nil {
+		offset = int(*input.Offset)
+	}
+	log.Printf("[QUERY] SearchArtists started - Query: '%s', Limit: %d, Offset: %d, Source: %s", input.Query, limit, offset, input.Source)
 
 	if r.spotifyServices == nil {
 		log.Printf("[QUERY] SearchArtists failed - Spotify service not available")
@@ -739,24 +897,23 @@ func (r *queryResolver) SearchArtists(ctx context.Context, input model.ArtistSea
 	}
 
 	// Check cache first
-	cacheKey := fmt.Sprintf("%s:%d", input.Query, limit)
-	log.Printf("[CACHE] Checking cache for artists - Key: %s", cacheKey)
-
-	if cachedData, err := r.repos.MusicCache.GetSearchResults(ctx, cacheKey, "artists"); err == nil && cachedData != nil {
-		if searchData, ok := cachedData.(*redisrepo.SearchCacheData); ok {
-			if results, ok := searchData.Results.([]*model.ArtistSearchResult); ok {
-				duration := time.Since(start)
-				log.Printf("[QUERY] SearchArtists completed (CACHE HIT) - Query: '%s', Count: %d, Duration: %v", input.Query, len(results), duration)
-				return results, nil
-			}
+	log.Printf("[CACHE] Checking cache for artists - Query: %s, Limit: %d, Offset: %d", input.Query, limit, offset)
+
+	if cached, err := r.repos.MusicCache.GetArtistSearchResults(ctx, input.Query, limit, offset); err == nil && cached != nil {
+		results := make([]*model.ArtistSearchResult, len(cached))
+		for i, artist := range cached {
+			results[i] = cachedArtistToSearchResult(artist)
 		}
+		duration := time.Since(start)
+		log.Printf("[QUERY] SearchArtists completed (CACHE HIT) - Query: '%s', Count: %d, Duration: %v", input.Query, len(results), duration)
+		return results, nil
 	}
-	log.Printf("[CACHE] Cache miss for artists - Key: %s", cacheKey)
+	log.Printf("[CACHE] Cache miss for artists - Query: %s, Limit: %d, Offset: %d", input.Query, limit, offset)
 
 	// Use the new Spotify client to search for artists
 	log.Printf("[SPOTIFY] Calling Spotify API for artists - Query: '%s', Limit: %d", input.Query, limit)
 	results, err := r.spotifyServices.Search.SearchArtists(ctx, input.Query,
-		spotifyapi.Limit(limit))
+		spotifyapi.Limit(limit), spotifyapi.Offset(offset))
 	if err != nil {
 		log.Printf("[SPOTIFY] SearchArtists failed - API error: %v", err)
 		return nil, fmt.Errorf("failed to search artists: %w", err)
@@ -765,20 +922,19 @@ func (r *queryResolver) SearchArtists(ctx context.Context, input model.ArtistSea
 	log.Printf("[SPOTIFY] Spotify API response received - Artists found: %d", len(results.Artists.Artists))
 
 	// Convert Spotify results to GraphQL model
+	artists := []models.SpotifyArtist{}
 	var artistResults []*model.ArtistSearchResult
 	if results.Artists != nil {
 		for _, artist := range results.Artists.Artists {
-			artistResults = append(artistResults, &model.ArtistSearchResult{
-				ID:             string(artist.ID),
-				Name:           artist.Name,
-				ExternalSource: model.ExternalSourceSpotify,
-			})
+			cached := spotify.ToModelArtist(artist.SimpleArtist)
+			artists = append(artists, cached)
+			artistResults = append(artistResults, cachedArtistToSearchResult(cached))
 		}
 	}
 
 	// Cache the results for faster future searches
-	log.Printf("[CACHE] Caching artist search results - Key: %s, Count: %d", cacheKey, len(artistResults))
-	if err := r.repos.MusicCache.SetSearchResults(ctx, cacheKey, "artists", artistResults); err != nil {
+	log.Printf("[CACHE] Caching artist search results - Query: %s, Offset: %d, Count: %d", input.Query, offset, len(artistResults))
+	if err := r.repos.MusicCache.SetArtistSearchResults(ctx, input.Query, limit, offset, artists); err != nil {
 		// Log the error but don't fail the request
 		log.Printf("[CACHE] Warning: Failed to cache artist search results: %v", err)
 	}
@@ -789,26 +945,83 @@ func (r *queryResolver) SearchArtists(ctx context.Context, input model.ArtistSea
 	return artistResults, nil
 }
 
+// ResolveItems is the resolver for the resolveItems field.
+func (r *queryResolver) ResolveItems(ctx context.Context, refs []*model.ItemRefInput) ([]model.ResolvedItem, error) {
+	start := time.Now()
+	log.Printf("[QUERY] ResolveItems started - Refs: %d", len(refs))
+
+	if r.itemService == nil {
+		log.Printf("[QUERY] ResolveItems failed - Spotify service not available")
+		return nil, fmt.Errorf("spotify service not available")
+	}
+
+	if len(refs) > maxResolveItemRefs {
+		log.Printf("[QUERY] ResolveItems failed - Too many refs: %d", len(refs))
+		return nil, fmt.Errorf("at most %d items can be resolved at once", maxResolveItemRefs)
+	}
+
+	items, err := r.itemService.Resolve(ctx, refs)
+	if err != nil {
+		log.Printf("[QUERY] ResolveItems failed - Error: %v", err)
+		return nil, fmt.Errorf("failed to resolve items: %w", err)
+	}
+
+	duration := time.Since(start)
+	log.Printf("[QUERY] ResolveItems completed - Refs: %d, Resolved: %d, Duration: %v", len(refs), len(items), duration)
+
+	return items, nil
+}
+
+// User is the resolver for the user field.
+func (r *reviewResolver) User(ctx context.Context, obj *model.Review) (*model.User, error) {
+	return r.loadUser(ctx, obj.UserID)
+}
+
 // ReviewAdded is the resolver for the reviewAdded field.
-func (r *subscriptionResolver) ReviewAdded(ctx context.Context, albumID string) (<-chan *model.Review, error) {
+func (r *subscriptionResolver) ReviewAdded(ctx context.Context, albumID string, includeSpoilers *bool) (<-chan *model.Review, error) {
 	// Subscribe to review updates for the specified album using subscription manager
 	reviewChan, cleanup := r.subscriptionMgr.Subscribe(ctx, albumID)
 
 	// The cleanup function will be called automatically when the context is cancelled
 	_ = cleanup
 
-	return reviewChan, nil
+	if includeSpoilers != nil && *includeSpoilers {
+		return reviewChan, nil
+	}
+
+	// Reviews are shared between subscribers, so redact a copy of each one
+	redacted := make(chan *model.Review, cap(reviewChan))
+	go func() {
+		defer close(redacted)
+		for review := range reviewChan {
+			select {
+			case redacted <- redactSpoiler(review, false):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return redacted, nil
 }
 
 // Mutation returns MutationResolver implementation.
 func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 
+// Playlist returns PlaylistResolver implementation.
+func (r *Resolver) Playlist() PlaylistResolver { return &playlistResolver{r} }
+
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
+// Review returns ReviewResolver implementation.
+func (r *Resolver) Review() ReviewResolver { return &reviewResolver{r} }
+
 // Subscription returns SubscriptionResolver implementation.
 func (r *Resolver) Subscription() SubscriptionResolver { return &subscriptionResolver{r} }
 
 type mutationResolver struct{ *Resolver }
+type playlistResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type reviewResolver struct{ *Resolver }
 type subscriptionResolver struct{ *Resolver }
@@ -0,0 +1,275 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/daedal00/muse/backend/graph/model"
+	"github.com/daedal00/muse/backend/internal/models"
+	"github.com/daedal00/muse/backend/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// executeQuery runs query through the executable schema and decodes the
+// response's data into out, failing on any GraphQL error
+func executeQuery(t *testing.T, resolver *Resolver, query string, out interface{}) {
+	t.Helper()
+
+	srv := handler.New(NewExecutableSchema(NewConfig(resolver)))
+	srv.AddTransport(transport.POST{})
+
+	body, err := json.Marshal(map[string]string{"query": query})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	resolver.LoaderMiddleware(srv).ServeHTTP(rec, req)
+
+	var resp struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Empty(t, resp.Errors)
+	require.NoError(t, json.Unmarshal(resp.Data, out))
+}
+
+// listedReviews serves a fixed page of reviews
+type listedReviews struct {
+	repository.ReviewRepository
+	reviews []*models.Review
+}
+
+func (r listedReviews) List(ctx context.Context, limit, offset int) ([]*models.Review, error) {
+	return r.reviews[:min(limit, len(r.reviews))], nil
+}
+
+func (r listedReviews) GetByID(ctx context.Context, id uuid.UUID) (*models.Review, error) {
+	for _, review := range r.reviews {
+		if review.ID == id {
+			return review, nil
+		}
+	}
+	return nil, fmt.Errorf("review %w", repository.ErrNotFound)
+}
+
+// countingUsers serves users by ID and counts the lookups made
+type countingUsers struct {
+	repository.UserRepository
+	users    map[uuid.UUID]*models.User
+	batches  *atomic.Int32
+	singles  *atomic.Int32
+	batchIDs *atomic.Int32
+}
+
+func (r countingUsers) GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.User, error) {
+	r.batches.Add(1)
+	r.batchIDs.Add(int32(len(ids)))
+	users := make(map[uuid.UUID]*models.User, len(ids))
+	for _, id := range ids {
+		if user, ok := r.users[id]; ok {
+			users[id] = user
+		}
+	}
+	return users, nil
+}
+
+func (r countingUsers) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	r.singles.Add(1)
+	return r.users[id], nil
+}
+
+func TestReviewsQuery_BatchesReviewAuthors(t *testing.T) {
+	users := make(map[uuid.UUID]*models.User)
+	var reviews []*models.Review
+	for i := 0; i < 20; i++ {
+		user := &models.User{ID: uuid.New(), Name: fmt.Sprintf("Reviewer %d", i+1)}
+		users[user.ID] = user
+		reviews = append(reviews, &models.Review{ID: uuid.New(), UserID: user.ID, Rating: i%5 + 1, CreatedAt: time.Now()})
+	}
+
+	var batches, singles, batchIDs atomic.Int32
+	repos := &repository.Repositories{
+		Review: listedReviews{reviews: reviews},
+		User:   countingUsers{users: users, batches: &batches, singles: &singles, batchIDs: &batchIDs},
+	}
+	resolver := &Resolver{repos: repos, paginationHelper: NewPaginationHelper(repos)}
+
+	var page struct {
+		Reviews struct {
+			Edges []struct {
+				Node struct {
+					User struct {
+						Name string `json:"name"`
+					} `json:"user"`
+				} `json:"node"`
+			} `json:"edges"`
+		} `json:"reviews"`
+	}
+	executeQuery(t, resolver, `{ reviews(first: 20) { edges { node { user { name } } } } }`, &page)
+
+	require.Len(t, page.Reviews.Edges, 20)
+	for i, edge := range page.Reviews.Edges {
+		require.Equal(t, fmt.Sprintf("Reviewer %d", i+1), edge.Node.User.Name)
+	}
+	require.Equal(t, int32(1), batches.Load(), "all 20 authors should load in one GetByIDs call")
+	require.Equal(t, int32(20), batchIDs.Load())
+	require.Zero(t, singles.Load())
+}
+
+func TestReviewsQuery_CapsPageSize(t *testing.T) {
+	reviews := make([]*models.Review, maxPageSize+50)
+	for i := range reviews {
+		reviews[i] = &models.Review{ID: uuid.New(), UserID: uuid.New(), Rating: 3, CreatedAt: time.Now()}
+	}
+	repos := &repository.Repositories{Review: listedReviews{reviews: reviews}}
+	resolver := &Resolver{repos: repos, paginationHelper: NewPaginationHelper(repos)}
+
+	var page struct {
+		Reviews struct {
+			Edges []struct {
+				Node struct {
+					ID string `json:"id"`
+				} `json:"node"`
+			} `json:"edges"`
+			PageInfo struct {
+				HasNextPage bool `json:"hasNextPage"`
+			} `json:"pageInfo"`
+		} `json:"reviews"`
+	}
+	executeQuery(t, resolver, `{ reviews(first: 1000000) { edges { node { id } } pageInfo { hasNextPage } } }`, &page)
+
+	require.Len(t, page.Reviews.Edges, maxPageSize)
+	require.True(t, page.Reviews.PageInfo.HasNextPage)
+}
+
+func TestReviewsQuery_RedactsSpoilersUnlessOptedIn(t *testing.T) {
+	text := func(s string) *string { return &s }
+	repos := &repository.Repositories{Review: listedReviews{reviews: []*models.Review{
+		{ID: uuid.New(), UserID: uuid.New(), Rating: 5, ReviewText: text("The narrator was dead all along"), HasSpoiler: true, CreatedAt: time.Now()},
+		{ID: uuid.New(), UserID: uuid.New(), Rating: 4, ReviewText: text("Great production"), CreatedAt: time.Now()},
+	}}}
+	resolver := &Resolver{repos: repos, paginationHelper: NewPaginationHelper(repos)}
+
+	type page struct {
+		Reviews struct {
+			Edges []struct {
+				Node struct {
+					ReviewText *string `json:"reviewText"`
+					HasSpoiler bool    `json:"hasSpoiler"`
+				} `json:"node"`
+			} `json:"edges"`
+		} `json:"reviews"`
+	}
+
+	var hidden page
+	executeQuery(t, resolver, `{ reviews(first: 10) { edges { node { reviewText hasSpoiler } } } }`, &hidden)
+	require.Len(t, hidden.Reviews.Edges, 2)
+	require.True(t, hidden.Reviews.Edges[0].Node.HasSpoiler)
+	require.Nil(t, hidden.Reviews.Edges[0].Node.ReviewText)
+	require.Equal(t, "Great production", *hidden.Reviews.Edges[1].Node.ReviewText)
+
+	var shown page
+	executeQuery(t, resolver, `{ reviews(first: 10, includeSpoilers: true) { edges { node { reviewText hasSpoiler } } } }`, &shown)
+	require.Len(t, shown.Reviews.Edges, 2)
+	require.NotNil(t, shown.Reviews.Edges[0].Node.ReviewText)
+	require.Equal(t, "The narrator was dead all along", *shown.Reviews.Edges[0].Node.ReviewText)
+}
+
+func TestReviewQuery_RedactsSpoilersUnlessOptedIn(t *testing.T) {
+	text := "The narrator was dead all along"
+	review := &models.Review{ID: uuid.New(), UserID: uuid.New(), Rating: 5, ReviewText: &text, HasSpoiler: true, CreatedAt: time.Now()}
+	resolver := &Resolver{repos: &repository.Repositories{Review: listedReviews{reviews: []*models.Review{review}}}}
+
+	type result struct {
+		Review struct {
+			ReviewText *string `json:"reviewText"`
+		} `json:"review"`
+	}
+
+	var hidden result
+	executeQuery(t, resolver, fmt.Sprintf(`{ review(id: %q) { reviewText } }`, review.ID), &hidden)
+	require.Nil(t, hidden.Review.ReviewText)
+
+	var shown result
+	executeQuery(t, resolver, fmt.Sprintf(`{ review(id: %q, includeSpoilers: true) { reviewText } }`, review.ID), &shown)
+	require.NotNil(t, shown.Review.ReviewText)
+	require.Equal(t, text, *shown.Review.ReviewText)
+}
+
+func TestReviewAddedSubscription_RedactsSpoilersUnlessOptedIn(t *testing.T) {
+	mgr := &SubscriptionManager{subscribers: make(map[string]map[chan *model.Review]bool)}
+	resolver := &Resolver{subscriptionMgr: mgr}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	albumID := uuid.New().String()
+	optedOut, err := resolver.Subscription().ReviewAdded(ctx, albumID, nil)
+	require.NoError(t, err)
+	includeSpoilers := true
+	optedIn, err := resolver.Subscription().ReviewAdded(ctx, albumID, &includeSpoilers)
+	require.NoError(t, err)
+
+	text := "The narrator was dead all along"
+	mgr.distributeReview(albumID, &model.Review{ID: uuid.New().String(), ReviewText: &text, HasSpoiler: true})
+
+	hidden := <-optedOut
+	require.Nil(t, hidden.ReviewText)
+	shown := <-optedIn
+	require.NotNil(t, shown.ReviewText)
+	require.Equal(t, text, *shown.ReviewText)
+}
+
+func TestAlbumPageQuery_RedactsSpoilersUnlessOptedIn(t *testing.T) {
+	service, store := newAlbumPageFixture(2)
+	spoiler, clean := "The last track is a hidden reprise", "Warm and loose"
+	store.reviews[0].ReviewText, store.reviews[0].HasSpoiler = &spoiler, true
+	store.reviews[1].ReviewText = &clean
+	resolver := &Resolver{repos: service.repos, albumPages: service}
+
+	type page struct {
+		AlbumPage struct {
+			ReviewCount int `json:"reviewCount"`
+			Tracks      []struct {
+				Title string `json:"title"`
+			} `json:"tracks"`
+			Reviews []struct {
+				ReviewText *string `json:"reviewText"`
+				User       struct {
+					Name string `json:"name"`
+				} `json:"user"`
+			} `json:"reviews"`
+			ViewerReview *struct {
+				ID string `json:"id"`
+			} `json:"viewerReview"`
+		} `json:"albumPage"`
+	}
+	const fields = `{ reviewCount tracks { title } reviews { reviewText user { name } } viewerReview { id } }`
+
+	var hidden page
+	executeQuery(t, resolver, `{ albumPage(spotifyId: "album-spotify-id") `+fields+` }`, &hidden)
+	require.Equal(t, 2, hidden.AlbumPage.ReviewCount)
+	require.Len(t, hidden.AlbumPage.Tracks, 12)
+	require.Len(t, hidden.AlbumPage.Reviews, 2)
+	require.Nil(t, hidden.AlbumPage.Reviews[0].ReviewText)
+	require.Equal(t, "Reviewer 1", hidden.AlbumPage.Reviews[0].User.Name)
+	require.Equal(t, clean, *hidden.AlbumPage.Reviews[1].ReviewText)
+	require.Nil(t, hidden.AlbumPage.ViewerReview)
+
+	var shown page
+	executeQuery(t, resolver, `{ albumPage(spotifyId: "album-spotify-id", includeSpoilers: true) `+fields+` }`, &shown)
+	require.NotNil(t, shown.AlbumPage.Reviews[0].ReviewText)
+	require.Equal(t, spoiler, *shown.AlbumPage.Reviews[0].ReviewText)
+}
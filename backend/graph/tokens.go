@@ -0,0 +1,170 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/daedal00/muse/backend/auth"
+	"github.com/daedal00/muse/backend/graph/model"
+	"github.com/daedal00/muse/backend/internal/models"
+	"github.com/daedal00/muse/backend/internal/repository"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	// loginTokenTTL is the lifetime of tokens from login, which cannot be renewed
+	loginTokenTTL = 24 * time.Hour
+
+	// accessTokenTTL is the lifetime of access tokens paired with a refresh token
+	accessTokenTTL = 15 * time.Minute
+
+	// refreshTokenTTL is how long a refresh token, and the session it renews,
+	// stays valid without being used
+	refreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// errInvalidRefreshToken is returned for refresh tokens that are unknown,
+// expired, already used, or whose session has ended
+var errInvalidRefreshToken = fmt.Errorf("invalid refresh token: %w", auth.ErrUnauthenticated)
+
+// verifyCredentials returns the user with the given email and password
+func (r *Resolver) verifyCredentials(ctx context.Context, email, password string) (*models.User, error) {
+	dbUser, err := r.repos.User.GetByEmail(ctx, email)
+	if err != nil {
+		log.Printf("[MUTATION] Login failed - User not found: %s", email)
+		return nil, fmt.Errorf("invalid credentials")
+	}
+
+	if !auth.VerifyPassword(password, dbUser.PasswordHash) {
+		log.Printf("[MUTATION] Login failed - Invalid password for user: %s", email)
+		return nil, fmt.Errorf("invalid credentials")
+	}
+
+	return dbUser, nil
+}
+
+// newSession describes a session for the client logging in, so it shows up
+// among the user's active devices
+func newSession(ctx context.Context, userID uuid.UUID, now, expiresAt time.Time) *models.Session {
+	client := ClientInfoForContext(ctx)
+	return &models.Session{
+		ID:         uuid.New().String(),
+		UserID:     userID,
+		ExpiresAt:  expiresAt,
+		CreatedAt:  now,
+		UserAgent:  client.UserAgent,
+		IPAddress:  client.IPAddress,
+		LastSeenAt: now,
+	}
+}
+
+// signAccessToken signs a JWT for user whose jti is the session it belongs to
+func (r *Resolver) signAccessToken(user *models.User, sessionID string, now, expiresAt time.Time) (string, error) {
+	claims := auth.CustomClaims{
+		UserID: user.ID.String(),
+		Role:   user.Role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        sessionID,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    "muse-backend",
+		},
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(r.config.JWTSecret))
+	if err != nil {
+		return "", fmt.Errorf("could not sign token: %w", err)
+	}
+	return signed, nil
+}
+
+// loginWithRefreshToken starts a renewable session, returning a short-lived
+// access token and the refresh token that renews it. Unlike Login the
+// session is required, since the refresh token is stored with it.
+func (r *Resolver) loginWithRefreshToken(ctx context.Context, email, password string) (*model.AuthPayload, error) {
+	dbUser, err := r.verifyCredentials(ctx, email, password)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := auth.NewRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := newSession(ctx, dbUser.ID, now, now.Add(refreshTokenTTL))
+	if err := r.repos.Session.Create(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	if err := r.repos.Session.StoreRefreshToken(ctx, auth.HashRefreshToken(refreshToken), session.ID, session.ExpiresAt); err != nil {
+		// Don't leave a session behind that no token can renew
+		if deleteErr := r.repos.Session.Delete(ctx, session.ID); deleteErr != nil {
+			log.Printf("[AUTH] Warning: Failed to delete session %s after refresh token failure: %v", session.ID, deleteErr)
+		}
+		return nil, fmt.Errorf("failed to store refresh token: %w", err)
+	}
+
+	accessToken, err := r.signAccessToken(dbUser, session.ID, now, now.Add(accessTokenTTL))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.repos.User.UpdateLastLogin(ctx, dbUser.ID); err != nil {
+		log.Printf("[MUTATION] Login warning - Failed to record last login for user %s: %v", dbUser.ID, err)
+	}
+
+	return &model.AuthPayload{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+// refreshTokens exchanges a refresh token for a new access token and a new
+// refresh token. The old refresh token is spent, so presenting it again
+// fails, and the session is extended to the new token's expiry.
+func (r *Resolver) refreshTokens(ctx context.Context, refreshToken string) (*model.AuthPayload, error) {
+	next, err := auth.NewRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(refreshTokenTTL)
+	sessionID, err := r.repos.Session.RotateRefreshToken(ctx, auth.HashRefreshToken(refreshToken), auth.HashRefreshToken(next), expiresAt)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errInvalidRefreshToken
+		}
+		return nil, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+
+	// A session ended by logout or account deletion takes its tokens with it
+	if err := r.repos.Session.Refresh(ctx, sessionID, expiresAt); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errInvalidRefreshToken
+		}
+		return nil, fmt.Errorf("failed to extend session: %w", err)
+	}
+	session, err := r.repos.Session.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	dbUser, err := r.repos.User.GetByID(ctx, session.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errInvalidRefreshToken
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	accessToken, err := r.signAccessToken(dbUser, sessionID, now, now.Add(accessTokenTTL))
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.AuthPayload{AccessToken: accessToken, RefreshToken: next}, nil
+}
@@ -0,0 +1,188 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/daedal00/muse/backend/auth"
+	"github.com/daedal00/muse/backend/internal/config"
+	"github.com/daedal00/muse/backend/internal/models"
+	"github.com/daedal00/muse/backend/internal/repository"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// refreshSessions keeps sessions and refresh token hashes in memory
+type refreshSessions struct {
+	repository.SessionRepository
+	sessions map[string]*models.Session
+	tokens   map[string]string // token hash -> session ID
+}
+
+func (s *refreshSessions) GetByID(ctx context.Context, id string) (*models.Session, error) {
+	session, ok := s.sessions[id]
+	if !ok {
+		return nil, fmt.Errorf("session %w", repository.ErrNotFound)
+	}
+	return session, nil
+}
+
+func (s *refreshSessions) Refresh(ctx context.Context, id string, newExpiry time.Time) error {
+	session, ok := s.sessions[id]
+	if !ok {
+		return fmt.Errorf("session %w", repository.ErrNotFound)
+	}
+	session.ExpiresAt = newExpiry
+	return nil
+}
+
+func (s *refreshSessions) RotateRefreshToken(ctx context.Context, oldHash, newHash string, expiresAt time.Time) (string, error) {
+	sessionID, ok := s.tokens[oldHash]
+	if !ok {
+		return "", fmt.Errorf("refresh token %w", repository.ErrNotFound)
+	}
+	delete(s.tokens, oldHash)
+	s.tokens[newHash] = sessionID
+	return sessionID, nil
+}
+
+func (s *refreshSessions) Create(ctx context.Context, session *models.Session) error {
+	s.sessions[session.ID] = session
+	return nil
+}
+
+func (s *refreshSessions) Delete(ctx context.Context, id string) error {
+	delete(s.sessions, id)
+	return nil
+}
+
+// unavailableSessions fails to create sessions
+type unavailableSessions struct {
+	*refreshSessions
+}
+
+func (s unavailableSessions) Create(ctx context.Context, session *models.Session) error {
+	return fmt.Errorf("connection refused")
+}
+
+// unstoredTokenSessions fails to store refresh tokens
+type unstoredTokenSessions struct {
+	*refreshSessions
+}
+
+func (s unstoredTokenSessions) StoreRefreshToken(ctx context.Context, tokenHash, sessionID string, expiresAt time.Time) error {
+	return fmt.Errorf("connection refused")
+}
+
+type refreshUsers struct {
+	repository.UserRepository
+	user *models.User
+}
+
+func (u refreshUsers) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	if id != u.user.ID {
+		return nil, fmt.Errorf("user %w", repository.ErrNotFound)
+	}
+	return u.user, nil
+}
+
+func (u refreshUsers) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	if email != u.user.Email {
+		return nil, fmt.Errorf("user %w", repository.ErrNotFound)
+	}
+	return u.user, nil
+}
+
+func newRefreshResolver(t *testing.T) (*Resolver, *refreshSessions, string) {
+	t.Helper()
+
+	user := &models.User{ID: uuid.New(), Role: models.RoleAdmin}
+	session := &models.Session{ID: uuid.New().String(), UserID: user.ID, ExpiresAt: time.Now().Add(time.Minute)}
+
+	token, err := auth.NewRefreshToken()
+	require.NoError(t, err)
+
+	sessions := &refreshSessions{
+		sessions: map[string]*models.Session{session.ID: session},
+		tokens:   map[string]string{auth.HashRefreshToken(token): session.ID},
+	}
+	resolver := &Resolver{
+		repos:  &repository.Repositories{User: refreshUsers{user: user}, Session: sessions},
+		config: &config.Config{JWTSecret: "test-secret"},
+	}
+	return resolver, sessions, token
+}
+
+func TestResolverRefreshTokens(t *testing.T) {
+	resolver, sessions, token := newRefreshResolver(t)
+
+	payload, err := resolver.refreshTokens(context.Background(), token)
+	require.NoError(t, err)
+	assert.NotEqual(t, token, payload.RefreshToken)
+
+	// The access token carries the session and the user's role
+	claims := &auth.CustomClaims{}
+	_, err = jwt.ParseWithClaims(payload.AccessToken, claims, func(*jwt.Token) (interface{}, error) {
+		return []byte("test-secret"), nil
+	})
+	require.NoError(t, err)
+	sessionID := sessions.tokens[auth.HashRefreshToken(payload.RefreshToken)]
+	assert.Equal(t, sessionID, claims.ID)
+	assert.Equal(t, models.RoleAdmin, claims.Role)
+	assert.WithinDuration(t, time.Now().Add(accessTokenTTL), claims.ExpiresAt.Time, time.Minute)
+
+	// The session is extended along with the new refresh token
+	assert.WithinDuration(t, time.Now().Add(refreshTokenTTL), sessions.sessions[sessionID].ExpiresAt, time.Minute)
+}
+
+func TestResolverRefreshTokens_ReuseIsRejected(t *testing.T) {
+	resolver, _, token := newRefreshResolver(t)
+
+	_, err := resolver.refreshTokens(context.Background(), token)
+	require.NoError(t, err)
+
+	_, err = resolver.refreshTokens(context.Background(), token)
+	assert.ErrorIs(t, err, auth.ErrUnauthenticated)
+}
+
+func TestResolverRefreshTokens_EndedSessionIsRejected(t *testing.T) {
+	resolver, sessions, token := newRefreshResolver(t)
+	clear(sessions.sessions)
+
+	_, err := resolver.refreshTokens(context.Background(), token)
+	assert.ErrorIs(t, err, auth.ErrUnauthenticated)
+}
+
+func TestResolverLoginWithRefreshToken_TokenStoreFailureEndsSession(t *testing.T) {
+	hash, err := auth.HashPassword("Secret123")
+	require.NoError(t, err)
+	user := &models.User{ID: uuid.New(), Email: "listener@example.com", PasswordHash: hash}
+	sessions := &refreshSessions{sessions: map[string]*models.Session{}, tokens: map[string]string{}}
+	resolver := &Resolver{
+		repos:  &repository.Repositories{User: refreshUsers{user: user}, Session: unstoredTokenSessions{sessions}},
+		config: &config.Config{JWTSecret: "test-secret"},
+	}
+
+	_, err = resolver.loginWithRefreshToken(context.Background(), user.Email, "Secret123")
+	require.Error(t, err)
+	assert.Empty(t, sessions.sessions)
+}
+
+func TestResolverLogin_SessionStoreFailureFailsLogin(t *testing.T) {
+	hash, err := auth.HashPassword("Secret123")
+	require.NoError(t, err)
+	user := &models.User{ID: uuid.New(), Email: "listener@example.com", PasswordHash: hash}
+	sessions := &refreshSessions{sessions: map[string]*models.Session{}, tokens: map[string]string{}}
+	resolver := &Resolver{
+		repos:  &repository.Repositories{User: refreshUsers{user: user}, Session: unavailableSessions{sessions}},
+		config: &config.Config{JWTSecret: "test-secret"},
+	}
+
+	token, err := resolver.Mutation().Login(context.Background(), user.Email, "Secret123")
+	require.Error(t, err)
+	assert.Empty(t, token)
+}
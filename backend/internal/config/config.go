@@ -2,8 +2,11 @@ package config
 
 import (
 	"fmt"
+	"net/netip"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -13,18 +16,50 @@ type Config struct {
 	Port        string
 	Environment string
 
+	// Maximum GraphQL query complexity; list fields count once per item
+	GraphQLComplexityLimit int
+
+	// Requests per minute allowed from one client IP on /query; 0 disables
+	RateLimitRequestsPerMinute int
+
+	// Proxies whose X-Forwarded-For header is trusted to name the client;
+	// requests from any other peer are identified by their own address
+	TrustedProxies []netip.Prefix
+
 	// Spotify
 	SpotifyClientID     string
 	SpotifyClientSecret string
 
+	// Outbound HTTP connection pool shared by all Spotify clients
+	SpotifyHTTPMaxIdleConns        int
+	SpotifyHTTPMaxIdleConnsPerHost int
+	SpotifyHTTPIdleConnTimeout     time.Duration
+
+	// Times a Spotify request rejected with 429 is retried; 0 disables
+	SpotifyMaxRateLimitRetries int
+
 	// Database
-	DatabaseURL string
-	DBHost      string
-	DBPort      int
-	DBName      string
-	DBUser      string
-	DBPassword  string
-	DBSSLMode   string
+	DatabaseURL      string
+	DBReadReplicaURL string
+	DBHost           string
+	DBPort           int
+	DBName           string
+	DBUser           string
+	DBPassword       string
+	DBSSLMode        string
+
+	// Longest a database statement may run; 0 disables the limit
+	DBStatementTimeout time.Duration
+
+	// Directory the migrate tool reads migrations from; empty uses the
+	// migrations embedded in the binary
+	MigrationsPath string
+
+	// Database connection pool tuning
+	DBMaxConns        int
+	DBMinConns        int
+	DBMaxConnLifetime time.Duration
+	DBMaxConnIdleTime time.Duration
 
 	// Redis
 	RedisURL      string
@@ -35,6 +70,15 @@ type Config struct {
 
 	// JWT
 	JWTSecret string
+
+	// Background job intervals; 0 disables a job
+	SessionCleanupInterval time.Duration
+	CacheWarmInterval      time.Duration
+
+	// Password policy
+	PasswordMinLength        int
+	PasswordRequireMixedCase bool
+	PasswordRequireDigit     bool
 }
 
 func Load() (*Config, error) {
@@ -68,20 +112,42 @@ func Load() (*Config, error) {
 		}
 	}
 
+	trustedProxies, err := getEnvAsPrefixes("TRUSTED_PROXIES")
+	if err != nil {
+		return nil, err
+	}
+
 	config := &Config{
 		Port:        getEnv("PORT", "8080"),
 		Environment: getEnv("ENVIRONMENT", "development"),
 
+		GraphQLComplexityLimit:     getEnvAsInt("GRAPHQL_COMPLEXITY_LIMIT", 1000),
+		RateLimitRequestsPerMinute: getEnvAsInt("RATE_LIMIT_REQUESTS_PER_MINUTE", 120),
+		TrustedProxies:             trustedProxies,
+
 		SpotifyClientID:     os.Getenv("SPOTIFY_CLIENT_ID"),
 		SpotifyClientSecret: os.Getenv("SPOTIFY_CLIENT_SECRET"),
 
-		DatabaseURL: os.Getenv("DATABASE_URL"),
-		DBHost:      getEnv("DB_HOST", "localhost"),
-		DBPort:      getEnvAsInt("DB_PORT", 5432),
-		DBName:      getEnv("DB_NAME", "muse"),
-		DBUser:      getEnv("DB_USER", "postgres"),
-		DBPassword:  os.Getenv("DB_PASSWORD"),
-		DBSSLMode:   getEnv("DB_SSL_MODE", "prefer"),
+		SpotifyHTTPMaxIdleConns:        getEnvAsInt("SPOTIFY_HTTP_MAX_IDLE_CONNS", 100),
+		SpotifyHTTPMaxIdleConnsPerHost: getEnvAsInt("SPOTIFY_HTTP_MAX_IDLE_CONNS_PER_HOST", 20),
+		SpotifyHTTPIdleConnTimeout:     getEnvAsDuration("SPOTIFY_HTTP_IDLE_CONN_TIMEOUT", 90*time.Second),
+		SpotifyMaxRateLimitRetries:     getEnvAsInt("SPOTIFY_MAX_RATE_LIMIT_RETRIES", 3),
+
+		DatabaseURL:      os.Getenv("DATABASE_URL"),
+		DBReadReplicaURL: os.Getenv("DATABASE_READ_REPLICA_URL"),
+		DBHost:           getEnv("DB_HOST", "localhost"),
+		DBPort:           getEnvAsInt("DB_PORT", 5432),
+		DBName:           getEnv("DB_NAME", "muse"),
+		DBUser:           getEnv("DB_USER", "postgres"),
+		DBPassword:       os.Getenv("DB_PASSWORD"),
+		DBSSLMode:        getEnv("DB_SSL_MODE", "prefer"),
+
+		DBStatementTimeout: getEnvAsDuration("DB_STATEMENT_TIMEOUT", 5*time.Second),
+		DBMaxConns:         getEnvAsInt("DB_MAX_CONNS", 30),
+		DBMinConns:         getEnvAsInt("DB_MIN_CONNS", 5),
+		DBMaxConnLifetime:  getEnvAsDuration("DB_MAX_CONN_LIFETIME", time.Hour),
+		DBMaxConnIdleTime:  getEnvAsDuration("DB_MAX_CONN_IDLE_TIME", 30*time.Minute),
+		MigrationsPath:     os.Getenv("MIGRATIONS_PATH"),
 
 		RedisURL:      redisURL,
 		RedisHost:     getEnv("REDIS_HOST", "localhost"),
@@ -90,6 +156,13 @@ func Load() (*Config, error) {
 		RedisDB:       getEnvAsInt("REDIS_DB", 0),
 
 		JWTSecret: getEnv("JWT_SECRET", "your-fallback-secret-key"),
+
+		SessionCleanupInterval: getEnvAsDuration("SESSION_CLEANUP_INTERVAL", time.Hour),
+		CacheWarmInterval:      getEnvAsDuration("CACHE_WARM_INTERVAL", 15*time.Minute),
+
+		PasswordMinLength:        getEnvAsInt("PASSWORD_MIN_LENGTH", 8),
+		PasswordRequireMixedCase: getEnvAsBool("PASSWORD_REQUIRE_MIXED_CASE", true),
+		PasswordRequireDigit:     getEnvAsBool("PASSWORD_REQUIRE_DIGIT", true),
 	}
 
 	if err := config.Validate(); err != nil {
@@ -109,6 +182,9 @@ func (c *Config) Validate() error {
 	if c.DatabaseURL == "" && c.DBPassword == "" {
 		return fmt.Errorf("either DATABASE_URL or DB_PASSWORD must be provided")
 	}
+	if c.GraphQLComplexityLimit <= 0 {
+		return fmt.Errorf("GRAPHQL_COMPLEXITY_LIMIT must be positive")
+	}
 	return nil
 }
 
@@ -135,3 +211,43 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
+
+// getEnvAsPrefixes parses a comma-separated list of IP addresses and CIDR
+// ranges, treating a bare address as a single-host range
+func getEnvAsPrefixes(key string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, entry := range strings.Split(os.Getenv(key), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if addr, err := netip.ParseAddr(entry); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid address or CIDR range %q", key, entry)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
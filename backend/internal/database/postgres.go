@@ -3,32 +3,96 @@ package database
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// DefaultStatementTimeout bounds database statements when no other timeout
+// is configured
+const DefaultStatementTimeout = 5 * time.Second
+
+// Pool sizing used for any PostgresOptions field left at zero
+const (
+	DefaultMaxConns        = 30
+	DefaultMinConns        = 5
+	DefaultMaxConnLifetime = time.Hour
+	DefaultMaxConnIdleTime = 30 * time.Minute
+)
+
 type PostgresDB struct {
 	Pool *pgxpool.Pool
+
+	// ReadPool points at a read replica when one is configured; read-only
+	// queries should go through Reader() rather than using it directly
+	ReadPool *pgxpool.Pool
+
+	// StatementTimeout is the longest a statement may run. The server
+	// enforces it on every connection; repositories also apply it as a
+	// context deadline to callers whose context has none.
+	StatementTimeout time.Duration
+}
+
+// PostgresOptions configures NewPostgresConnectionWithOptions
+type PostgresOptions struct {
+	// ReplicaURL, if non-empty, is a read replica used for read-only queries
+	ReplicaURL string
+
+	// StatementTimeout bounds every statement; zero or negative disables it
+	StatementTimeout time.Duration
+
+	// Pool sizing and connection recycling; zero uses the package defaults
+	MaxConns        int32
+	MinConns        int32
+	MaxConnLifetime time.Duration
+	MaxConnIdleTime time.Duration
 }
 
 func NewPostgresConnection(databaseURL string) (*PostgresDB, error) {
+	return NewPostgresConnectionWithOptions(databaseURL, PostgresOptions{StatementTimeout: DefaultStatementTimeout})
+}
+
+// NewPostgresConnectionWithReplica connects to the primary database and, if
+// replicaURL is non-empty, to a read replica used for read-only queries
+func NewPostgresConnectionWithReplica(databaseURL, replicaURL string) (*PostgresDB, error) {
+	return NewPostgresConnectionWithOptions(databaseURL, PostgresOptions{
+		ReplicaURL:       replicaURL,
+		StatementTimeout: DefaultStatementTimeout,
+	})
+}
+
+// NewPostgresConnectionWithOptions connects to the primary database, and to
+// a read replica if opts names one
+func NewPostgresConnectionWithOptions(databaseURL string, opts PostgresOptions) (*PostgresDB, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	// Configure the connection pool
-	config, err := pgxpool.ParseConfig(databaseURL)
+	pool, err := newPool(ctx, databaseURL, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse database URL: %w", err)
+		return nil, err
 	}
 
-	// Set pool configuration for optimal performance
-	config.MaxConns = 30
-	config.MinConns = 5
-	config.MaxConnLifetime = time.Hour
-	config.MaxConnIdleTime = time.Minute * 30
-	config.HealthCheckPeriod = time.Minute
+	db := &PostgresDB{Pool: pool, StatementTimeout: max(opts.StatementTimeout, 0)}
+
+	if opts.ReplicaURL != "" {
+		readPool, err := newPool(ctx, opts.ReplicaURL, opts)
+		if err != nil {
+			pool.Close()
+			return nil, fmt.Errorf("read replica: %w", err)
+		}
+		db.ReadPool = readPool
+	}
+
+	return db, nil
+}
+
+func newPool(ctx context.Context, databaseURL string, opts PostgresOptions) (*pgxpool.Pool, error) {
+	config, err := newPoolConfig(databaseURL, opts)
+	if err != nil {
+		return nil, err
+	}
 
 	// Create the connection pool
 	pool, err := pgxpool.NewWithConfig(ctx, config)
@@ -42,10 +106,59 @@ func NewPostgresConnection(databaseURL string) (*PostgresDB, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	return &PostgresDB{Pool: pool}, nil
+	return pool, nil
+}
+
+// newPoolConfig parses databaseURL and applies opts to the resulting pool
+// configuration
+func newPoolConfig(databaseURL string, opts PostgresOptions) (*pgxpool.Config, error) {
+	// Configure the connection pool
+	config, err := pgxpool.ParseConfig(databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse database URL: %w", err)
+	}
+
+	// Set pool configuration for optimal performance
+	config.MaxConns = orDefault(opts.MaxConns, DefaultMaxConns)
+	config.MinConns = min(orDefault(opts.MinConns, DefaultMinConns), config.MaxConns)
+	config.MaxConnLifetime = orDefault(opts.MaxConnLifetime, DefaultMaxConnLifetime)
+	config.MaxConnIdleTime = orDefault(opts.MaxConnIdleTime, DefaultMaxConnIdleTime)
+	config.HealthCheckPeriod = time.Minute
+
+	// A statement_timeout given in the URL takes precedence
+	if _, ok := config.ConnConfig.RuntimeParams["statement_timeout"]; !ok && opts.StatementTimeout > 0 {
+		config.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(opts.StatementTimeout.Milliseconds(), 10)
+	}
+
+	return config, nil
+}
+
+// orDefault returns v, or def if v is not positive
+func orDefault[T int32 | time.Duration](v, def T) T {
+	if v > 0 {
+		return v
+	}
+	return def
+}
+
+// Reader returns the pool to use for read-only queries: the read replica when
+// configured, otherwise the primary
+func (db *PostgresDB) Reader() *pgxpool.Pool {
+	if db.ReadPool != nil {
+		return db.ReadPool
+	}
+	return db.Pool
+}
+
+// Stats returns a snapshot of the primary pool's connection usage
+func (db *PostgresDB) Stats() *pgxpool.Stat {
+	return db.Pool.Stat()
 }
 
 func (db *PostgresDB) Close() {
+	if db.ReadPool != nil {
+		db.ReadPool.Close()
+	}
 	if db.Pool != nil {
 		db.Pool.Close()
 	}
@@ -54,7 +167,16 @@ func (db *PostgresDB) Close() {
 func (db *PostgresDB) Health() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	return db.Pool.Ping(ctx)
+
+	if err := db.Pool.Ping(ctx); err != nil {
+		return err
+	}
+	if db.ReadPool != nil {
+		if err := db.ReadPool.Ping(ctx); err != nil {
+			return fmt.Errorf("read replica: %w", err)
+		}
+	}
+	return nil
 }
 
 // Transaction helper
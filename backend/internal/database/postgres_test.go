@@ -0,0 +1,113 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// newLazyPool creates a pool without opening any connections, which is enough
+// to check which pool queries are routed to
+func newLazyPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	pool, err := pgxpool.New(context.Background(), "postgres://test@127.0.0.1:1/test")
+	if err != nil {
+		t.Fatalf("Failed to create pool: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	return pool
+}
+
+func TestPostgresDB_Reader_UsesReplicaWhenConfigured(t *testing.T) {
+	primary := newLazyPool(t)
+	replica := newLazyPool(t)
+
+	db := &PostgresDB{Pool: primary, ReadPool: replica}
+
+	if db.Reader() != replica {
+		t.Error("Expected reads to be routed to the replica pool")
+	}
+}
+
+func TestPostgresDB_Reader_FallsBackToPrimary(t *testing.T) {
+	primary := newLazyPool(t)
+
+	db := &PostgresDB{Pool: primary}
+
+	if db.Reader() != primary {
+		t.Error("Expected reads to fall back to the primary pool")
+	}
+}
+
+func TestNewPoolConfig_StatementTimeout(t *testing.T) {
+	config, err := newPoolConfig("postgres://test@127.0.0.1:1/test", PostgresOptions{StatementTimeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to build pool config: %v", err)
+	}
+	if got := config.ConnConfig.RuntimeParams["statement_timeout"]; got != "5000" {
+		t.Errorf("Expected statement_timeout 5000, got %q", got)
+	}
+
+	// A timeout set in the URL is left alone
+	config, err = newPoolConfig("postgres://test@127.0.0.1:1/test?statement_timeout=100", PostgresOptions{StatementTimeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to build pool config: %v", err)
+	}
+	if got := config.ConnConfig.RuntimeParams["statement_timeout"]; got != "100" {
+		t.Errorf("Expected statement_timeout from URL, got %q", got)
+	}
+
+	config, err = newPoolConfig("postgres://test@127.0.0.1:1/test", PostgresOptions{})
+	if err != nil {
+		t.Fatalf("Failed to build pool config: %v", err)
+	}
+	if _, ok := config.ConnConfig.RuntimeParams["statement_timeout"]; ok {
+		t.Error("Expected no statement_timeout when disabled")
+	}
+}
+
+func TestNewPoolConfig_PoolTuning(t *testing.T) {
+	config, err := newPoolConfig("postgres://test@127.0.0.1:1/test", PostgresOptions{
+		MaxConns:        12,
+		MinConns:        3,
+		MaxConnLifetime: 10 * time.Minute,
+		MaxConnIdleTime: 2 * time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("Failed to build pool config: %v", err)
+	}
+	if config.MaxConns != 12 || config.MinConns != 3 {
+		t.Errorf("Expected pool size (12, 3), got (%d, %d)", config.MaxConns, config.MinConns)
+	}
+	if config.MaxConnLifetime != 10*time.Minute || config.MaxConnIdleTime != 2*time.Minute {
+		t.Errorf("Expected connection lifetimes (10m, 2m), got (%s, %s)", config.MaxConnLifetime, config.MaxConnIdleTime)
+	}
+
+	// Unset fields fall back to the defaults, and MinConns never exceeds MaxConns
+	config, err = newPoolConfig("postgres://test@127.0.0.1:1/test", PostgresOptions{MaxConns: 2})
+	if err != nil {
+		t.Fatalf("Failed to build pool config: %v", err)
+	}
+	if config.MaxConns != 2 || config.MinConns != 2 {
+		t.Errorf("Expected pool size (2, 2), got (%d, %d)", config.MaxConns, config.MinConns)
+	}
+	if config.MaxConnLifetime != DefaultMaxConnLifetime || config.MaxConnIdleTime != DefaultMaxConnIdleTime {
+		t.Errorf("Expected default connection lifetimes, got (%s, %s)", config.MaxConnLifetime, config.MaxConnIdleTime)
+	}
+}
+
+func TestPostgresDB_Stats(t *testing.T) {
+	db := &PostgresDB{Pool: newLazyPool(t)}
+
+	stats := db.Stats()
+	if stats == nil {
+		t.Fatal("Expected pool stats")
+	}
+	if stats.MaxConns() <= 0 {
+		t.Errorf("Expected a positive max connection count, got %d", stats.MaxConns())
+	}
+}
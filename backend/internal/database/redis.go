@@ -3,8 +3,10 @@ package database
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -59,6 +61,55 @@ func (r *RedisClient) Exists(ctx context.Context, keys ...string) (int64, error)
 	return r.Client.Exists(ctx, keys...).Result()
 }
 
+// Rate limiting
+
+// slidingWindowScript drops hits older than ARGV[2] from the window in
+// KEYS[1] and, if fewer than ARGV[4] remain, records a hit at ARGV[1] named
+// ARGV[5] and extends the window's TTL to ARGV[3] ms. It returns {1} for an
+// allowed hit, or {0, oldest score} when the window is full.
+var slidingWindowScript = redis.NewScript(`
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", "(" .. ARGV[2])
+if redis.call("ZCARD", KEYS[1]) < tonumber(ARGV[4]) then
+	redis.call("ZADD", KEYS[1], ARGV[1], ARGV[5])
+	redis.call("PEXPIRE", KEYS[1], ARGV[3])
+	return {1}
+end
+local oldest = redis.call("ZRANGE", KEYS[1], 0, 0, "WITHSCORES")
+return {0, oldest[2]}
+`)
+
+// AllowSlidingWindow reports whether a hit against key is within limit hits
+// over the trailing window, recording it only if it is. Rejected hits are not
+// counted, so a client is let back in as soon as its earlier hits age out.
+// When the hit is rejected the returned duration is how long until the
+// oldest hit in the window expires.
+func (r *RedisClient) AllowSlidingWindow(ctx context.Context, key string, limit int, window time.Duration) (bool, time.Duration, error) {
+	// Scores are microseconds, which float64 still holds exactly
+	now := time.Now()
+	windowStart := now.Add(-window)
+
+	result, err := slidingWindowScript.Run(ctx, r.Client, []string{key},
+		now.UnixMicro(), windowStart.UnixMicro(), window.Milliseconds(), limit, uuid.NewString(),
+	).Slice()
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to record rate limit hit: %w", err)
+	}
+
+	if allowed, _ := result[0].(int64); allowed == 1 {
+		return true, 0, nil
+	}
+
+	retryAfter := window
+	if len(result) > 1 {
+		if score, ok := result[1].(string); ok {
+			if micros, err := strconv.ParseFloat(score, 64); err == nil {
+				retryAfter = time.UnixMicro(int64(micros)).Add(window).Sub(now)
+			}
+		}
+	}
+	return false, retryAfter, nil
+}
+
 // Session-specific operations
 func (r *RedisClient) SetSession(ctx context.Context, sessionID string, userID string, expiration time.Duration) error {
 	return r.Set(ctx, fmt.Sprintf("session:%s", sessionID), userID, expiration)
@@ -0,0 +1,100 @@
+// Package jobs runs periodic background work such as cache warming and
+// cleanup alongside the server.
+package jobs
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Locker hands out locks shared by every server instance, such as the Redis
+// lock in the repository layer
+type Locker interface {
+	Acquire(ctx context.Context, key string, ttl time.Duration) (func(), bool, error)
+}
+
+// Job is a unit of work run every Interval
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler runs registered jobs on their own tickers until stopped
+type Scheduler struct {
+	locker Locker
+	jobs   []Job
+}
+
+// NewScheduler returns a scheduler that takes a lock named after each job
+// before running it, so each job runs on one instance per interval. A nil
+// locker runs jobs unguarded.
+func NewScheduler(locker Locker) *Scheduler {
+	return &Scheduler{locker: locker}
+}
+
+// Register adds a job. Jobs with a non-positive interval are disabled.
+func (s *Scheduler) Register(job Job) {
+	if job.Interval <= 0 {
+		log.Printf("[JOBS] %s is disabled", job.Name)
+		return
+	}
+	s.jobs = append(s.jobs, job)
+}
+
+// Run starts every job and blocks until ctx is cancelled and any runs in
+// progress have returned
+func (s *Scheduler) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, job := range s.jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.loop(ctx, job)
+		}()
+	}
+	wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	log.Printf("[JOBS] Scheduling %s every %s", job.Name, job.Interval)
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.runOnce(ctx, job)
+		}
+	}
+}
+
+// runOnce runs a job unless another instance has run it this interval.
+// Failures are logged and the job is tried again on the next tick.
+func (s *Scheduler) runOnce(ctx context.Context, job Job) {
+	if s.locker != nil {
+		// The lock is left to expire after one interval rather than released
+		// when the run finishes, so instances whose tickers fire later in the
+		// same interval skip the job instead of repeating it
+		_, ok, err := s.locker.Acquire(ctx, "job:"+job.Name, job.Interval)
+		if err != nil {
+			log.Printf("[JOBS] Warning: Failed to lock %s: %v", job.Name, err)
+			return
+		}
+		if !ok {
+			return
+		}
+	}
+
+	start := time.Now()
+	if err := job.Run(ctx); err != nil {
+		log.Printf("[JOBS] %s failed after %s: %v", job.Name, time.Since(start), err)
+		return
+	}
+	log.Printf("[JOBS] %s completed in %s", job.Name, time.Since(start))
+}
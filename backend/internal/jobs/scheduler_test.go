@@ -0,0 +1,132 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// heldLocker reports every lock as held by another instance
+type heldLocker struct{}
+
+func (heldLocker) Acquire(ctx context.Context, key string, ttl time.Duration) (func(), bool, error) {
+	return nil, false, nil
+}
+
+// memoryLocker is a lock store shared by schedulers standing in for separate
+// instances; locks are held until their TTL passes or they are released
+type memoryLocker struct {
+	mu       sync.Mutex
+	expiries map[string]time.Time
+	releases int
+}
+
+func (l *memoryLocker) Acquire(ctx context.Context, key string, ttl time.Duration) (func(), bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if time.Now().Before(l.expiries[key]) {
+		return nil, false, nil
+	}
+	l.expiries[key] = time.Now().Add(ttl)
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.releases++
+		delete(l.expiries, key)
+	}, true, nil
+}
+
+// runScheduler runs s in the background, returning a channel closed once Run
+// has returned
+func runScheduler(ctx context.Context, s *Scheduler) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.Run(ctx)
+	}()
+	return done
+}
+
+func TestScheduler_RunsJobUntilCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ran := make(chan struct{}, 1)
+	scheduler := NewScheduler(nil)
+	scheduler.Register(Job{
+		Name:     "test",
+		Interval: 10 * time.Millisecond,
+		Run: func(ctx context.Context) error {
+			select {
+			case ran <- struct{}{}:
+			default:
+			}
+			return nil
+		},
+	})
+	done := runScheduler(ctx, scheduler)
+
+	select {
+	case <-ran:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the job to run within a second")
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the scheduler to stop after cancellation")
+	}
+}
+
+func TestScheduler_SkipsJobLockedElsewhere(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	var runs atomic.Int32
+	scheduler := NewScheduler(heldLocker{})
+	scheduler.Register(Job{
+		Name:     "test",
+		Interval: 10 * time.Millisecond,
+		Run: func(ctx context.Context) error {
+			runs.Add(1)
+			return nil
+		},
+	})
+	<-runScheduler(ctx, scheduler)
+
+	assert.Zero(t, runs.Load())
+}
+
+func TestScheduler_RunsJobOncePerIntervalAcrossInstances(t *testing.T) {
+	locker := &memoryLocker{expiries: make(map[string]time.Time)}
+	var runs atomic.Int32
+	job := Job{
+		Name:     "test",
+		Interval: time.Minute,
+		Run: func(ctx context.Context) error {
+			runs.Add(1)
+			return nil
+		},
+	}
+
+	// A second instance ticking after the first run finished still skips it
+	NewScheduler(locker).runOnce(context.Background(), job)
+	NewScheduler(locker).runOnce(context.Background(), job)
+
+	assert.Equal(t, int32(1), runs.Load())
+	assert.Zero(t, locker.releases)
+}
+
+func TestScheduler_DisabledJob(t *testing.T) {
+	scheduler := NewScheduler(nil)
+	scheduler.Register(Job{Name: "disabled", Interval: 0, Run: func(ctx context.Context) error { return nil }})
+
+	require.Empty(t, scheduler.jobs)
+}
@@ -0,0 +1,74 @@
+// Package logging provides the structured JSON logger and the request-scoped
+// context the HTTP middleware attaches to every request.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+)
+
+// unexported type to avoid collisions with other packages
+type ctxKey string
+
+const (
+	requestIDKey ctxKey = "requestID"
+	attrsKey     ctxKey = "requestAttrs"
+)
+
+// NewLogger returns a logger writing one JSON object per line to w
+func NewLogger(w io.Writer) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(w, nil))
+}
+
+// WithRequestID returns a copy of ctx carrying the request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestID returns the request ID stored in ctx, or "" outside a request
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// FromContext returns the default logger, tagged with the request ID when ctx
+// belongs to a request so lines logged while serving it can be correlated
+func FromContext(ctx context.Context) *slog.Logger {
+	logger := slog.Default()
+	if id := RequestID(ctx); id != "" {
+		logger = logger.With("request_id", id)
+	}
+	return logger
+}
+
+// requestAttrs collects attributes set by inner handlers for the request log line
+type requestAttrs struct {
+	mu    sync.Mutex
+	attrs []slog.Attr
+}
+
+// WithAttrCollector returns a copy of ctx that AddAttrs can record into, and a
+// function returning everything recorded so far
+func WithAttrCollector(ctx context.Context) (context.Context, func() []slog.Attr) {
+	collector := &requestAttrs{}
+	collected := func() []slog.Attr {
+		collector.mu.Lock()
+		defer collector.mu.Unlock()
+		return append([]slog.Attr(nil), collector.attrs...)
+	}
+	return context.WithValue(ctx, attrsKey, collector), collected
+}
+
+// AddAttrs records attributes on the request's log line. It is a no-op when
+// ctx has no collector, e.g. outside the logging middleware.
+func AddAttrs(ctx context.Context, attrs ...slog.Attr) {
+	collector, ok := ctx.Value(attrsKey).(*requestAttrs)
+	if !ok {
+		return
+	}
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	collector.attrs = append(collector.attrs, attrs...)
+}
@@ -0,0 +1,50 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromContext_TagsRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(NewLogger(&buf))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	ctx := WithRequestID(context.Background(), "req-42")
+	FromContext(ctx).Info("resolving field", "field", "album")
+
+	var line map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "resolving field", line["msg"])
+	assert.Equal(t, "req-42", line["request_id"])
+	assert.Equal(t, "album", line["field"])
+
+	// Outside a request there is no ID to attach
+	buf.Reset()
+	FromContext(context.Background()).Info("background work")
+	line = nil
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.NotContains(t, line, "request_id")
+}
+
+func TestAddAttrs(t *testing.T) {
+	ctx, collected := WithAttrCollector(context.Background())
+	AddAttrs(ctx, slog.String("auth_status", "authenticated"))
+	AddAttrs(ctx, slog.Int("attempt", 2))
+
+	attrs := collected()
+	require.Len(t, attrs, 2)
+	assert.Equal(t, "auth_status", attrs[0].Key)
+	assert.Equal(t, "authenticated", attrs[0].Value.String())
+	assert.Equal(t, int64(2), attrs[1].Value.Int64())
+
+	// Without a collector recording is a no-op
+	assert.NotPanics(t, func() { AddAttrs(context.Background(), slog.String("k", "v")) })
+}
@@ -8,14 +8,41 @@ import (
 
 // User represents a user in the system
 type User struct {
-	ID           uuid.UUID `json:"id" db:"id"`
-	Name         string    `json:"name" db:"name"`
-	Email        string    `json:"email" db:"email"`
-	PasswordHash string    `json:"-" db:"password_hash"`
-	Bio          *string   `json:"bio" db:"bio"`
-	Avatar       *string   `json:"avatar" db:"avatar"`
-	CreatedAt    time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+	ID           uuid.UUID  `json:"id" db:"id"`
+	Name         string     `json:"name" db:"name"`
+	Email        string     `json:"email" db:"email"`
+	PasswordHash string     `json:"-" db:"password_hash"`
+	Bio          *string    `json:"bio" db:"bio"`
+	Avatar       *string    `json:"avatar" db:"avatar"`
+	Country      *string    `json:"country" db:"country"`
+	Role         string     `json:"role" db:"role"`
+	LastActiveAt *time.Time `json:"last_active_at" db:"last_active_at"`
+	LastLoginAt  *time.Time `json:"last_login_at" db:"last_login_at"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// User roles, carried in JWT claims for authorization
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// UserPreferences holds a user's taste and notification/privacy settings
+type UserPreferences struct {
+	UserID               uuid.UUID       `json:"user_id" db:"user_id"`
+	PreferredGenres      []string        `json:"preferred_genres" db:"preferred_genres"`
+	FavoriteArtistIDs    []string        `json:"favorite_artist_ids" db:"favorite_artist_ids"`
+	NotificationSettings map[string]bool `json:"notification_settings" db:"notification_settings"`
+	PrivacySettings      map[string]bool `json:"privacy_settings" db:"privacy_settings"`
+	CreatedAt            time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// FollowCounts is how many users follow a user and how many they follow
+type FollowCounts struct {
+	Followers int `json:"followers"`
+	Following int `json:"following"`
 }
 
 // Artist represents a music artist
@@ -64,6 +91,7 @@ type Review struct {
 	AlbumID    uuid.UUID `json:"album_id" db:"album_id"`
 	Rating     int       `json:"rating" db:"rating"`
 	ReviewText *string   `json:"review_text" db:"review_text"`
+	HasSpoiler bool      `json:"has_spoiler" db:"has_spoiler"`
 	CreatedAt  time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
 
@@ -72,6 +100,104 @@ type Review struct {
 	Album *Album `json:"album,omitempty"`
 }
 
+// Review search event types
+const (
+	ReviewSearchEventCreated = "created"
+	ReviewSearchEventUpdated = "updated"
+	ReviewSearchEventDeleted = "deleted"
+)
+
+// ReviewSearchEvent records a review change that a search indexer has yet to apply
+type ReviewSearchEvent struct {
+	ID          int64      `json:"id" db:"id"`
+	ReviewID    uuid.UUID  `json:"review_id" db:"review_id"`
+	EventType   string     `json:"event_type" db:"event_type"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	ProcessedAt *time.Time `json:"processed_at" db:"processed_at"`
+}
+
+// RatingPoint is the average album rating within a single time bucket
+type RatingPoint struct {
+	BucketStart   time.Time `json:"bucket_start"`
+	AverageRating float64   `json:"average_rating"`
+	Count         int       `json:"count"`
+}
+
+// ReviewStats summarizes a group of reviews
+type ReviewStats struct {
+	AverageRating float64 `json:"average_rating"`
+	Count         int     `json:"count"`
+}
+
+// ItemReviewAggregate summarizes the reviews of one Spotify item
+type ItemReviewAggregate struct {
+	SpotifyID     string  `json:"spotify_id"`
+	Count         int     `json:"count"`
+	AverageRating float64 `json:"average_rating"`
+}
+
+// RatingDistribution is the number of reviews at each rating from 1 to 5
+type RatingDistribution struct {
+	Counts map[int]int `json:"counts"`
+	Total  int         `json:"total"`
+}
+
+// ReviewSummary is a page of an album's reviews together with its overall stats
+type ReviewSummary struct {
+	Reviews       []*Review   `json:"reviews"`
+	AverageRating float64     `json:"average_rating"`
+	Count         int         `json:"count"`
+	Distribution  map[int]int `json:"distribution"`
+}
+
+// AlbumPageData is everything the album page shows, assembled in one call
+type AlbumPageData struct {
+	Album        *Album         `json:"album"`
+	Tracks       []*Track       `json:"tracks"`
+	Reviews      *ReviewSummary `json:"reviews"`
+	ViewerReview *Review        `json:"viewer_review,omitempty"`
+}
+
+// SpotifyArtist is the Spotify artist metadata cached for search results
+type SpotifyArtist struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	ExternalURL string `json:"external_url,omitempty"`
+}
+
+// SpotifyAlbum is the Spotify album metadata cached for search results
+type SpotifyAlbum struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Artists     []SpotifyArtist `json:"artists"`
+	ReleaseDate string          `json:"release_date,omitempty"`
+	ImageURL    string          `json:"image_url,omitempty"`
+	ImageURLs   []string        `json:"image_urls,omitempty"`
+	ExternalURL string          `json:"external_url,omitempty"`
+}
+
+// SpotifyTrack is the Spotify track metadata cached for search results
+type SpotifyTrack struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Artists     []SpotifyArtist `json:"artists"`
+	Album       *SpotifyAlbum   `json:"album,omitempty"`
+	DurationMs  int             `json:"duration_ms"`
+	TrackNumber int             `json:"track_number"`
+	PreviewURL  string          `json:"preview_url,omitempty"`
+	ExternalURL string          `json:"external_url,omitempty"`
+}
+
+// CachedRecommendations is a generated set of Spotify track recommendations
+// for a user, together with the seeds they were generated from
+type CachedRecommendations struct {
+	UserID      uuid.UUID      `json:"user_id"`
+	Tracks      []SpotifyTrack `json:"tracks"`
+	SeedArtists []string       `json:"seed_artists"`
+	SeedGenres  []string       `json:"seed_genres"`
+	GeneratedAt time.Time      `json:"generated_at"`
+}
+
 // Playlist represents a user's playlist
 type Playlist struct {
 	ID          uuid.UUID `json:"id" db:"id"`
@@ -79,13 +205,36 @@ type Playlist struct {
 	Description *string   `json:"description" db:"description"`
 	CoverImage  *string   `json:"cover_image" db:"cover_image"`
 	CreatorID   uuid.UUID `json:"creator_id" db:"creator_id"`
+	IsPublic    bool      `json:"is_public" db:"is_public"`
+	Tags        []string  `json:"tags" db:"tags"`
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
 
+	// SpotifyPlaylistID is set on playlists imported from Spotify
+	SpotifyPlaylistID *string `json:"spotify_playlist_id" db:"spotify_playlist_id"`
+
 	// Relations
 	Creator *User `json:"creator,omitempty"`
 }
 
+// PlaylistAudioStats averages the Spotify audio features of a playlist's
+// tracks. TrackCount is how many tracks had features to average.
+type PlaylistAudioStats struct {
+	PlaylistID   uuid.UUID `json:"playlist_id"`
+	TrackCount   int       `json:"track_count"`
+	Energy       float64   `json:"energy"`
+	Danceability float64   `json:"danceability"`
+	Valence      float64   `json:"valence"`
+	Tempo        float64   `json:"tempo"`
+	ComputedAt   time.Time `json:"computed_at"`
+}
+
+// TagCount is the number of public playlists using a tag
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
 // PlaylistTrack represents the many-to-many relationship between playlists and tracks
 type PlaylistTrack struct {
 	ID         uuid.UUID `json:"id" db:"id"`
@@ -106,14 +255,21 @@ type Session struct {
 	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 
+	// Client the session was started from, for listing active devices
+	UserAgent  string    `json:"user_agent,omitempty" db:"user_agent"`
+	IPAddress  string    `json:"ip_address,omitempty" db:"ip_address"`
+	LastSeenAt time.Time `json:"last_seen_at" db:"last_seen_at"`
+
 	// Relations
 	User *User `json:"user,omitempty"`
 }
 
 // Pagination types
 type PageInfo struct {
-	EndCursor   *string `json:"end_cursor"`
-	HasNextPage bool    `json:"has_next_page"`
+	StartCursor     *string `json:"start_cursor"`
+	EndCursor       *string `json:"end_cursor"`
+	HasNextPage     bool    `json:"has_next_page"`
+	HasPreviousPage bool    `json:"has_previous_page"`
 }
 
 type Connection[T any] struct {
@@ -0,0 +1,51 @@
+package repository
+
+import "errors"
+
+// Sentinel errors returned by repository implementations. Callers should
+// compare against these with errors.Is since implementations may wrap them.
+var (
+	// ErrNotFound is returned when the requested record does not exist
+	ErrNotFound = errors.New("not found")
+
+	// ErrDuplicate is returned when a write would create a record that
+	// already exists, such as a second user with the same email
+	ErrDuplicate = errors.New("already exists")
+
+	// ErrConflict is returned when a write is not allowed given the current
+	// state of the records it touches
+	ErrConflict = errors.New("conflict")
+)
+
+// More specific errors, each of which also matches one of the kinds above
+// under errors.Is
+var (
+	// ErrDuplicateReview is returned when a user reviews the same album twice
+	ErrDuplicateReview = newKindError("user has already reviewed this album", ErrDuplicate)
+
+	// ErrSelfFollow is returned when a user tries to follow themselves
+	ErrSelfFollow = newKindError("users cannot follow themselves", ErrConflict)
+
+	// ErrAlreadyImported is returned when a user imports the same Spotify
+	// playlist twice
+	ErrAlreadyImported = newKindError("spotify playlist has already been imported", ErrDuplicate)
+
+	// ErrCachedNotFound reports that the cache remembers an item as not
+	// existing upstream, so callers should not look it up again
+	ErrCachedNotFound = newKindError("item cached as not found", ErrNotFound)
+)
+
+// kindError is an error with its own message that unwraps to a broader kind,
+// so it matches both itself and the kind under errors.Is
+type kindError struct {
+	msg  string
+	kind error
+}
+
+func newKindError(msg string, kind error) error {
+	return &kindError{msg: msg, kind: kind}
+}
+
+func (e *kindError) Error() string { return e.msg }
+
+func (e *kindError) Unwrap() error { return e.kind }
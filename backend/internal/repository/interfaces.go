@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/daedal00/muse/backend/internal/models"
 	"github.com/google/uuid"
@@ -12,15 +13,42 @@ import (
 type UserRepository interface {
 	Create(ctx context.Context, user *models.User) error
 	GetByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.User, error)
 	GetByEmail(ctx context.Context, email string) (*models.User, error)
 	Update(ctx context.Context, user *models.User) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	List(ctx context.Context, limit, offset int) ([]*models.User, error)
+	ListConnection(ctx context.Context, first int, after *string) (*models.Connection[models.User], error)
+	SearchByName(ctx context.Context, query string, limit, offset int) ([]*models.User, error)
+	TouchLastActive(ctx context.Context, id uuid.UUID) error
+	UpdateLastLogin(ctx context.Context, id uuid.UUID) error
+	DeleteAccount(ctx context.Context, id uuid.UUID) error
+	GetInactiveUsers(ctx context.Context, inactiveSince time.Time, limit, offset int) ([]*models.User, error)
+}
+
+type UserPreferencesRepository interface {
+	Create(ctx context.Context, prefs *models.UserPreferences) error
+	GetByUserID(ctx context.Context, userID uuid.UUID) (*models.UserPreferences, error)
+	Update(ctx context.Context, prefs *models.UserPreferences) error
+	Delete(ctx context.Context, userID uuid.UUID) error
+	AddFavoriteArtist(ctx context.Context, userID uuid.UUID, artistID string) error
+	RemoveFavoriteArtist(ctx context.Context, userID uuid.UUID, artistID string) error
+	UpdatePreferredGenres(ctx context.Context, userID uuid.UUID, genres []string) error
+}
+
+type FollowRepository interface {
+	Follow(ctx context.Context, followerID, followeeID uuid.UUID) error
+	Unfollow(ctx context.Context, followerID, followeeID uuid.UUID) error
+	IsFollowing(ctx context.Context, followerID, followeeID uuid.UUID) (bool, error)
+	GetFollowers(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.User, error)
+	GetFollowing(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.User, error)
+	GetCounts(ctx context.Context, userID uuid.UUID) (models.FollowCounts, error)
 }
 
 type ArtistRepository interface {
 	Create(ctx context.Context, artist *models.Artist) error
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Artist, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.Artist, error)
 	GetBySpotifyID(ctx context.Context, spotifyID string) (*models.Artist, error)
 	Update(ctx context.Context, artist *models.Artist) error
 	Delete(ctx context.Context, id uuid.UUID) error
@@ -30,6 +58,7 @@ type ArtistRepository interface {
 type AlbumRepository interface {
 	Create(ctx context.Context, album *models.Album) error
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Album, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.Album, error)
 	GetBySpotifyID(ctx context.Context, spotifyID string) (*models.Album, error)
 	GetByArtistID(ctx context.Context, artistID uuid.UUID, limit, offset int) ([]*models.Album, error)
 	Update(ctx context.Context, album *models.Album) error
@@ -39,7 +68,9 @@ type AlbumRepository interface {
 
 type TrackRepository interface {
 	Create(ctx context.Context, track *models.Track) error
+	// GetByID and GetByIDs populate the album and its artist
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Track, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.Track, error)
 	GetBySpotifyID(ctx context.Context, spotifyID string) (*models.Track, error)
 	GetByAlbumID(ctx context.Context, albumID uuid.UUID, limit, offset int) ([]*models.Track, error)
 	Update(ctx context.Context, track *models.Track) error
@@ -47,39 +78,125 @@ type TrackRepository interface {
 	List(ctx context.Context, limit, offset int) ([]*models.Track, error)
 }
 
+// ReviewFilter narrows ListFiltered results; nil fields are not filtered on
+type ReviewFilter struct {
+	UserID    *uuid.UUID
+	AlbumID   *uuid.UUID
+	MinRating *int
+	MaxRating *int
+	Since     *time.Time
+	Until     *time.Time
+}
+
 type ReviewRepository interface {
 	Create(ctx context.Context, review *models.Review) error
+	Upsert(ctx context.Context, review *models.Review) error
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Review, error)
+	GetByIDWithVoteCount(ctx context.Context, id uuid.UUID) (*models.Review, int, error)
 	GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.Review, error)
+	GetFeedReviews(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.Review, error)
 	GetByAlbumID(ctx context.Context, albumID uuid.UUID, limit, offset int) ([]*models.Review, error)
+	GetByAlbumIDWithSpoilerFlag(ctx context.Context, albumID uuid.UUID, includeSpoilers bool, limit, offset int) ([]*models.Review, error)
 	GetByUserAndAlbum(ctx context.Context, userID, albumID uuid.UUID) (*models.Review, error)
 	Update(ctx context.Context, review *models.Review) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	Restore(ctx context.Context, id uuid.UUID) error
+	PurgeSoftDeleted(ctx context.Context, olderThan time.Time) (int64, error)
 	List(ctx context.Context, limit, offset int) ([]*models.Review, error)
+	ListFiltered(ctx context.Context, filter ReviewFilter, limit, offset int) ([]*models.Review, error)
+	ListConnection(ctx context.Context, first int, after, before *string) (*models.Connection[models.Review], error)
+	SearchByText(ctx context.Context, query string, limit, offset int) ([]*models.Review, error)
+
+	// Aggregates
+	GetAlbumStats(ctx context.Context, albumID uuid.UUID) (models.ReviewStats, error)
+	GetRatingTrend(ctx context.Context, albumID uuid.UUID, buckets int, bucketSize time.Duration) ([]models.RatingPoint, error)
+	GetRatingMatrix(ctx context.Context, userIDs, albumIDs []uuid.UUID) (map[uuid.UUID]map[uuid.UUID]int, error)
+	GetRatingDistribution(ctx context.Context, albumID uuid.UUID) (*models.RatingDistribution, error)
+	GetRatingByCountry(ctx context.Context, albumID uuid.UUID) (map[string]models.ReviewStats, error)
+	GetReviewSummary(ctx context.Context, albumID uuid.UUID, limit, offset int) (*models.ReviewSummary, error)
+	GetMostReviewed(ctx context.Context, spotifyType string, since time.Time, limit int) ([]models.ItemReviewAggregate, error)
+	GetTopRated(ctx context.Context, spotifyType string, minReviews int, limit int) ([]models.ItemReviewAggregate, error)
+	GetAverageRatings(ctx context.Context, spotifyType string, spotifyIDs []string) (map[string]float64, error)
+
+	// Search outbox
+	FetchPendingSearchEvents(ctx context.Context, limit int) ([]*models.ReviewSearchEvent, error)
+	MarkSearchEventsProcessed(ctx context.Context, ids []int64) error
+}
+
+// ReviewVoteRepository records which users found a review helpful
+type ReviewVoteRepository interface {
+	Vote(ctx context.Context, reviewID, userID uuid.UUID) error
+	Unvote(ctx context.Context, reviewID, userID uuid.UUID) error
+	GetVoteCount(ctx context.Context, reviewID uuid.UUID) (int, error)
+	HasVoted(ctx context.Context, reviewID, userID uuid.UUID) (bool, error)
+}
+
+// TrackInsert is a track appended to a playlist by AddTracks; a zero AddedAt
+// means now
+type TrackInsert struct {
+	TrackID uuid.UUID
+	AddedAt time.Time
 }
 
 type PlaylistRepository interface {
 	Create(ctx context.Context, playlist *models.Playlist) error
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Playlist, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.Playlist, error)
 	GetByCreatorID(ctx context.Context, creatorID uuid.UUID, limit, offset int) ([]*models.Playlist, error)
+	CountByCreatorID(ctx context.Context, creatorID uuid.UUID, publicOnly bool) (int, error)
+	GetBySpotifyPlaylistID(ctx context.Context, userID uuid.UUID, spotifyPlaylistID string) (*models.Playlist, error)
 	Update(ctx context.Context, playlist *models.Playlist) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	Restore(ctx context.Context, id uuid.UUID) error
+	PurgeSoftDeleted(ctx context.Context, olderThan time.Time) (int64, error)
 	List(ctx context.Context, limit, offset int) ([]*models.Playlist, error)
+	ListConnection(ctx context.Context, first int, after *string) (*models.Connection[models.Playlist], error)
+	SearchByTitle(ctx context.Context, query string, limit, offset int) ([]*models.Playlist, error)
+	GetPopularTags(ctx context.Context, limit int) ([]models.TagCount, error)
 
 	// Playlist track operations
 	AddTrack(ctx context.Context, playlistID, trackID uuid.UUID, position int) error
+	AddTracks(ctx context.Context, playlistID uuid.UUID, tracks []TrackInsert) error
 	RemoveTrack(ctx context.Context, playlistID, trackID uuid.UUID) error
+	RemoveTracks(ctx context.Context, playlistID uuid.UUID, spotifyIDs []string) error
+	ClearTracks(ctx context.Context, playlistID uuid.UUID) error
 	GetTracks(ctx context.Context, playlistID uuid.UUID, limit, offset int) ([]*models.Track, error)
+	GetTracksDesc(ctx context.Context, playlistID uuid.UUID, limit, offset int) ([]*models.Track, error)
+	GetTracksInRange(ctx context.Context, playlistID uuid.UUID, fromPosition, toPosition int) ([]*models.Track, error)
+	GetTrackSpotifyIDs(ctx context.Context, playlistID uuid.UUID) ([]string, error)
+	GetTrackCounts(ctx context.Context, playlistIDs []uuid.UUID) (map[uuid.UUID]int, error)
 	ReorderTracks(ctx context.Context, playlistID uuid.UUID, trackPositions map[uuid.UUID]int) error
+	MoveTrack(ctx context.Context, playlistID uuid.UUID, spotifyID string, newPosition int) error
+
+	// Collaborators, who may edit a playlist alongside its creator
+	AddCollaborator(ctx context.Context, playlistID, userID uuid.UUID) error
+	RemoveCollaborator(ctx context.Context, playlistID, userID uuid.UUID) error
+	IsCollaborator(ctx context.Context, playlistID, userID uuid.UUID) (bool, error)
+	CanEdit(ctx context.Context, playlistID, userID uuid.UUID) (bool, error)
 }
 
 type SessionRepository interface {
 	Create(ctx context.Context, session *models.Session) error
 	GetByID(ctx context.Context, id string) (*models.Session, error)
 	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*models.Session, error)
+	// Refresh extends an active session to newExpiry, returning ErrNotFound
+	// if it has already expired or been deleted
+	Refresh(ctx context.Context, id string, newExpiry time.Time) error
+	// UpdateLastSeen marks a session as just used, returning ErrNotFound if
+	// it has expired or been deleted
+	UpdateLastSeen(ctx context.Context, id string) error
 	Delete(ctx context.Context, id string) error
 	DeleteExpired(ctx context.Context) error
 	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
+	// DeleteByUserIDExcept ends every session of a user but keepSessionID
+	DeleteByUserIDExcept(ctx context.Context, userID uuid.UUID, keepSessionID string) error
+	// StoreRefreshToken records the hash of a refresh token for a session
+	StoreRefreshToken(ctx context.Context, tokenHash, sessionID string, expiresAt time.Time) error
+	// RotateRefreshToken atomically consumes an unexpired refresh token and
+	// stores newHash for the same session, returning the session ID.
+	// ErrNotFound means the token is unknown, expired or already used, or
+	// that its session has ended, in which case the token is discarded.
+	RotateRefreshToken(ctx context.Context, oldHash, newHash string, expiresAt time.Time) (string, error)
 }
 
 // MusicCacheRepository handles caching of user music data and search results
@@ -88,10 +205,26 @@ type MusicCacheRepository interface {
 	SetUserMusicData(ctx context.Context, userID uuid.UUID, data interface{}) error
 	GetUserMusicData(ctx context.Context, userID uuid.UUID) (interface{}, error)
 	AddToRecentlyPlayed(ctx context.Context, userID uuid.UUID, track *models.Track) error
+	GetRecentlyPlayed(ctx context.Context, userID uuid.UUID, limit int) ([]*models.Track, error)
 
 	// Search results caching
-	SetSearchResults(ctx context.Context, query string, resultType string, results interface{}) error
-	GetSearchResults(ctx context.Context, query string, resultType string) (interface{}, error)
+	SetSearchResults(ctx context.Context, query string, resultType string, limit, offset int, results interface{}) error
+	GetSearchResults(ctx context.Context, query string, resultType string, limit, offset int) (interface{}, error)
+	SetAlbumSearchResults(ctx context.Context, query string, limit, offset int, results []models.SpotifyAlbum) error
+	GetAlbumSearchResults(ctx context.Context, query string, limit, offset int) ([]models.SpotifyAlbum, error)
+	SetArtistSearchResults(ctx context.Context, query string, limit, offset int, results []models.SpotifyArtist) error
+	GetArtistSearchResults(ctx context.Context, query string, limit, offset int) ([]models.SpotifyArtist, error)
+	SetTrackSearchResults(ctx context.Context, query string, limit, offset int, results []models.SpotifyTrack) error
+	GetTrackSearchResults(ctx context.Context, query string, limit, offset int) ([]models.SpotifyTrack, error)
+
+	// Generated recommendations
+	SetRecommendations(ctx context.Context, recommendations *models.CachedRecommendations) error
+	GetRecommendations(ctx context.Context, userID uuid.UUID) (*models.CachedRecommendations, error)
+
+	// Playlist audio feature statistics
+	SetPlaylistAudioStats(ctx context.Context, stats *models.PlaylistAudioStats) error
+	GetPlaylistAudioStats(ctx context.Context, playlistID uuid.UUID) (*models.PlaylistAudioStats, error)
+	DeletePlaylistAudioStats(ctx context.Context, playlistID uuid.UUID) error
 
 	// Listening history
 	SetListeningHistory(ctx context.Context, userID uuid.UUID, history interface{}) error
@@ -103,20 +236,38 @@ type MusicCacheRepository interface {
 	SetPopularTracks(ctx context.Context, tracks []*models.Track) error
 	GetPopularTracks(ctx context.Context) ([]*models.Track, error)
 
+	// Spotify item metadata caching, keyed by item type and Spotify ID
+	SetSpotifyItems(ctx context.Context, itemType string, items map[string]interface{}) error
+	GetSpotifyItems(ctx context.Context, itemType string, ids []string) (map[string][]byte, error)
+	GetSpotifyItem(ctx context.Context, itemType, id string) ([]byte, error)
+	SetSpotifyItemsNotFound(ctx context.Context, itemType string, ids []string) error
+
 	// Cache management
 	InvalidateUserCache(ctx context.Context, userID uuid.UUID) error
 	InvalidateSearchCache(ctx context.Context, query string) error
+	ClearSpotifyCache(ctx context.Context) (int, error)
 	GetCacheStats(ctx context.Context) (map[string]int, error)
 }
 
 // Repository container
 type Repositories struct {
-	User       UserRepository
-	Artist     ArtistRepository
-	Album      AlbumRepository
-	Track      TrackRepository
-	Review     ReviewRepository
-	Playlist   PlaylistRepository
-	Session    SessionRepository
-	MusicCache MusicCacheRepository // New: Redis music cache
+	User            UserRepository
+	UserPreferences UserPreferencesRepository
+	Follow          FollowRepository
+	Artist          ArtistRepository
+	Album           AlbumRepository
+	Track           TrackRepository
+	Review          ReviewRepository
+	ReviewVote      ReviewVoteRepository
+	Playlist        PlaylistRepository
+	Session         SessionRepository
+	MusicCache      MusicCacheRepository // New: Redis music cache
+}
+
+// TxManager runs multi-repository operations atomically
+type TxManager interface {
+	// WithTx calls fn with repositories bound to one database transaction,
+	// committing if fn returns nil and rolling back otherwise. Cache and
+	// other non-database repositories are not transactional.
+	WithTx(ctx context.Context, fn func(Repositories) error) error
 }
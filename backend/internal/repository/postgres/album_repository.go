@@ -12,11 +12,15 @@ import (
 )
 
 type albumRepository struct {
-	db *database.PostgresDB
+	db *dbConn
 }
 
 func NewAlbumRepository(db *database.PostgresDB) repository.AlbumRepository {
-	return &albumRepository{db: db}
+	return &albumRepository{db: newDBConn(db)}
+}
+
+func newAlbumRepository(q querier) *albumRepository {
+	return &albumRepository{db: &dbConn{Pool: q}}
 }
 
 func (r *albumRepository) Create(ctx context.Context, album *models.Album) error {
@@ -31,6 +35,9 @@ func (r *albumRepository) Create(ctx context.Context, album *models.Album) error
 	)
 
 	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("album %w", repository.ErrDuplicate)
+		}
 		return fmt.Errorf("failed to create album: %w", err)
 	}
 
@@ -45,14 +52,14 @@ func (r *albumRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Al
 	`
 
 	album := &models.Album{}
-	err := r.db.Pool.QueryRow(ctx, query, id).Scan(
+	err := r.db.Reader().QueryRow(ctx, query, id).Scan(
 		&album.ID, &album.SpotifyID, &album.Title, &album.ArtistID,
 		&album.ReleaseDate, &album.CoverImage, &album.CreatedAt, &album.UpdatedAt,
 	)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("album not found")
+			return nil, fmt.Errorf("album %w", repository.ErrNotFound)
 		}
 		return nil, fmt.Errorf("failed to get album: %w", err)
 	}
@@ -60,6 +67,45 @@ func (r *albumRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Al
 	return album, nil
 }
 
+// GetByIDs fetches several albums in one query, keyed by ID. IDs with no
+// matching album are left out of the map.
+func (r *albumRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.Album, error) {
+	albums := make(map[uuid.UUID]*models.Album, len(ids))
+	if len(ids) == 0 {
+		return albums, nil
+	}
+
+	query := `
+		SELECT id, spotify_id, title, artist_id, release_date, cover_image, created_at, updated_at
+		FROM albums
+		WHERE id = ANY($1)
+	`
+
+	rows, err := r.db.Reader().Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get albums: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		album := &models.Album{}
+		err := rows.Scan(
+			&album.ID, &album.SpotifyID, &album.Title, &album.ArtistID,
+			&album.ReleaseDate, &album.CoverImage, &album.CreatedAt, &album.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan album: %w", err)
+		}
+		albums[album.ID] = album
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating albums: %w", err)
+	}
+
+	return albums, nil
+}
+
 func (r *albumRepository) GetBySpotifyID(ctx context.Context, spotifyID string) (*models.Album, error) {
 	query := `
 		SELECT id, spotify_id, title, artist_id, release_date, cover_image, created_at, updated_at
@@ -68,14 +114,14 @@ func (r *albumRepository) GetBySpotifyID(ctx context.Context, spotifyID string)
 	`
 
 	album := &models.Album{}
-	err := r.db.Pool.QueryRow(ctx, query, spotifyID).Scan(
+	err := r.db.Reader().QueryRow(ctx, query, spotifyID).Scan(
 		&album.ID, &album.SpotifyID, &album.Title, &album.ArtistID,
 		&album.ReleaseDate, &album.CoverImage, &album.CreatedAt, &album.UpdatedAt,
 	)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("album not found")
+			return nil, fmt.Errorf("album %w", repository.ErrNotFound)
 		}
 		return nil, fmt.Errorf("failed to get album: %w", err)
 	}
@@ -92,7 +138,7 @@ func (r *albumRepository) GetByArtistID(ctx context.Context, artistID uuid.UUID,
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query, artistID, limit, offset)
+	rows, err := r.db.Reader().Query(ctx, query, artistID, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list albums by artist: %w", err)
 	}
@@ -134,7 +180,7 @@ func (r *albumRepository) Update(ctx context.Context, album *models.Album) error
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("album not found")
+		return fmt.Errorf("album %w", repository.ErrNotFound)
 	}
 
 	return nil
@@ -149,7 +195,7 @@ func (r *albumRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("album not found")
+		return fmt.Errorf("album %w", repository.ErrNotFound)
 	}
 
 	return nil
@@ -163,7 +209,7 @@ func (r *albumRepository) List(ctx context.Context, limit, offset int) ([]*model
 		LIMIT $1 OFFSET $2
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query, limit, offset)
+	rows, err := r.db.Reader().Query(ctx, query, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list albums: %w", err)
 	}
@@ -10,7 +10,7 @@ import (
 	"github.com/google/uuid"
 )
 
-func setupTestAlbum(t *testing.T, artistID uuid.UUID) *models.Album {
+func setupTestAlbum(t testing.TB, artistID uuid.UUID) *models.Album {
 	t.Helper()
 
 	releaseDate, _ := time.Parse("2006-01-02", "2023-01-15")
@@ -27,7 +27,7 @@ func setupTestAlbum(t *testing.T, artistID uuid.UUID) *models.Album {
 	}
 }
 
-func cleanupTestAlbum(t *testing.T, ctx context.Context, albumID uuid.UUID) {
+func cleanupTestAlbum(t testing.TB, ctx context.Context, albumID uuid.UUID) {
 	t.Helper()
 
 	_, err := testDB.Pool.Exec(ctx, "DELETE FROM albums WHERE id = $1", albumID)
@@ -136,6 +136,40 @@ func TestAlbumRepository_GetByID_NotFound(t *testing.T) {
 	}
 }
 
+func TestAlbumRepository_GetByIDs(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	albumRepo := NewAlbumRepository(testDB)
+	artistRepo := NewArtistRepository(testDB)
+	ctx := context.Background()
+
+	artist := setupTestArtist(t)
+	defer cleanupTestArtist(t, ctx, artist.ID)
+	if err := artistRepo.Create(ctx, artist); err != nil {
+		t.Fatalf("Failed to create test artist: %v", err)
+	}
+
+	var ids []uuid.UUID
+	for i := 0; i < 2; i++ {
+		album := setupTestAlbum(t, artist.ID)
+		defer cleanupTestAlbum(t, ctx, album.ID)
+		if err := albumRepo.Create(ctx, album); err != nil {
+			t.Fatalf("Failed to create album: %v", err)
+		}
+		ids = append(ids, album.ID)
+	}
+
+	albums, err := albumRepo.GetByIDs(ctx, append(ids, uuid.New()))
+	if err != nil {
+		t.Fatalf("Failed to get albums by IDs: %v", err)
+	}
+	if len(albums) != 2 || albums[ids[0]] == nil || albums[ids[1]] == nil {
+		t.Errorf("Expected both albums and no entry for the missing ID, got %v", albums)
+	}
+}
+
 func TestAlbumRepository_GetBySpotifyID(t *testing.T) {
 	if testDB == nil {
 		t.Skip("Database not available")
@@ -12,11 +12,15 @@ import (
 )
 
 type artistRepository struct {
-	db *database.PostgresDB
+	db *dbConn
 }
 
 func NewArtistRepository(db *database.PostgresDB) repository.ArtistRepository {
-	return &artistRepository{db: db}
+	return &artistRepository{db: newDBConn(db)}
+}
+
+func newArtistRepository(q querier) *artistRepository {
+	return &artistRepository{db: &dbConn{Pool: q}}
 }
 
 func (r *artistRepository) Create(ctx context.Context, artist *models.Artist) error {
@@ -30,6 +34,9 @@ func (r *artistRepository) Create(ctx context.Context, artist *models.Artist) er
 	)
 
 	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("artist %w", repository.ErrDuplicate)
+		}
 		return fmt.Errorf("failed to create artist: %w", err)
 	}
 
@@ -44,13 +51,13 @@ func (r *artistRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.A
 	`
 
 	artist := &models.Artist{}
-	err := r.db.Pool.QueryRow(ctx, query, id).Scan(
+	err := r.db.Reader().QueryRow(ctx, query, id).Scan(
 		&artist.ID, &artist.SpotifyID, &artist.Name, &artist.CreatedAt, &artist.UpdatedAt,
 	)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("artist not found")
+			return nil, fmt.Errorf("artist %w", repository.ErrNotFound)
 		}
 		return nil, fmt.Errorf("failed to get artist: %w", err)
 	}
@@ -58,6 +65,44 @@ func (r *artistRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.A
 	return artist, nil
 }
 
+// GetByIDs fetches several artists in one query, keyed by ID. IDs with no
+// matching artist are left out of the map.
+func (r *artistRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.Artist, error) {
+	artists := make(map[uuid.UUID]*models.Artist, len(ids))
+	if len(ids) == 0 {
+		return artists, nil
+	}
+
+	query := `
+		SELECT id, spotify_id, name, created_at, updated_at
+		FROM artists
+		WHERE id = ANY($1)
+	`
+
+	rows, err := r.db.Reader().Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get artists: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		artist := &models.Artist{}
+		err := rows.Scan(
+			&artist.ID, &artist.SpotifyID, &artist.Name, &artist.CreatedAt, &artist.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan artist: %w", err)
+		}
+		artists[artist.ID] = artist
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating artists: %w", err)
+	}
+
+	return artists, nil
+}
+
 func (r *artistRepository) GetBySpotifyID(ctx context.Context, spotifyID string) (*models.Artist, error) {
 	query := `
 		SELECT id, spotify_id, name, created_at, updated_at
@@ -66,13 +111,13 @@ func (r *artistRepository) GetBySpotifyID(ctx context.Context, spotifyID string)
 	`
 
 	artist := &models.Artist{}
-	err := r.db.Pool.QueryRow(ctx, query, spotifyID).Scan(
+	err := r.db.Reader().QueryRow(ctx, query, spotifyID).Scan(
 		&artist.ID, &artist.SpotifyID, &artist.Name, &artist.CreatedAt, &artist.UpdatedAt,
 	)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("artist not found")
+			return nil, fmt.Errorf("artist %w", repository.ErrNotFound)
 		}
 		return nil, fmt.Errorf("failed to get artist: %w", err)
 	}
@@ -96,7 +141,7 @@ func (r *artistRepository) Update(ctx context.Context, artist *models.Artist) er
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("artist not found")
+		return fmt.Errorf("artist %w", repository.ErrNotFound)
 	}
 
 	return nil
@@ -111,7 +156,7 @@ func (r *artistRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("artist not found")
+		return fmt.Errorf("artist %w", repository.ErrNotFound)
 	}
 
 	return nil
@@ -125,7 +170,7 @@ func (r *artistRepository) List(ctx context.Context, limit, offset int) ([]*mode
 		LIMIT $1 OFFSET $2
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query, limit, offset)
+	rows, err := r.db.Reader().Query(ctx, query, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list artists: %w", err)
 	}
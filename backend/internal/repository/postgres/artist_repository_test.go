@@ -10,7 +10,7 @@ import (
 	"github.com/google/uuid"
 )
 
-func setupTestArtist(t *testing.T) *models.Artist {
+func setupTestArtist(t testing.TB) *models.Artist {
 	t.Helper()
 
 	return &models.Artist{
@@ -22,7 +22,7 @@ func setupTestArtist(t *testing.T) *models.Artist {
 	}
 }
 
-func cleanupTestArtist(t *testing.T, ctx context.Context, artistID uuid.UUID) {
+func cleanupTestArtist(t testing.TB, ctx context.Context, artistID uuid.UUID) {
 	t.Helper()
 
 	_, err := testDB.Pool.Exec(ctx, "DELETE FROM artists WHERE id = $1", artistID)
@@ -105,6 +105,33 @@ func TestArtistRepository_GetByID_NotFound(t *testing.T) {
 	}
 }
 
+func TestArtistRepository_GetByIDs(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewArtistRepository(testDB)
+	ctx := context.Background()
+
+	var ids []uuid.UUID
+	for i := 0; i < 2; i++ {
+		artist := setupTestArtist(t)
+		defer cleanupTestArtist(t, ctx, artist.ID)
+		if err := repo.Create(ctx, artist); err != nil {
+			t.Fatalf("Failed to create artist: %v", err)
+		}
+		ids = append(ids, artist.ID)
+	}
+
+	artists, err := repo.GetByIDs(ctx, append(ids, uuid.New()))
+	if err != nil {
+		t.Fatalf("Failed to get artists by IDs: %v", err)
+	}
+	if len(artists) != 2 || artists[ids[0]] == nil || artists[ids[1]] == nil {
+		t.Errorf("Expected both artists and no entry for the missing ID, got %v", artists)
+	}
+}
+
 func TestArtistRepository_GetBySpotifyID(t *testing.T) {
 	if testDB == nil {
 		t.Skip("Database not available")
@@ -0,0 +1,35 @@
+package postgres
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// uniqueViolation is the SQLSTATE Postgres reports for unique constraint failures
+const uniqueViolation = "23505"
+
+// spotifyPlaylistImportIndex is the unique index that stops a user importing
+// the same Spotify playlist twice (migration 012)
+const spotifyPlaylistImportIndex = "idx_playlists_creator_spotify_playlist"
+
+// activeReviewIndex is the unique index that allows one live review per user
+// and album (migration 007)
+const activeReviewIndex = "idx_reviews_user_album_active"
+
+// userEmailIndex is the case-insensitive unique index on user emails
+// (migration 019)
+const userEmailIndex = "idx_users_email_lower"
+
+// isUniqueViolation reports whether err was caused by a unique constraint
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
+
+// isConstraintViolation reports whether err was caused by the named unique
+// constraint or index
+func isConstraintViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation && pgErr.ConstraintName == constraint
+}
@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/daedal00/muse/backend/internal/database"
+	"github.com/daedal00/muse/backend/internal/models"
+	"github.com/daedal00/muse/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+type followRepository struct {
+	db *dbConn
+}
+
+func NewFollowRepository(db *database.PostgresDB) repository.FollowRepository {
+	return &followRepository{db: newDBConn(db)}
+}
+
+func newFollowRepository(q querier) *followRepository {
+	return &followRepository{db: &dbConn{Pool: q}}
+}
+
+// Follow makes followerID follow followeeID. Following someone already
+// followed is a no-op; following yourself returns repository.ErrSelfFollow.
+func (r *followRepository) Follow(ctx context.Context, followerID, followeeID uuid.UUID) error {
+	if followerID == followeeID {
+		return repository.ErrSelfFollow
+	}
+
+	query := `
+		INSERT INTO follows (follower_id, followee_id)
+		VALUES ($1, $2)
+		ON CONFLICT (follower_id, followee_id) DO NOTHING
+	`
+
+	if _, err := r.db.Pool.Exec(ctx, query, followerID, followeeID); err != nil {
+		return fmt.Errorf("failed to follow user: %w", err)
+	}
+
+	return nil
+}
+
+// Unfollow removes the follow if there is one
+func (r *followRepository) Unfollow(ctx context.Context, followerID, followeeID uuid.UUID) error {
+	query := `DELETE FROM follows WHERE follower_id = $1 AND followee_id = $2`
+
+	if _, err := r.db.Pool.Exec(ctx, query, followerID, followeeID); err != nil {
+		return fmt.Errorf("failed to unfollow user: %w", err)
+	}
+
+	return nil
+}
+
+func (r *followRepository) IsFollowing(ctx context.Context, followerID, followeeID uuid.UUID) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM follows WHERE follower_id = $1 AND followee_id = $2)`
+
+	var following bool
+	if err := r.db.Reader().QueryRow(ctx, query, followerID, followeeID).Scan(&following); err != nil {
+		return false, fmt.Errorf("failed to check follow: %w", err)
+	}
+
+	return following, nil
+}
+
+// GetFollowers returns the users following userID, most recent follow first
+func (r *followRepository) GetFollowers(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.User, error) {
+	query := `
+		SELECT u.id, u.name, u.email, u.password_hash, u.bio, u.avatar, u.country, u.role, u.last_active_at, u.last_login_at, u.created_at, u.updated_at
+		FROM follows f
+		JOIN users u ON u.id = f.follower_id
+		WHERE f.followee_id = $1
+		ORDER BY f.created_at DESC, u.id
+		LIMIT $2 OFFSET $3
+	`
+
+	return r.listUsers(ctx, query, userID, limit, offset)
+}
+
+// GetFollowing returns the users userID follows, most recent follow first
+func (r *followRepository) GetFollowing(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.User, error) {
+	query := `
+		SELECT u.id, u.name, u.email, u.password_hash, u.bio, u.avatar, u.country, u.role, u.last_active_at, u.last_login_at, u.created_at, u.updated_at
+		FROM follows f
+		JOIN users u ON u.id = f.followee_id
+		WHERE f.follower_id = $1
+		ORDER BY f.created_at DESC, u.id
+		LIMIT $2 OFFSET $3
+	`
+
+	return r.listUsers(ctx, query, userID, limit, offset)
+}
+
+func (r *followRepository) listUsers(ctx context.Context, query string, userID uuid.UUID, limit, offset int) ([]*models.User, error) {
+	limit, offset = clampLimitOffset(limit, offset)
+
+	rows, err := r.db.Reader().Query(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list follows: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*models.User
+	for rows.Next() {
+		user := &models.User{}
+		err := rows.Scan(
+			&user.ID, &user.Name, &user.Email, &user.PasswordHash,
+			&user.Bio, &user.Avatar, &user.Country, &user.Role, &user.LastActiveAt, &user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating users: %w", err)
+	}
+
+	return users, nil
+}
+
+func (r *followRepository) GetCounts(ctx context.Context, userID uuid.UUID) (models.FollowCounts, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM follows WHERE followee_id = $1),
+			(SELECT COUNT(*) FROM follows WHERE follower_id = $1)
+	`
+
+	var counts models.FollowCounts
+	if err := r.db.Reader().QueryRow(ctx, query, userID).Scan(&counts.Followers, &counts.Following); err != nil {
+		return models.FollowCounts{}, fmt.Errorf("failed to get follow counts: %w", err)
+	}
+
+	return counts, nil
+}
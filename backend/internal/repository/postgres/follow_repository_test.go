@@ -0,0 +1,175 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/daedal00/muse/backend/internal/models"
+	"github.com/daedal00/muse/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// setupFollowUsers creates n users for follow tests along with a cleanup
+// function
+func setupFollowUsers(t testing.TB, ctx context.Context, n int) ([]uuid.UUID, func()) {
+	t.Helper()
+
+	userRepo := NewUserRepository(testDB)
+	ids := make([]uuid.UUID, 0, n)
+	cleanup := func() {
+		for _, id := range ids {
+			cleanupTestUser(t, ctx, id)
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		user := setupTestUser(t)
+		if err := userRepo.Create(ctx, user); err != nil {
+			cleanup()
+			t.Fatalf("Failed to create test user: %v", err)
+		}
+		ids = append(ids, user.ID)
+	}
+
+	return ids, cleanup
+}
+
+func TestFollowRepository_Lifecycle(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewFollowRepository(testDB)
+	ctx := context.Background()
+
+	ids, cleanup := setupFollowUsers(t, ctx, 2)
+	defer cleanup()
+	alice, bob := ids[0], ids[1]
+
+	following, err := repo.IsFollowing(ctx, alice, bob)
+	if err != nil {
+		t.Fatalf("Failed to check follow: %v", err)
+	}
+	if following {
+		t.Error("Expected alice not to follow bob yet")
+	}
+
+	// Following twice is idempotent
+	for i := 0; i < 2; i++ {
+		if err := repo.Follow(ctx, alice, bob); err != nil {
+			t.Fatalf("Failed to follow user: %v", err)
+		}
+	}
+
+	following, err = repo.IsFollowing(ctx, alice, bob)
+	if err != nil {
+		t.Fatalf("Failed to check follow: %v", err)
+	}
+	if !following {
+		t.Error("Expected alice to follow bob")
+	}
+
+	// Follows are one-directional
+	following, err = repo.IsFollowing(ctx, bob, alice)
+	if err != nil {
+		t.Fatalf("Failed to check follow: %v", err)
+	}
+	if following {
+		t.Error("Expected bob not to follow alice")
+	}
+
+	followers, err := repo.GetFollowers(ctx, bob, 10, 0)
+	if err != nil {
+		t.Fatalf("Failed to get followers: %v", err)
+	}
+	if len(followers) != 1 || followers[0].ID != alice {
+		t.Errorf("Expected alice as bob's only follower, got %d followers", len(followers))
+	}
+
+	followees, err := repo.GetFollowing(ctx, alice, 10, 0)
+	if err != nil {
+		t.Fatalf("Failed to get following: %v", err)
+	}
+	if len(followees) != 1 || followees[0].ID != bob {
+		t.Errorf("Expected alice to follow only bob, got %d users", len(followees))
+	}
+
+	// Unfollowing twice is idempotent
+	for i := 0; i < 2; i++ {
+		if err := repo.Unfollow(ctx, alice, bob); err != nil {
+			t.Fatalf("Failed to unfollow user: %v", err)
+		}
+	}
+
+	following, err = repo.IsFollowing(ctx, alice, bob)
+	if err != nil {
+		t.Fatalf("Failed to check follow: %v", err)
+	}
+	if following {
+		t.Error("Expected alice not to follow bob after unfollowing")
+	}
+}
+
+func TestFollowRepository_SelfFollow(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewFollowRepository(testDB)
+	ctx := context.Background()
+
+	ids, cleanup := setupFollowUsers(t, ctx, 1)
+	defer cleanup()
+
+	if err := repo.Follow(ctx, ids[0], ids[0]); !errors.Is(err, repository.ErrSelfFollow) {
+		t.Errorf("Expected ErrSelfFollow, got %v", err)
+	}
+}
+
+func TestFollowRepository_GetCounts(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewFollowRepository(testDB)
+	ctx := context.Background()
+
+	ids, cleanup := setupFollowUsers(t, ctx, 4)
+	defer cleanup()
+	user := ids[0]
+
+	// Three users follow user, who follows one of them back
+	for _, id := range ids[1:] {
+		if err := repo.Follow(ctx, id, user); err != nil {
+			t.Fatalf("Failed to follow user: %v", err)
+		}
+	}
+	if err := repo.Follow(ctx, user, ids[1]); err != nil {
+		t.Fatalf("Failed to follow user: %v", err)
+	}
+
+	counts, err := repo.GetCounts(ctx, user)
+	if err != nil {
+		t.Fatalf("Failed to get follow counts: %v", err)
+	}
+	if want := (models.FollowCounts{Followers: 3, Following: 1}); counts != want {
+		t.Errorf("Expected counts %+v, got %+v", want, counts)
+	}
+
+	followers, err := repo.GetFollowers(ctx, user, 2, 0)
+	if err != nil {
+		t.Fatalf("Failed to get followers: %v", err)
+	}
+	if len(followers) != 2 {
+		t.Errorf("Expected a page of 2 followers, got %d", len(followers))
+	}
+
+	counts, err = repo.GetCounts(ctx, uuid.New())
+	if err != nil {
+		t.Fatalf("Failed to get follow counts: %v", err)
+	}
+	if counts != (models.FollowCounts{}) {
+		t.Errorf("Expected zero counts for unknown user, got %+v", counts)
+	}
+}
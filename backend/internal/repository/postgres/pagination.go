@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/daedal00/muse/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+// clampLimitOffset keeps caller-supplied paging arguments within sane bounds
+func clampLimitOffset(limit, offset int) (int, int) {
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+// escapeLikePattern escapes LIKE/ILIKE wildcards so user input matches literally
+func escapeLikePattern(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// Keyset cursors are opaque base64 strings of "<created_at>|<id>". Ordering on
+// (created_at, id) keeps them stable when new rows are inserted.
+
+func encodeCursor(createdAt time.Time, id uuid.UUID) string {
+	raw := createdAt.UTC().Format(time.RFC3339Nano) + "|" + id.String()
+	return base64.URLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (time.Time, uuid.UUID, error) {
+	raw, err := base64.URLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, uuid.Nil, fmt.Errorf("invalid cursor")
+	}
+
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return time.Time{}, uuid.Nil, fmt.Errorf("invalid cursor")
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return time.Time{}, uuid.Nil, fmt.Errorf("invalid cursor")
+	}
+
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return time.Time{}, uuid.Nil, fmt.Errorf("invalid cursor")
+	}
+
+	return createdAt, id, nil
+}
+
+// keysetArgs decodes an optional after cursor into query arguments; both are
+// nil when there is no cursor so the keyset condition is skipped
+func keysetArgs(after *string) (*time.Time, *uuid.UUID, error) {
+	if after == nil || *after == "" {
+		return nil, nil, nil
+	}
+
+	createdAt, id, err := decodeCursor(*after)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &createdAt, &id, nil
+}
+
+// newConnection builds a connection from up to first+1 rows fetched in
+// (created_at, id) descending order; the extra row only signals a next page.
+// total is the size of the whole list, not just this page. hasPreviousPage is
+// set by the caller, which knows whether it paged from a cursor.
+func newConnection[T any](nodes []T, first, total int, hasPreviousPage bool, key func(T) (time.Time, uuid.UUID)) *models.Connection[T] {
+	hasNextPage := len(nodes) > first
+	if hasNextPage {
+		nodes = nodes[:first]
+	}
+
+	return buildConnection(nodes, total, models.PageInfo{HasNextPage: hasNextPage, HasPreviousPage: hasPreviousPage}, key)
+}
+
+// newBackwardConnection builds a connection from up to first+1 rows fetched
+// in ascending order from a before cursor. The rows are flipped back to
+// descending order so both directions return pages in the same order, and
+// the cursor row itself guarantees a next page.
+func newBackwardConnection[T any](nodes []T, first, total int, key func(T) (time.Time, uuid.UUID)) *models.Connection[T] {
+	hasPreviousPage := len(nodes) > first
+	if hasPreviousPage {
+		nodes = nodes[:first]
+	}
+
+	for i, j := 0, len(nodes)-1; i < j; i, j = i+1, j-1 {
+		nodes[i], nodes[j] = nodes[j], nodes[i]
+	}
+
+	return buildConnection(nodes, total, models.PageInfo{HasNextPage: true, HasPreviousPage: hasPreviousPage}, key)
+}
+
+func buildConnection[T any](nodes []T, total int, pageInfo models.PageInfo, key func(T) (time.Time, uuid.UUID)) *models.Connection[T] {
+	connection := &models.Connection[T]{
+		TotalCount: total,
+		Edges:      make([]models.Edge[T], len(nodes)),
+		PageInfo:   pageInfo,
+	}
+
+	for i, node := range nodes {
+		createdAt, id := key(node)
+		connection.Edges[i] = models.Edge[T]{Cursor: encodeCursor(createdAt, id), Node: node}
+	}
+
+	if len(connection.Edges) > 0 {
+		startCursor := connection.Edges[0].Cursor
+		endCursor := connection.Edges[len(connection.Edges)-1].Cursor
+		connection.PageInfo.StartCursor = &startCursor
+		connection.PageInfo.EndCursor = &endCursor
+	}
+
+	return connection
+}
+
+// connectionTotal returns the list size for a connection. Connection queries
+// select the count alongside every row, so a second query is only needed
+// when the page came back empty.
+func connectionTotal(ctx context.Context, q querier, rows, rowTotal int, countQuery string) (int, error) {
+	if rows > 0 {
+		return rowTotal, nil
+	}
+
+	var total int
+	if err := q.QueryRow(ctx, countQuery).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to count rows: %w", err)
+	}
+	return total, nil
+}
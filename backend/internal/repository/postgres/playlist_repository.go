@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/daedal00/muse/backend/internal/database"
 	"github.com/daedal00/muse/backend/internal/models"
@@ -12,25 +13,35 @@ import (
 )
 
 type playlistRepository struct {
-	db *database.PostgresDB
+	db *dbConn
 }
 
 func NewPlaylistRepository(db *database.PostgresDB) repository.PlaylistRepository {
-	return &playlistRepository{db: db}
+	return &playlistRepository{db: newDBConn(db)}
+}
+
+func newPlaylistRepository(q querier) *playlistRepository {
+	return &playlistRepository{db: &dbConn{Pool: q}}
 }
 
 func (r *playlistRepository) Create(ctx context.Context, playlist *models.Playlist) error {
 	query := `
-		INSERT INTO playlists (id, title, description, cover_image, creator_id, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO playlists (id, title, description, cover_image, creator_id, is_public, tags, spotify_playlist_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, COALESCE($7::text[], '{}'), $8, $9, $10)
 	`
 
 	_, err := r.db.Pool.Exec(ctx, query,
 		playlist.ID, playlist.Title, playlist.Description, playlist.CoverImage,
-		playlist.CreatorID, playlist.CreatedAt, playlist.UpdatedAt,
+		playlist.CreatorID, playlist.IsPublic, playlist.Tags, playlist.SpotifyPlaylistID, playlist.CreatedAt, playlist.UpdatedAt,
 	)
 
 	if err != nil {
+		if isConstraintViolation(err, spotifyPlaylistImportIndex) {
+			return repository.ErrAlreadyImported
+		}
+		if isUniqueViolation(err) {
+			return fmt.Errorf("playlist %w", repository.ErrDuplicate)
+		}
 		return fmt.Errorf("failed to create playlist: %w", err)
 	}
 
@@ -39,20 +50,20 @@ func (r *playlistRepository) Create(ctx context.Context, playlist *models.Playli
 
 func (r *playlistRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Playlist, error) {
 	query := `
-		SELECT id, title, description, cover_image, creator_id, created_at, updated_at
+		SELECT id, title, description, cover_image, creator_id, is_public, tags, spotify_playlist_id, created_at, updated_at
 		FROM playlists 
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	playlist := &models.Playlist{}
-	err := r.db.Pool.QueryRow(ctx, query, id).Scan(
+	err := r.db.Reader().QueryRow(ctx, query, id).Scan(
 		&playlist.ID, &playlist.Title, &playlist.Description, &playlist.CoverImage,
-		&playlist.CreatorID, &playlist.CreatedAt, &playlist.UpdatedAt,
+		&playlist.CreatorID, &playlist.IsPublic, &playlist.Tags, &playlist.SpotifyPlaylistID, &playlist.CreatedAt, &playlist.UpdatedAt,
 	)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("playlist not found")
+			return nil, fmt.Errorf("playlist %w", repository.ErrNotFound)
 		}
 		return nil, fmt.Errorf("failed to get playlist: %w", err)
 	}
@@ -60,16 +71,55 @@ func (r *playlistRepository) GetByID(ctx context.Context, id uuid.UUID) (*models
 	return playlist, nil
 }
 
+// GetByIDs fetches several playlists in one query, keyed by ID. IDs with no
+// matching playlist are left out of the map.
+func (r *playlistRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.Playlist, error) {
+	playlists := make(map[uuid.UUID]*models.Playlist, len(ids))
+	if len(ids) == 0 {
+		return playlists, nil
+	}
+
+	query := `
+		SELECT id, title, description, cover_image, creator_id, is_public, tags, spotify_playlist_id, created_at, updated_at
+		FROM playlists
+		WHERE id = ANY($1) AND deleted_at IS NULL
+	`
+
+	rows, err := r.db.Reader().Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get playlists: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		playlist := &models.Playlist{}
+		err := rows.Scan(
+			&playlist.ID, &playlist.Title, &playlist.Description, &playlist.CoverImage,
+			&playlist.CreatorID, &playlist.IsPublic, &playlist.Tags, &playlist.SpotifyPlaylistID, &playlist.CreatedAt, &playlist.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan playlist: %w", err)
+		}
+		playlists[playlist.ID] = playlist
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating playlists: %w", err)
+	}
+
+	return playlists, nil
+}
+
 func (r *playlistRepository) GetByCreatorID(ctx context.Context, creatorID uuid.UUID, limit, offset int) ([]*models.Playlist, error) {
 	query := `
-		SELECT id, title, description, cover_image, creator_id, created_at, updated_at
+		SELECT id, title, description, cover_image, creator_id, is_public, tags, spotify_playlist_id, created_at, updated_at
 		FROM playlists 
-		WHERE creator_id = $1
+		WHERE creator_id = $1 AND deleted_at IS NULL
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query, creatorID, limit, offset)
+	rows, err := r.db.Reader().Query(ctx, query, creatorID, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list playlists by creator: %w", err)
 	}
@@ -80,7 +130,7 @@ func (r *playlistRepository) GetByCreatorID(ctx context.Context, creatorID uuid.
 		playlist := &models.Playlist{}
 		err := rows.Scan(
 			&playlist.ID, &playlist.Title, &playlist.Description, &playlist.CoverImage,
-			&playlist.CreatorID, &playlist.CreatedAt, &playlist.UpdatedAt,
+			&playlist.CreatorID, &playlist.IsPublic, &playlist.Tags, &playlist.SpotifyPlaylistID, &playlist.CreatedAt, &playlist.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan playlist: %w", err)
@@ -95,15 +145,58 @@ func (r *playlistRepository) GetByCreatorID(ctx context.Context, creatorID uuid.
 	return playlists, nil
 }
 
+// CountByCreatorID returns how many live playlists the user has, counting
+// only public ones when publicOnly is set
+func (r *playlistRepository) CountByCreatorID(ctx context.Context, creatorID uuid.UUID, publicOnly bool) (int, error) {
+	query := `SELECT COUNT(*) FROM playlists WHERE creator_id = $1 AND deleted_at IS NULL`
+	if publicOnly {
+		query += ` AND is_public = true`
+	}
+
+	var count int
+	if err := r.db.Reader().QueryRow(ctx, query, creatorID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count playlists: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetBySpotifyPlaylistID returns the user's live playlist imported from the
+// given Spotify playlist
+func (r *playlistRepository) GetBySpotifyPlaylistID(ctx context.Context, userID uuid.UUID, spotifyPlaylistID string) (*models.Playlist, error) {
+	query := `
+		SELECT id, title, description, cover_image, creator_id, is_public, tags, spotify_playlist_id, created_at, updated_at
+		FROM playlists
+		WHERE creator_id = $1 AND spotify_playlist_id = $2 AND deleted_at IS NULL
+	`
+
+	playlist := &models.Playlist{}
+	err := r.db.Reader().QueryRow(ctx, query, userID, spotifyPlaylistID).Scan(
+		&playlist.ID, &playlist.Title, &playlist.Description, &playlist.CoverImage,
+		&playlist.CreatorID, &playlist.IsPublic, &playlist.Tags, &playlist.SpotifyPlaylistID, &playlist.CreatedAt, &playlist.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("playlist %w", repository.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get playlist: %w", err)
+	}
+
+	return playlist, nil
+}
+
 func (r *playlistRepository) Update(ctx context.Context, playlist *models.Playlist) error {
 	query := `
 		UPDATE playlists 
-		SET title = $2, description = $3, cover_image = $4, updated_at = NOW()
-		WHERE id = $1
+		SET title = $2, description = $3, cover_image = $4, is_public = $5,
+			tags = COALESCE($6::text[], '{}'), updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	result, err := r.db.Pool.Exec(ctx, query,
 		playlist.ID, playlist.Title, playlist.Description, playlist.CoverImage,
+		playlist.IsPublic, playlist.Tags,
 	)
 
 	if err != nil {
@@ -111,52 +204,68 @@ func (r *playlistRepository) Update(ctx context.Context, playlist *models.Playli
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("playlist not found")
+		return fmt.Errorf("playlist %w", repository.ErrNotFound)
 	}
 
 	return nil
 }
 
+// Delete soft-deletes a playlist. Its tracks are kept so a restored playlist
+// comes back intact; they are removed with the playlist when it is purged.
 func (r *playlistRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	// Start a transaction to delete playlist tracks first, then the playlist
-	tx, err := r.db.Pool.Begin(ctx)
+	query := `UPDATE playlists SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
+
+	result, err := r.db.Pool.Exec(ctx, query, id)
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return fmt.Errorf("failed to delete playlist: %w", err)
 	}
-	defer func() { _ = tx.Rollback(ctx) }()
 
-	// Delete all playlist tracks first
-	_, err = tx.Exec(ctx, `DELETE FROM playlist_tracks WHERE playlist_id = $1`, id)
-	if err != nil {
-		return fmt.Errorf("failed to delete playlist tracks: %w", err)
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("playlist %w", repository.ErrNotFound)
 	}
 
-	// Delete the playlist
-	result, err := tx.Exec(ctx, `DELETE FROM playlists WHERE id = $1`, id)
+	return nil
+}
+
+// Restore undoes a soft delete
+func (r *playlistRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE playlists SET deleted_at = NULL, updated_at = NOW() WHERE id = $1 AND deleted_at IS NOT NULL`
+
+	result, err := r.db.Pool.Exec(ctx, query, id)
 	if err != nil {
-		return fmt.Errorf("failed to delete playlist: %w", err)
+		return fmt.Errorf("failed to restore playlist: %w", err)
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("playlist not found")
+		return fmt.Errorf("playlist %w", repository.ErrNotFound)
 	}
 
-	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+	return nil
+}
+
+// PurgeSoftDeleted permanently removes playlists soft-deleted before
+// olderThan, along with their tracks, and returns how many were removed
+func (r *playlistRepository) PurgeSoftDeleted(ctx context.Context, olderThan time.Time) (int64, error) {
+	query := `DELETE FROM playlists WHERE deleted_at IS NOT NULL AND deleted_at < $1`
+
+	result, err := r.db.Pool.Exec(ctx, query, olderThan)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted playlists: %w", err)
 	}
 
-	return nil
+	return result.RowsAffected(), nil
 }
 
 func (r *playlistRepository) List(ctx context.Context, limit, offset int) ([]*models.Playlist, error) {
 	query := `
-		SELECT id, title, description, cover_image, creator_id, created_at, updated_at
+		SELECT id, title, description, cover_image, creator_id, is_public, tags, spotify_playlist_id, created_at, updated_at
 		FROM playlists 
+		WHERE deleted_at IS NULL
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query, limit, offset)
+	rows, err := r.db.Reader().Query(ctx, query, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list playlists: %w", err)
 	}
@@ -167,7 +276,7 @@ func (r *playlistRepository) List(ctx context.Context, limit, offset int) ([]*mo
 		playlist := &models.Playlist{}
 		err := rows.Scan(
 			&playlist.ID, &playlist.Title, &playlist.Description, &playlist.CoverImage,
-			&playlist.CreatorID, &playlist.CreatedAt, &playlist.UpdatedAt,
+			&playlist.CreatorID, &playlist.IsPublic, &playlist.Tags, &playlist.SpotifyPlaylistID, &playlist.CreatedAt, &playlist.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan playlist: %w", err)
@@ -182,23 +291,130 @@ func (r *playlistRepository) List(ctx context.Context, limit, offset int) ([]*mo
 	return playlists, nil
 }
 
-// Playlist track operations
+// SearchByTitle returns public playlists whose title contains query, matched
+// case-insensitively, along with their creators. An empty query matches nothing.
+func (r *playlistRepository) SearchByTitle(ctx context.Context, query string, limit, offset int) ([]*models.Playlist, error) {
+	if query == "" {
+		return []*models.Playlist{}, nil
+	}
+	limit, offset = clampLimitOffset(limit, offset)
+
+	sqlQuery := `
+		SELECT p.id, p.title, p.description, p.cover_image, p.creator_id, p.is_public, p.tags, p.spotify_playlist_id, p.created_at, p.updated_at,
+			u.id, u.name, u.email, u.bio, u.avatar, u.created_at, u.updated_at
+		FROM playlists p
+		JOIN users u ON u.id = p.creator_id
+		WHERE p.is_public = TRUE AND p.deleted_at IS NULL AND p.title ILIKE '%' || $1 || '%'
+		ORDER BY p.created_at DESC
+		LIMIT $2 OFFSET $3
+	`
 
-func (r *playlistRepository) AddTrack(ctx context.Context, playlistID, trackID uuid.UUID, position int) error {
-	// If position is 0 or negative, append to the end
-	if position <= 0 {
-		var maxPosition int
-		query := `SELECT COALESCE(MAX(position), 0) FROM playlist_tracks WHERE playlist_id = $1`
-		err := r.db.Pool.QueryRow(ctx, query, playlistID).Scan(&maxPosition)
+	rows, err := r.db.Reader().Query(ctx, sqlQuery, escapeLikePattern(query), limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search playlists: %w", err)
+	}
+	defer rows.Close()
+
+	playlists := []*models.Playlist{}
+	for rows.Next() {
+		playlist := &models.Playlist{Creator: &models.User{}}
+		err := rows.Scan(
+			&playlist.ID, &playlist.Title, &playlist.Description, &playlist.CoverImage,
+			&playlist.CreatorID, &playlist.IsPublic, &playlist.Tags, &playlist.SpotifyPlaylistID, &playlist.CreatedAt, &playlist.UpdatedAt,
+			&playlist.Creator.ID, &playlist.Creator.Name, &playlist.Creator.Email, &playlist.Creator.Bio,
+			&playlist.Creator.Avatar, &playlist.Creator.CreatedAt, &playlist.Creator.UpdatedAt,
+		)
 		if err != nil {
-			return fmt.Errorf("failed to get max position: %w", err)
+			return nil, fmt.Errorf("failed to scan playlist: %w", err)
 		}
+		playlists = append(playlists, playlist)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating playlists: %w", err)
+	}
+
+	return playlists, nil
+}
+
+// GetPopularTags returns the most used tags across public playlists, most
+// popular first
+func (r *playlistRepository) GetPopularTags(ctx context.Context, limit int) ([]models.TagCount, error) {
+	limit, _ = clampLimitOffset(limit, 0)
+
+	query := `
+		SELECT tag, COUNT(*) AS usage
+		FROM playlists, unnest(tags) AS tag
+		WHERE is_public = TRUE AND deleted_at IS NULL
+		GROUP BY tag
+		ORDER BY usage DESC, tag ASC
+		LIMIT $1
+	`
+
+	rows, err := r.db.Reader().Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get popular tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []models.TagCount{}
+	for rows.Next() {
+		var tag models.TagCount
+		if err := rows.Scan(&tag.Tag, &tag.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// Playlist track operations
+
+// lockPlaylist takes a row lock on a live playlist for the rest of tx so
+// concurrent track changes compute positions one at a time
+func lockPlaylist(ctx context.Context, tx pgx.Tx, playlistID uuid.UUID) error {
+	query := `SELECT id FROM playlists WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+
+	if err := tx.QueryRow(ctx, query, playlistID).Scan(&playlistID); err != nil {
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("playlist %w", repository.ErrNotFound)
+		}
+		return fmt.Errorf("failed to lock playlist: %w", err)
+	}
+
+	return nil
+}
+
+// AddTrack inserts a track at position, shifting later tracks down. A
+// position of 0 or less, or past the end, appends the track.
+func (r *playlistRepository) AddTrack(ctx context.Context, playlistID, trackID uuid.UUID, position int) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := lockPlaylist(ctx, tx, playlistID); err != nil {
+		return err
+	}
+
+	var maxPosition int
+	query := `SELECT COALESCE(MAX(position), 0) FROM playlist_tracks WHERE playlist_id = $1`
+	if err := tx.QueryRow(ctx, query, playlistID).Scan(&maxPosition); err != nil {
+		return fmt.Errorf("failed to get max position: %w", err)
+	}
+
+	if position <= 0 || position > maxPosition {
 		position = maxPosition + 1
 	} else {
 		// Shift existing tracks to make room
 		shiftQuery := `UPDATE playlist_tracks SET position = position + 1 WHERE playlist_id = $1 AND position >= $2`
-		_, err := r.db.Pool.Exec(ctx, shiftQuery, playlistID, position)
-		if err != nil {
+		if _, err := tx.Exec(ctx, shiftQuery, playlistID, position); err != nil {
 			return fmt.Errorf("failed to shift track positions: %w", err)
 		}
 	}
@@ -208,26 +424,80 @@ func (r *playlistRepository) AddTrack(ctx context.Context, playlistID, trackID u
 		VALUES ($1, $2, $3, $4, NOW())
 	`
 
-	_, err := r.db.Pool.Exec(ctx, insertQuery, uuid.New(), playlistID, trackID, position)
-	if err != nil {
+	if _, err := tx.Exec(ctx, insertQuery, uuid.New(), playlistID, trackID, position); err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("track %w in playlist", repository.ErrDuplicate)
+		}
 		return fmt.Errorf("failed to add track to playlist: %w", err)
 	}
 
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return nil
 }
 
-func (r *playlistRepository) RemoveTrack(ctx context.Context, playlistID, trackID uuid.UUID) error {
-	// Get the position of the track being removed
-	var position int
-	getPositionQuery := `SELECT position FROM playlist_tracks WHERE playlist_id = $1 AND track_id = $2`
-	err := r.db.Pool.QueryRow(ctx, getPositionQuery, playlistID, trackID).Scan(&position)
+// AddTracks appends tracks to the end of a playlist in the given order using
+// a single COPY. Repeated track IDs are added once, at their first position;
+// a track already in the playlist fails the whole batch.
+func (r *playlistRepository) AddTracks(ctx context.Context, playlistID uuid.UUID, tracks []repository.TrackInsert) error {
+	if len(tracks) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Pool.Begin(ctx)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			return fmt.Errorf("track not found in playlist")
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := lockPlaylist(ctx, tx, playlistID); err != nil {
+		return err
+	}
+
+	var maxPosition int
+	query := `SELECT COALESCE(MAX(position), 0) FROM playlist_tracks WHERE playlist_id = $1`
+	if err := tx.QueryRow(ctx, query, playlistID).Scan(&maxPosition); err != nil {
+		return fmt.Errorf("failed to get max position: %w", err)
+	}
+
+	now := time.Now()
+	seen := make(map[uuid.UUID]bool, len(tracks))
+	rows := make([][]any, 0, len(tracks))
+	for _, track := range tracks {
+		if seen[track.TrackID] {
+			continue
 		}
-		return fmt.Errorf("failed to get track position: %w", err)
+		seen[track.TrackID] = true
+
+		addedAt := track.AddedAt
+		if addedAt.IsZero() {
+			addedAt = now
+		}
+		rows = append(rows, []any{uuid.New(), playlistID, track.TrackID, maxPosition + len(rows) + 1, addedAt})
+	}
+
+	_, err = tx.CopyFrom(ctx,
+		pgx.Identifier{"playlist_tracks"},
+		[]string{"id", "playlist_id", "track_id", "position", "added_at"},
+		pgx.CopyFromRows(rows),
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("track %w in playlist", repository.ErrDuplicate)
+		}
+		return fmt.Errorf("failed to add tracks to playlist: %w", err)
 	}
 
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *playlistRepository) RemoveTrack(ctx context.Context, playlistID, trackID uuid.UUID) error {
 	// Start a transaction to remove track and shift positions
 	tx, err := r.db.Pool.Begin(ctx)
 	if err != nil {
@@ -235,6 +505,21 @@ func (r *playlistRepository) RemoveTrack(ctx context.Context, playlistID, trackI
 	}
 	defer func() { _ = tx.Rollback(ctx) }()
 
+	if err := lockPlaylist(ctx, tx, playlistID); err != nil {
+		return err
+	}
+
+	// Get the position of the track being removed
+	var position int
+	getPositionQuery := `SELECT position FROM playlist_tracks WHERE playlist_id = $1 AND track_id = $2`
+	err = tx.QueryRow(ctx, getPositionQuery, playlistID, trackID).Scan(&position)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("track %w in playlist", repository.ErrNotFound)
+		}
+		return fmt.Errorf("failed to get track position: %w", err)
+	}
+
 	// Remove the track
 	removeQuery := `DELETE FROM playlist_tracks WHERE playlist_id = $1 AND track_id = $2`
 	result, err := tx.Exec(ctx, removeQuery, playlistID, trackID)
@@ -243,7 +528,7 @@ func (r *playlistRepository) RemoveTrack(ctx context.Context, playlistID, trackI
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("track not found in playlist")
+		return fmt.Errorf("track %w in playlist", repository.ErrNotFound)
 	}
 
 	// Shift remaining tracks down
@@ -260,23 +545,153 @@ func (r *playlistRepository) RemoveTrack(ctx context.Context, playlistID, trackI
 	return nil
 }
 
+// RemoveTracks removes the tracks with the given Spotify IDs from a playlist
+// in one transaction, then renumbers the remaining tracks 1..n once. IDs that
+// are not in the playlist are ignored.
+func (r *playlistRepository) RemoveTracks(ctx context.Context, playlistID uuid.UUID, spotifyIDs []string) error {
+	if len(spotifyIDs) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := lockPlaylist(ctx, tx, playlistID); err != nil {
+		return err
+	}
+
+	removeQuery := `
+		DELETE FROM playlist_tracks pt
+		USING tracks t
+		WHERE pt.track_id = t.id AND pt.playlist_id = $1 AND t.spotify_id = ANY($2)
+	`
+	result, err := tx.Exec(ctx, removeQuery, playlistID, spotifyIDs)
+	if err != nil {
+		return fmt.Errorf("failed to remove tracks from playlist: %w", err)
+	}
+
+	if result.RowsAffected() > 0 {
+		if err := compactPositions(ctx, tx, playlistID); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ClearTracks removes every track from a playlist
+func (r *playlistRepository) ClearTracks(ctx context.Context, playlistID uuid.UUID) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := lockPlaylist(ctx, tx, playlistID); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM playlist_tracks WHERE playlist_id = $1`, playlistID); err != nil {
+		return fmt.Errorf("failed to clear playlist tracks: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// compactPositions renumbers a playlist's tracks 1..n in their current order,
+// closing the gaps left by removals. The position constraint is deferred, so
+// the single UPDATE may pass through duplicates.
+func compactPositions(ctx context.Context, tx pgx.Tx, playlistID uuid.UUID) error {
+	query := `
+		WITH ranked AS (
+			SELECT id, ROW_NUMBER() OVER (ORDER BY position) AS new_position
+			FROM playlist_tracks
+			WHERE playlist_id = $1
+		)
+		UPDATE playlist_tracks pt
+		SET position = ranked.new_position
+		FROM ranked
+		WHERE pt.id = ranked.id AND pt.position <> ranked.new_position
+	`
+
+	if _, err := tx.Exec(ctx, query, playlistID); err != nil {
+		return fmt.Errorf("failed to compact track positions: %w", err)
+	}
+
+	return nil
+}
+
 func (r *playlistRepository) GetTracks(ctx context.Context, playlistID uuid.UUID, limit, offset int) ([]*models.Track, error) {
 	query := `
 		SELECT t.id, t.spotify_id, t.title, t.album_id, t.duration_ms, t.track_number, t.created_at, t.updated_at
 		FROM tracks t
 		INNER JOIN playlist_tracks pt ON t.id = pt.track_id
+		INNER JOIN playlists p ON p.id = pt.playlist_id AND p.deleted_at IS NULL
 		WHERE pt.playlist_id = $1
 		ORDER BY pt.position ASC
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query, playlistID, limit, offset)
+	return r.queryTracks(ctx, query, playlistID, limit, offset)
+}
+
+// GetTracksDesc returns a page of a playlist's tracks from the last position back
+func (r *playlistRepository) GetTracksDesc(ctx context.Context, playlistID uuid.UUID, limit, offset int) ([]*models.Track, error) {
+	query := `
+		SELECT t.id, t.spotify_id, t.title, t.album_id, t.duration_ms, t.track_number, t.created_at, t.updated_at
+		FROM tracks t
+		INNER JOIN playlist_tracks pt ON t.id = pt.track_id
+		INNER JOIN playlists p ON p.id = pt.playlist_id AND p.deleted_at IS NULL
+		WHERE pt.playlist_id = $1
+		ORDER BY pt.position DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	return r.queryTracks(ctx, query, playlistID, limit, offset)
+}
+
+// GetTracksInRange returns the tracks at positions fromPosition through
+// toPosition inclusive, in position order. At most maxLimit tracks are
+// returned; a range past the end of the playlist returns none.
+func (r *playlistRepository) GetTracksInRange(ctx context.Context, playlistID uuid.UUID, fromPosition, toPosition int) ([]*models.Track, error) {
+	fromPosition = max(fromPosition, 1)
+	toPosition = min(toPosition, fromPosition+maxLimit-1)
+	if toPosition < fromPosition {
+		return []*models.Track{}, nil
+	}
+
+	query := `
+		SELECT t.id, t.spotify_id, t.title, t.album_id, t.duration_ms, t.track_number, t.created_at, t.updated_at
+		FROM tracks t
+		INNER JOIN playlist_tracks pt ON t.id = pt.track_id
+		INNER JOIN playlists p ON p.id = pt.playlist_id AND p.deleted_at IS NULL
+		WHERE pt.playlist_id = $1 AND pt.position BETWEEN $2 AND $3
+		ORDER BY pt.position ASC
+	`
+
+	return r.queryTracks(ctx, query, playlistID, fromPosition, toPosition)
+}
+
+// queryTracks runs a playlist track query and scans the tracks it selects
+func (r *playlistRepository) queryTracks(ctx context.Context, query string, args ...any) ([]*models.Track, error) {
+	rows, err := r.db.Reader().Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get playlist tracks: %w", err)
 	}
 	defer rows.Close()
 
-	var tracks []*models.Track
+	tracks := []*models.Track{}
 	for rows.Next() {
 		track := &models.Track{}
 		err := rows.Scan(
@@ -296,6 +711,80 @@ func (r *playlistRepository) GetTracks(ctx context.Context, playlistID uuid.UUID
 	return tracks, nil
 }
 
+// GetTrackSpotifyIDs returns the Spotify IDs of a playlist's tracks in
+// position order, skipping tracks that have none
+func (r *playlistRepository) GetTrackSpotifyIDs(ctx context.Context, playlistID uuid.UUID) ([]string, error) {
+	query := `
+		SELECT t.spotify_id
+		FROM tracks t
+		INNER JOIN playlist_tracks pt ON t.id = pt.track_id
+		INNER JOIN playlists p ON p.id = pt.playlist_id AND p.deleted_at IS NULL
+		WHERE pt.playlist_id = $1 AND t.spotify_id IS NOT NULL
+		ORDER BY pt.position ASC
+	`
+
+	rows, err := r.db.Reader().Query(ctx, query, playlistID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get playlist track spotify ids: %w", err)
+	}
+	defer rows.Close()
+
+	spotifyIDs := []string{}
+	for rows.Next() {
+		var spotifyID string
+		if err := rows.Scan(&spotifyID); err != nil {
+			return nil, fmt.Errorf("failed to scan track spotify id: %w", err)
+		}
+		spotifyIDs = append(spotifyIDs, spotifyID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating track spotify ids: %w", err)
+	}
+
+	return spotifyIDs, nil
+}
+
+// GetTrackCounts returns how many tracks each playlist has in one query.
+// Every requested ID is in the result, with zero for playlists without tracks.
+func (r *playlistRepository) GetTrackCounts(ctx context.Context, playlistIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	counts := make(map[uuid.UUID]int, len(playlistIDs))
+	if len(playlistIDs) == 0 {
+		return counts, nil
+	}
+	for _, id := range playlistIDs {
+		counts[id] = 0
+	}
+
+	query := `
+		SELECT playlist_id, COUNT(*)
+		FROM playlist_tracks
+		WHERE playlist_id = ANY($1)
+		GROUP BY playlist_id
+	`
+
+	rows, err := r.db.Reader().Query(ctx, query, playlistIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count playlist tracks: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var playlistID uuid.UUID
+		var count int
+		if err := rows.Scan(&playlistID, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan playlist track count: %w", err)
+		}
+		counts[playlistID] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating playlist track counts: %w", err)
+	}
+
+	return counts, nil
+}
+
 func (r *playlistRepository) ReorderTracks(ctx context.Context, playlistID uuid.UUID, trackPositions map[uuid.UUID]int) error {
 	tx, err := r.db.Pool.Begin(ctx)
 	if err != nil {
@@ -303,6 +792,10 @@ func (r *playlistRepository) ReorderTracks(ctx context.Context, playlistID uuid.
 	}
 	defer func() { _ = tx.Rollback(ctx) }()
 
+	if err := lockPlaylist(ctx, tx, playlistID); err != nil {
+		return err
+	}
+
 	for trackID, position := range trackPositions {
 		updateQuery := `UPDATE playlist_tracks SET position = $1 WHERE playlist_id = $2 AND track_id = $3`
 		_, err := tx.Exec(ctx, updateQuery, position, playlistID, trackID)
@@ -317,3 +810,189 @@ func (r *playlistRepository) ReorderTracks(ctx context.Context, playlistID uuid.
 
 	return nil
 }
+
+// MoveTrack moves the track with the given Spotify ID to newPosition and
+// shifts the tracks in between by one so positions stay contiguous. Positions
+// past the end move the track to the end; positions below 1 move it to the
+// start.
+func (r *playlistRepository) MoveTrack(ctx context.Context, playlistID uuid.UUID, spotifyID string, newPosition int) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := lockPlaylist(ctx, tx, playlistID); err != nil {
+		return err
+	}
+
+	var entryID uuid.UUID
+	var position int
+	getPositionQuery := `
+		SELECT pt.id, pt.position
+		FROM playlist_tracks pt
+		INNER JOIN tracks t ON t.id = pt.track_id
+		WHERE pt.playlist_id = $1 AND t.spotify_id = $2
+	`
+	err = tx.QueryRow(ctx, getPositionQuery, playlistID, spotifyID).Scan(&entryID, &position)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("track %w in playlist", repository.ErrNotFound)
+		}
+		return fmt.Errorf("failed to get track position: %w", err)
+	}
+
+	var maxPosition int
+	query := `SELECT COALESCE(MAX(position), 0) FROM playlist_tracks WHERE playlist_id = $1`
+	if err := tx.QueryRow(ctx, query, playlistID).Scan(&maxPosition); err != nil {
+		return fmt.Errorf("failed to get max position: %w", err)
+	}
+
+	newPosition = max(1, min(newPosition, maxPosition))
+	if newPosition == position {
+		return nil
+	}
+
+	// Close the gap left behind and open one at the destination
+	shiftQuery := `
+		UPDATE playlist_tracks SET position = position - 1
+		WHERE playlist_id = $1 AND position > $2 AND position <= $3
+	`
+	if newPosition < position {
+		shiftQuery = `
+			UPDATE playlist_tracks SET position = position + 1
+			WHERE playlist_id = $1 AND position >= $3 AND position < $2
+		`
+	}
+	if _, err := tx.Exec(ctx, shiftQuery, playlistID, position, newPosition); err != nil {
+		return fmt.Errorf("failed to shift track positions: %w", err)
+	}
+
+	moveQuery := `UPDATE playlist_tracks SET position = $1 WHERE id = $2`
+	if _, err := tx.Exec(ctx, moveQuery, newPosition, entryID); err != nil {
+		return fmt.Errorf("failed to move track: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// AddCollaborator lets a user edit a playlist; adding an existing
+// collaborator again is a no-op
+func (r *playlistRepository) AddCollaborator(ctx context.Context, playlistID, userID uuid.UUID) error {
+	query := `
+		INSERT INTO playlist_collaborators (playlist_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (playlist_id, user_id) DO NOTHING
+	`
+
+	if _, err := r.db.Pool.Exec(ctx, query, playlistID, userID); err != nil {
+		return fmt.Errorf("failed to add playlist collaborator: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveCollaborator revokes a collaborator's edit access if they had it
+func (r *playlistRepository) RemoveCollaborator(ctx context.Context, playlistID, userID uuid.UUID) error {
+	query := `DELETE FROM playlist_collaborators WHERE playlist_id = $1 AND user_id = $2`
+
+	if _, err := r.db.Pool.Exec(ctx, query, playlistID, userID); err != nil {
+		return fmt.Errorf("failed to remove playlist collaborator: %w", err)
+	}
+
+	return nil
+}
+
+// IsCollaborator reports whether the user was added as a collaborator. It
+// reads from the primary so a collaborator removed moments ago is not still
+// seen on a lagging replica.
+func (r *playlistRepository) IsCollaborator(ctx context.Context, playlistID, userID uuid.UUID) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM playlist_collaborators WHERE playlist_id = $1 AND user_id = $2)`
+
+	var exists bool
+	if err := r.db.Pool.QueryRow(ctx, query, playlistID, userID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check playlist collaborator: %w", err)
+	}
+
+	return exists, nil
+}
+
+// CanEdit reports whether the user created the live playlist or collaborates
+// on it. Like IsCollaborator it reads from the primary, as it guards writes.
+func (r *playlistRepository) CanEdit(ctx context.Context, playlistID, userID uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM playlists p
+			WHERE p.id = $1 AND p.deleted_at IS NULL
+				AND (p.creator_id = $2 OR EXISTS(
+					SELECT 1 FROM playlist_collaborators pc
+					WHERE pc.playlist_id = p.id AND pc.user_id = $2
+				))
+		)
+	`
+
+	var canEdit bool
+	if err := r.db.Pool.QueryRow(ctx, query, playlistID, userID).Scan(&canEdit); err != nil {
+		return false, fmt.Errorf("failed to check playlist permissions: %w", err)
+	}
+
+	return canEdit, nil
+}
+
+// ListConnection returns a page of playlists, newest first, using keyset
+// pagination on (created_at, id) starting after the given cursor
+func (r *playlistRepository) ListConnection(ctx context.Context, first int, after *string) (*models.Connection[models.Playlist], error) {
+	first, _ = clampLimitOffset(first, 0)
+
+	afterCreatedAt, afterID, err := keysetArgs(after)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT id, title, description, cover_image, creator_id, is_public, tags, spotify_playlist_id, created_at, updated_at,
+			(SELECT COUNT(*) FROM playlists WHERE deleted_at IS NULL)
+		FROM playlists
+		WHERE deleted_at IS NULL
+			AND ($1::timestamptz IS NULL OR (created_at, id) < ($1::timestamptz, $2::uuid))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3
+	`
+
+	rows, err := r.db.Reader().Query(ctx, query, afterCreatedAt, afterID, first+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list playlists: %w", err)
+	}
+	defer rows.Close()
+
+	var playlists []models.Playlist
+	var total int
+	for rows.Next() {
+		var playlist models.Playlist
+		err := rows.Scan(
+			&playlist.ID, &playlist.Title, &playlist.Description, &playlist.CoverImage,
+			&playlist.CreatorID, &playlist.IsPublic, &playlist.Tags, &playlist.SpotifyPlaylistID, &playlist.CreatedAt, &playlist.UpdatedAt, &total,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan playlist: %w", err)
+		}
+		playlists = append(playlists, playlist)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating playlists: %w", err)
+	}
+
+	total, err = connectionTotal(ctx, r.db.Reader(), len(playlists), total, `SELECT COUNT(*) FROM playlists WHERE deleted_at IS NULL`)
+	if err != nil {
+		return nil, err
+	}
+
+	return newConnection(playlists, first, total, afterCreatedAt != nil, func(playlist models.Playlist) (time.Time, uuid.UUID) {
+		return playlist.CreatedAt, playlist.ID
+	}), nil
+}
@@ -0,0 +1,1144 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/daedal00/muse/backend/internal/models"
+	"github.com/daedal00/muse/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// setupTestPlaylist creates a public test playlist for repository tests
+func setupTestPlaylist(t *testing.T, creatorID uuid.UUID, title string) *models.Playlist {
+	t.Helper()
+
+	return &models.Playlist{
+		ID:          uuid.New(),
+		Title:       title,
+		Description: stringPtr("Test playlist"),
+		CreatorID:   creatorID,
+		IsPublic:    true,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+}
+
+// setupPlaylistTrackFixtures creates a playlist owned by a new user and the
+// given number of tracks on a new album, returning the playlist and track IDs
+// along with a cleanup function
+func setupPlaylistTrackFixtures(t testing.TB, ctx context.Context, tracks int) (uuid.UUID, []uuid.UUID, func()) {
+	t.Helper()
+
+	artist := setupTestArtist(t)
+	if err := NewArtistRepository(testDB).Create(ctx, artist); err != nil {
+		t.Fatalf("Failed to create test artist: %v", err)
+	}
+
+	user := setupTestUser(t)
+	cleanup := func() {
+		cleanupTestUser(t, ctx, user.ID)
+		cleanupTestArtist(t, ctx, artist.ID)
+	}
+
+	if err := NewUserRepository(testDB).Create(ctx, user); err != nil {
+		cleanup()
+		t.Fatalf("Failed to create test user: %v", err)
+	}
+
+	album := setupTestAlbum(t, artist.ID)
+	if err := NewAlbumRepository(testDB).Create(ctx, album); err != nil {
+		cleanup()
+		t.Fatalf("Failed to create test album: %v", err)
+	}
+
+	playlist := &models.Playlist{
+		ID:        uuid.New(),
+		Title:     "Track fixture",
+		CreatorID: user.ID,
+		IsPublic:  true,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := NewPlaylistRepository(testDB).Create(ctx, playlist); err != nil {
+		cleanup()
+		t.Fatalf("Failed to create test playlist: %v", err)
+	}
+
+	trackRepo := NewTrackRepository(testDB)
+	trackIDs := make([]uuid.UUID, tracks)
+	for i := range trackIDs {
+		// Each fixture track's Spotify ID is its own UUID string
+		id := uuid.New()
+		spotifyID := id.String()
+		track := &models.Track{
+			ID:        id,
+			SpotifyID: &spotifyID,
+			Title:     fmt.Sprintf("Track %d", i+1),
+			AlbumID:   album.ID,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+		if err := trackRepo.Create(ctx, track); err != nil {
+			cleanup()
+			t.Fatalf("Failed to create test track: %v", err)
+		}
+		trackIDs[i] = track.ID
+	}
+
+	return playlist.ID, trackIDs, cleanup
+}
+
+// playlistTrackPositions returns the playlist's track IDs and positions in position order
+func playlistTrackPositions(t testing.TB, ctx context.Context, playlistID uuid.UUID) ([]uuid.UUID, []int) {
+	t.Helper()
+
+	rows, err := testDB.Pool.Query(ctx,
+		`SELECT track_id, position FROM playlist_tracks WHERE playlist_id = $1 ORDER BY position`, playlistID)
+	if err != nil {
+		t.Fatalf("Failed to query playlist tracks: %v", err)
+	}
+	defer rows.Close()
+
+	var trackIDs []uuid.UUID
+	var positions []int
+	for rows.Next() {
+		var trackID uuid.UUID
+		var position int
+		if err := rows.Scan(&trackID, &position); err != nil {
+			t.Fatalf("Failed to scan playlist track: %v", err)
+		}
+		trackIDs = append(trackIDs, trackID)
+		positions = append(positions, position)
+	}
+
+	if err := rows.Err(); err != nil {
+		t.Fatalf("Failed to iterate playlist tracks: %v", err)
+	}
+
+	return trackIDs, positions
+}
+
+func TestPlaylistRepository_SearchByTitle(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewPlaylistRepository(testDB)
+	userRepo := NewUserRepository(testDB)
+	ctx := context.Background()
+
+	user := setupTestUser(t)
+	if err := userRepo.Create(ctx, user); err != nil {
+		t.Fatalf("Failed to create test user: %v", err)
+	}
+	defer cleanupTestUser(t, ctx, user.ID)
+
+	marker := uuid.New().String()[:8]
+	public := setupTestPlaylist(t, user.ID, fmt.Sprintf("Late Night %s Drive", marker))
+	private := setupTestPlaylist(t, user.ID, fmt.Sprintf("Late Night %s Secret", marker))
+	private.IsPublic = false
+	other := setupTestPlaylist(t, user.ID, "Something Else")
+
+	for _, playlist := range []*models.Playlist{public, private, other} {
+		if err := repo.Create(ctx, playlist); err != nil {
+			t.Fatalf("Failed to create playlist: %v", err)
+		}
+	}
+
+	// Matching is case-insensitive and excludes private playlists
+	results, err := repo.SearchByTitle(ctx, "late night "+marker, 10, 0)
+	if err != nil {
+		t.Fatalf("Failed to search playlists: %v", err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 playlist, got %d", len(results))
+	}
+
+	if results[0].ID != public.ID {
+		t.Errorf("Expected playlist %s, got %s", public.ID, results[0].ID)
+	}
+
+	if results[0].Creator == nil || results[0].Creator.ID != user.ID {
+		t.Error("Expected creator to be populated")
+	}
+}
+
+func TestPlaylistRepository_SearchByTitle_EmptyQuery(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewPlaylistRepository(testDB)
+	ctx := context.Background()
+
+	results, err := repo.SearchByTitle(ctx, "", 10, 0)
+	if err != nil {
+		t.Fatalf("Failed to search playlists: %v", err)
+	}
+
+	if results == nil || len(results) != 0 {
+		t.Errorf("Expected empty slice for empty query, got %v", results)
+	}
+}
+
+func TestPlaylistRepository_GetPopularTags(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	ctx := context.Background()
+	tx, err := testDB.Pool.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Other tests' tags are cleared inside a transaction that is rolled back
+	// afterwards, so only this test's tags are counted
+	if _, err := tx.Exec(ctx, `UPDATE playlists SET tags = '{}'`); err != nil {
+		t.Fatalf("Failed to clear tags: %v", err)
+	}
+
+	repo := newPlaylistRepository(tx)
+	user := setupTestUser(t)
+	if err := newUserRepository(tx).Create(ctx, user); err != nil {
+		t.Fatalf("Failed to create test user: %v", err)
+	}
+
+	tagSets := [][]string{
+		{"common", "mid", "rare"},
+		{"common", "mid"},
+		{"common"},
+	}
+	for i, tags := range tagSets {
+		playlist := setupTestPlaylist(t, user.ID, fmt.Sprintf("Tagged %d", i))
+		playlist.Tags = tags
+		if err := repo.Create(ctx, playlist); err != nil {
+			t.Fatalf("Failed to create playlist: %v", err)
+		}
+	}
+
+	// Tags on private playlists must not count
+	private := setupTestPlaylist(t, user.ID, "Private tagged")
+	private.IsPublic = false
+	private.Tags = []string{"rare", "hidden"}
+	if err := repo.Create(ctx, private); err != nil {
+		t.Fatalf("Failed to create playlist: %v", err)
+	}
+
+	tags, err := repo.GetPopularTags(ctx, 100)
+	if err != nil {
+		t.Fatalf("Failed to get popular tags: %v", err)
+	}
+
+	expected := []models.TagCount{{Tag: "common", Count: 3}, {Tag: "mid", Count: 2}, {Tag: "rare", Count: 1}}
+	if len(tags) != len(expected) {
+		t.Fatalf("Expected tags %v, got %v", expected, tags)
+	}
+	for i, tag := range expected {
+		if tags[i] != tag {
+			t.Errorf("Expected %v at position %d, got %v", tag, i, tags[i])
+		}
+	}
+}
+
+func TestPlaylistRepository_GetPopularTags_NoTags(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	ctx := context.Background()
+	tx, err := testDB.Pool.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Clear every tag inside a transaction that is rolled back afterwards
+	if _, err := tx.Exec(ctx, `UPDATE playlists SET tags = '{}'`); err != nil {
+		t.Fatalf("Failed to clear tags: %v", err)
+	}
+
+	tags, err := newPlaylistRepository(tx).GetPopularTags(ctx, 10)
+	if err != nil {
+		t.Fatalf("Failed to get popular tags: %v", err)
+	}
+	if tags == nil || len(tags) != 0 {
+		t.Errorf("Expected an empty, non-nil tag list, got %#v", tags)
+	}
+}
+
+func TestPlaylistRepository_GetByID_NotFound(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewPlaylistRepository(testDB)
+	ctx := context.Background()
+
+	_, err := repo.GetByID(ctx, uuid.New())
+	if !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestPlaylistRepository_SoftDelete(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewPlaylistRepository(testDB)
+	userRepo := NewUserRepository(testDB)
+	ctx := context.Background()
+
+	user := setupTestUser(t)
+	if err := userRepo.Create(ctx, user); err != nil {
+		t.Fatalf("Failed to create test user: %v", err)
+	}
+	defer cleanupTestUser(t, ctx, user.ID)
+
+	marker := uuid.New().String()[:8]
+	playlist := setupTestPlaylist(t, user.ID, "Deleted "+marker)
+	if err := repo.Create(ctx, playlist); err != nil {
+		t.Fatalf("Failed to create playlist: %v", err)
+	}
+
+	if err := repo.Delete(ctx, playlist.ID); err != nil {
+		t.Fatalf("Failed to delete playlist: %v", err)
+	}
+
+	// Soft-deleted playlists are hidden from lookups and search
+	if _, err := repo.GetByID(ctx, playlist.ID); err == nil {
+		t.Error("Expected error when getting deleted playlist")
+	}
+
+	results, err := repo.SearchByTitle(ctx, marker, 10, 0)
+	if err != nil {
+		t.Fatalf("Failed to search playlists: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("Expected deleted playlist to be excluded from search, got %d results", len(results))
+	}
+
+	if err := repo.Restore(ctx, playlist.ID); err != nil {
+		t.Fatalf("Failed to restore playlist: %v", err)
+	}
+	if _, err := repo.GetByID(ctx, playlist.ID); err != nil {
+		t.Errorf("Expected restored playlist to be found: %v", err)
+	}
+
+	if err := repo.Restore(ctx, playlist.ID); err == nil {
+		t.Error("Expected error when restoring a playlist that is not deleted")
+	}
+
+	if err := repo.Delete(ctx, playlist.ID); err != nil {
+		t.Fatalf("Failed to delete playlist: %v", err)
+	}
+
+	purged, err := repo.PurgeSoftDeleted(ctx, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Failed to purge playlists: %v", err)
+	}
+	if purged < 1 {
+		t.Errorf("Expected at least 1 purged playlist, got %d", purged)
+	}
+
+	if err := repo.Restore(ctx, playlist.ID); err == nil {
+		t.Error("Expected error when restoring a purged playlist")
+	}
+}
+
+func TestPlaylistRepository_CountByCreatorID(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewPlaylistRepository(testDB)
+	userRepo := NewUserRepository(testDB)
+	ctx := context.Background()
+
+	user := setupTestUser(t)
+	if err := userRepo.Create(ctx, user); err != nil {
+		t.Fatalf("Failed to create test user: %v", err)
+	}
+	defer cleanupTestUser(t, ctx, user.ID)
+
+	for i, public := range []bool{true, true, false, true, false} {
+		playlist := setupTestPlaylist(t, user.ID, fmt.Sprintf("Counted %d", i))
+		playlist.IsPublic = public
+		if err := repo.Create(ctx, playlist); err != nil {
+			t.Fatalf("Failed to create playlist: %v", err)
+		}
+	}
+
+	// Deleted playlists are not counted
+	deleted := setupTestPlaylist(t, user.ID, "Deleted")
+	if err := repo.Create(ctx, deleted); err != nil {
+		t.Fatalf("Failed to create playlist: %v", err)
+	}
+	if err := repo.Delete(ctx, deleted.ID); err != nil {
+		t.Fatalf("Failed to delete playlist: %v", err)
+	}
+
+	all, err := repo.CountByCreatorID(ctx, user.ID, false)
+	if err != nil {
+		t.Fatalf("Failed to count playlists: %v", err)
+	}
+	if all != 5 {
+		t.Errorf("Expected 5 playlists, got %d", all)
+	}
+
+	public, err := repo.CountByCreatorID(ctx, user.ID, true)
+	if err != nil {
+		t.Fatalf("Failed to count public playlists: %v", err)
+	}
+	if public != 3 {
+		t.Errorf("Expected 3 public playlists, got %d", public)
+	}
+}
+
+func TestPlaylistRepository_SpotifyImport(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewPlaylistRepository(testDB)
+	userRepo := NewUserRepository(testDB)
+	ctx := context.Background()
+
+	user := setupTestUser(t)
+	if err := userRepo.Create(ctx, user); err != nil {
+		t.Fatalf("Failed to create test user: %v", err)
+	}
+	defer cleanupTestUser(t, ctx, user.ID)
+
+	spotifyID := "spotify-" + uuid.New().String()[:8]
+	if _, err := repo.GetBySpotifyPlaylistID(ctx, user.ID, spotifyID); err == nil {
+		t.Error("Expected error before the playlist is imported")
+	}
+
+	imported := setupTestPlaylist(t, user.ID, "Imported")
+	imported.SpotifyPlaylistID = &spotifyID
+	if err := repo.Create(ctx, imported); err != nil {
+		t.Fatalf("Failed to create imported playlist: %v", err)
+	}
+
+	found, err := repo.GetBySpotifyPlaylistID(ctx, user.ID, spotifyID)
+	if err != nil {
+		t.Fatalf("Failed to get playlist by Spotify ID: %v", err)
+	}
+	if found.ID != imported.ID {
+		t.Errorf("Expected playlist %s, got %s", imported.ID, found.ID)
+	}
+	if found.SpotifyPlaylistID == nil || *found.SpotifyPlaylistID != spotifyID {
+		t.Errorf("Expected Spotify playlist ID %q, got %v", spotifyID, found.SpotifyPlaylistID)
+	}
+
+	// Importing the same Spotify playlist again is rejected
+	again := setupTestPlaylist(t, user.ID, "Imported again")
+	again.SpotifyPlaylistID = &spotifyID
+	if err := repo.Create(ctx, again); err != repository.ErrAlreadyImported {
+		t.Errorf("Expected ErrAlreadyImported on re-import, got %v", err)
+	}
+
+	// Once the first import is deleted the playlist can be imported again
+	if err := repo.Delete(ctx, imported.ID); err != nil {
+		t.Fatalf("Failed to delete playlist: %v", err)
+	}
+	if err := repo.Create(ctx, again); err != nil {
+		t.Errorf("Expected re-import after delete to succeed: %v", err)
+	}
+
+	// Other unique violations, such as a reused ID, are not reported as imports
+	clash := setupTestPlaylist(t, user.ID, "Clashing ID")
+	clash.ID = again.ID
+	err = repo.Create(ctx, clash)
+	if !errors.Is(err, repository.ErrDuplicate) || errors.Is(err, repository.ErrAlreadyImported) {
+		t.Errorf("Expected ErrDuplicate but not ErrAlreadyImported for a reused ID, got %v", err)
+	}
+}
+
+func TestPlaylistRepository_AddTracks(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewPlaylistRepository(testDB)
+	ctx := context.Background()
+
+	playlistID, trackIDs, cleanup := setupPlaylistTrackFixtures(t, ctx, 6)
+	defer cleanup()
+
+	// An existing track keeps position 1; the batch is appended after it
+	if err := repo.AddTrack(ctx, playlistID, trackIDs[0], 0); err != nil {
+		t.Fatalf("Failed to add track: %v", err)
+	}
+
+	batch := []repository.TrackInsert{
+		{TrackID: trackIDs[4]},
+		{TrackID: trackIDs[2]},
+		{TrackID: trackIDs[5]},
+		{TrackID: trackIDs[2]}, // duplicate, skipped
+		{TrackID: trackIDs[1]},
+	}
+	if err := repo.AddTracks(ctx, playlistID, batch); err != nil {
+		t.Fatalf("Failed to add tracks: %v", err)
+	}
+
+	gotIDs, positions := playlistTrackPositions(t, ctx, playlistID)
+	expected := []uuid.UUID{trackIDs[0], trackIDs[4], trackIDs[2], trackIDs[5], trackIDs[1]}
+
+	if len(gotIDs) != len(expected) {
+		t.Fatalf("Expected %d tracks, got %d", len(expected), len(gotIDs))
+	}
+	for i := range expected {
+		if gotIDs[i] != expected[i] {
+			t.Errorf("Expected track %s at position %d, got %s", expected[i], i+1, gotIDs[i])
+		}
+		if positions[i] != i+1 {
+			t.Errorf("Expected contiguous position %d, got %d", i+1, positions[i])
+		}
+	}
+
+	// A batch containing a track already in the playlist is rejected as a whole
+	err := repo.AddTracks(ctx, playlistID, []repository.TrackInsert{{TrackID: trackIDs[3]}, {TrackID: trackIDs[0]}})
+	if err == nil {
+		t.Error("Expected error when adding a track already in the playlist")
+	}
+	if gotIDs, _ := playlistTrackPositions(t, ctx, playlistID); len(gotIDs) != len(expected) {
+		t.Errorf("Expected failed batch to add nothing, got %d tracks", len(gotIDs))
+	}
+}
+
+func TestPlaylistRepository_AddTracks_MissingPlaylist(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewPlaylistRepository(testDB)
+
+	err := repo.AddTracks(context.Background(), uuid.New(), []repository.TrackInsert{{TrackID: uuid.New()}})
+	if err == nil {
+		t.Error("Expected error when adding tracks to a missing playlist")
+	}
+}
+
+func TestPlaylistRepository_AddTrack_ConcurrentAppends(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewPlaylistRepository(testDB)
+	ctx := context.Background()
+
+	playlistID, trackIDs, cleanup := setupPlaylistTrackFixtures(t, ctx, 10)
+	defer cleanup()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(trackIDs))
+	for _, trackID := range trackIDs {
+		wg.Add(1)
+		go func(trackID uuid.UUID) {
+			defer wg.Done()
+			errs <- repo.AddTrack(ctx, playlistID, trackID, 0)
+		}(trackID)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Failed to add track: %v", err)
+		}
+	}
+
+	gotIDs, positions := playlistTrackPositions(t, ctx, playlistID)
+	if len(gotIDs) != len(trackIDs) {
+		t.Fatalf("Expected %d tracks, got %d", len(trackIDs), len(gotIDs))
+	}
+
+	// Positions are distinct and run 1..n with no gaps
+	for i, position := range positions {
+		if position != i+1 {
+			t.Errorf("Expected position %d, got %d", i+1, position)
+		}
+	}
+}
+
+func TestPlaylistRepository_RemoveTrack_ConcurrentRemovals(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewPlaylistRepository(testDB)
+	ctx := context.Background()
+
+	playlistID, trackIDs, cleanup := setupPlaylistTrackFixtures(t, ctx, 10)
+	defer cleanup()
+
+	for _, trackID := range trackIDs {
+		if err := repo.AddTrack(ctx, playlistID, trackID, 0); err != nil {
+			t.Fatalf("Failed to add track: %v", err)
+		}
+	}
+
+	// Remove every other track at once
+	var wg sync.WaitGroup
+	errs := make(chan error, len(trackIDs))
+	for i := 0; i < len(trackIDs); i += 2 {
+		wg.Add(1)
+		go func(trackID uuid.UUID) {
+			defer wg.Done()
+			errs <- repo.RemoveTrack(ctx, playlistID, trackID)
+		}(trackIDs[i])
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Failed to remove track: %v", err)
+		}
+	}
+
+	gotIDs, positions := playlistTrackPositions(t, ctx, playlistID)
+	if len(gotIDs) != len(trackIDs)/2 {
+		t.Fatalf("Expected %d tracks, got %d", len(trackIDs)/2, len(gotIDs))
+	}
+	for i, position := range positions {
+		if gotIDs[i] != trackIDs[2*i+1] || position != i+1 {
+			t.Errorf("Expected track %s at position %d, got %s at %d", trackIDs[2*i+1], i+1, gotIDs[i], position)
+		}
+	}
+}
+
+func TestPlaylistRepository_AddTrack_InsertShiftsLaterTracks(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewPlaylistRepository(testDB)
+	ctx := context.Background()
+
+	playlistID, trackIDs, cleanup := setupPlaylistTrackFixtures(t, ctx, 4)
+	defer cleanup()
+
+	for _, trackID := range trackIDs[:3] {
+		if err := repo.AddTrack(ctx, playlistID, trackID, 0); err != nil {
+			t.Fatalf("Failed to add track: %v", err)
+		}
+	}
+
+	if err := repo.AddTrack(ctx, playlistID, trackIDs[3], 2); err != nil {
+		t.Fatalf("Failed to insert track: %v", err)
+	}
+
+	gotIDs, positions := playlistTrackPositions(t, ctx, playlistID)
+	expected := []uuid.UUID{trackIDs[0], trackIDs[3], trackIDs[1], trackIDs[2]}
+	if len(gotIDs) != len(expected) {
+		t.Fatalf("Expected %d tracks, got %d", len(expected), len(gotIDs))
+	}
+	for i := range expected {
+		if gotIDs[i] != expected[i] || positions[i] != i+1 {
+			t.Errorf("Expected track %s at position %d, got %s at %d", expected[i], i+1, gotIDs[i], positions[i])
+		}
+	}
+}
+
+func TestPlaylistRepository_MoveTrack(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewPlaylistRepository(testDB)
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		from        int
+		newPosition int
+		expected    []int // fixture track indexes in position order
+	}{
+		{name: "forward", from: 1, newPosition: 4, expected: []int{0, 2, 3, 1, 4}},
+		{name: "backward", from: 3, newPosition: 1, expected: []int{0, 3, 1, 2, 4}},
+		{name: "to start", from: 4, newPosition: 1, expected: []int{4, 0, 1, 2, 3}},
+		{name: "to end", from: 0, newPosition: 5, expected: []int{1, 2, 3, 4, 0}},
+		{name: "past end clamps", from: 2, newPosition: 99, expected: []int{0, 1, 3, 4, 2}},
+		{name: "before start clamps", from: 2, newPosition: 0, expected: []int{2, 0, 1, 3, 4}},
+		{name: "same position", from: 2, newPosition: 3, expected: []int{0, 1, 2, 3, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			playlistID, trackIDs, cleanup := setupPlaylistTrackFixtures(t, ctx, 5)
+			defer cleanup()
+
+			for _, trackID := range trackIDs {
+				if err := repo.AddTrack(ctx, playlistID, trackID, 0); err != nil {
+					t.Fatalf("Failed to add track: %v", err)
+				}
+			}
+
+			if err := repo.MoveTrack(ctx, playlistID, trackIDs[tt.from].String(), tt.newPosition); err != nil {
+				t.Fatalf("Failed to move track: %v", err)
+			}
+
+			gotIDs, positions := playlistTrackPositions(t, ctx, playlistID)
+			if len(gotIDs) != len(tt.expected) {
+				t.Fatalf("Expected %d tracks, got %d", len(tt.expected), len(gotIDs))
+			}
+			for i, index := range tt.expected {
+				if gotIDs[i] != trackIDs[index] || positions[i] != i+1 {
+					t.Errorf("Expected track %s at position %d, got %s at %d", trackIDs[index], i+1, gotIDs[i], positions[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPlaylistRepository_MoveTrack_NotInPlaylist(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewPlaylistRepository(testDB)
+	ctx := context.Background()
+
+	playlistID, _, cleanup := setupPlaylistTrackFixtures(t, ctx, 1)
+	defer cleanup()
+
+	if err := repo.MoveTrack(ctx, playlistID, "missing-spotify-id", 1); err == nil {
+		t.Error("Expected error moving a track that is not in the playlist")
+	}
+}
+
+func TestPlaylistRepository_RemoveTracks(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewPlaylistRepository(testDB)
+	ctx := context.Background()
+
+	playlistID, trackIDs, cleanup := setupPlaylistTrackFixtures(t, ctx, 6)
+	defer cleanup()
+
+	for _, trackID := range trackIDs {
+		if err := repo.AddTrack(ctx, playlistID, trackID, 0); err != nil {
+			t.Fatalf("Failed to add track: %v", err)
+		}
+	}
+
+	// Remove the first, two middle and last tracks, plus an ID not in the playlist
+	remove := []string{trackIDs[0].String(), trackIDs[2].String(), trackIDs[3].String(), trackIDs[5].String(), "missing-spotify-id"}
+	if err := repo.RemoveTracks(ctx, playlistID, remove); err != nil {
+		t.Fatalf("Failed to remove tracks: %v", err)
+	}
+
+	gotIDs, positions := playlistTrackPositions(t, ctx, playlistID)
+	expected := []uuid.UUID{trackIDs[1], trackIDs[4]}
+	if len(gotIDs) != len(expected) {
+		t.Fatalf("Expected %d remaining tracks, got %d", len(expected), len(gotIDs))
+	}
+	for i, trackID := range expected {
+		if gotIDs[i] != trackID || positions[i] != i+1 {
+			t.Errorf("Expected track %s at position %d, got %s at %d", trackID, i+1, gotIDs[i], positions[i])
+		}
+	}
+
+	// Nothing to remove is a no-op
+	if err := repo.RemoveTracks(ctx, playlistID, nil); err != nil {
+		t.Errorf("Expected no error removing no tracks, got %v", err)
+	}
+}
+
+func TestPlaylistRepository_ClearTracks(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewPlaylistRepository(testDB)
+	ctx := context.Background()
+
+	playlistID, trackIDs, cleanup := setupPlaylistTrackFixtures(t, ctx, 4)
+	defer cleanup()
+
+	for _, trackID := range trackIDs {
+		if err := repo.AddTrack(ctx, playlistID, trackID, 0); err != nil {
+			t.Fatalf("Failed to add track: %v", err)
+		}
+	}
+
+	if err := repo.ClearTracks(ctx, playlistID); err != nil {
+		t.Fatalf("Failed to clear tracks: %v", err)
+	}
+
+	if gotIDs, _ := playlistTrackPositions(t, ctx, playlistID); len(gotIDs) != 0 {
+		t.Errorf("Expected an empty playlist, got %d tracks", len(gotIDs))
+	}
+
+	// The cleared playlist accepts tracks again from position 1
+	if err := repo.AddTrack(ctx, playlistID, trackIDs[0], 0); err != nil {
+		t.Fatalf("Failed to add track: %v", err)
+	}
+	if _, positions := playlistTrackPositions(t, ctx, playlistID); len(positions) != 1 || positions[0] != 1 {
+		t.Errorf("Expected one track at position 1, got %v", positions)
+	}
+
+	if err := repo.ClearTracks(ctx, uuid.New()); err == nil {
+		t.Error("Expected error clearing a playlist that does not exist")
+	}
+}
+
+func TestPlaylistRepository_GetTracks_DeletedPlaylist(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewPlaylistRepository(testDB)
+	ctx := context.Background()
+
+	playlistID, trackIDs, cleanup := setupPlaylistTrackFixtures(t, ctx, 3)
+	defer cleanup()
+
+	for _, trackID := range trackIDs {
+		if err := repo.AddTrack(ctx, playlistID, trackID, 0); err != nil {
+			t.Fatalf("Failed to add track: %v", err)
+		}
+	}
+
+	if err := repo.Delete(ctx, playlistID); err != nil {
+		t.Fatalf("Failed to delete playlist: %v", err)
+	}
+
+	// A soft-deleted playlist's tracks are hidden like the playlist itself
+	tracks, err := repo.GetTracks(ctx, playlistID, 10, 0)
+	if err != nil {
+		t.Fatalf("Failed to get tracks: %v", err)
+	}
+	if len(tracks) != 0 {
+		t.Errorf("Expected no tracks for a deleted playlist, got %d", len(tracks))
+	}
+
+	ids, err := repo.GetTrackSpotifyIDs(ctx, playlistID)
+	if err != nil {
+		t.Fatalf("Failed to get track Spotify IDs: %v", err)
+	}
+	if len(ids) != 0 {
+		t.Errorf("Expected no Spotify IDs for a deleted playlist, got %d", len(ids))
+	}
+}
+
+func TestPlaylistRepository_GetTracksDesc(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewPlaylistRepository(testDB)
+	ctx := context.Background()
+
+	playlistID, trackIDs, cleanup := setupPlaylistTrackFixtures(t, ctx, 5)
+	defer cleanup()
+
+	for _, trackID := range trackIDs {
+		if err := repo.AddTrack(ctx, playlistID, trackID, 0); err != nil {
+			t.Fatalf("Failed to add track: %v", err)
+		}
+	}
+
+	tracks, err := repo.GetTracksDesc(ctx, playlistID, 3, 1)
+	if err != nil {
+		t.Fatalf("Failed to get tracks: %v", err)
+	}
+
+	expected := []uuid.UUID{trackIDs[3], trackIDs[2], trackIDs[1]}
+	if len(tracks) != len(expected) {
+		t.Fatalf("Expected %d tracks, got %d", len(expected), len(tracks))
+	}
+	for i, trackID := range expected {
+		if tracks[i].ID != trackID {
+			t.Errorf("Expected track %s at index %d, got %s", trackID, i, tracks[i].ID)
+		}
+	}
+}
+
+func TestPlaylistRepository_GetTracksInRange(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewPlaylistRepository(testDB)
+	ctx := context.Background()
+
+	playlistID, trackIDs, cleanup := setupPlaylistTrackFixtures(t, ctx, 6)
+	defer cleanup()
+
+	for _, trackID := range trackIDs {
+		if err := repo.AddTrack(ctx, playlistID, trackID, 0); err != nil {
+			t.Fatalf("Failed to add track: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		from, to int
+		expected []int // fixture track indexes in position order
+	}{
+		{name: "bounded range", from: 2, to: 4, expected: []int{1, 2, 3}},
+		{name: "single position", from: 5, to: 5, expected: []int{4}},
+		{name: "runs past end", from: 5, to: 50, expected: []int{4, 5}},
+		{name: "starts before first", from: -3, to: 2, expected: []int{0, 1}},
+		{name: "entirely past end", from: 7, to: 10, expected: nil},
+		{name: "inverted", from: 4, to: 2, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracks, err := repo.GetTracksInRange(ctx, playlistID, tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("Failed to get tracks: %v", err)
+			}
+			if len(tracks) != len(tt.expected) {
+				t.Fatalf("Expected %d tracks, got %d", len(tt.expected), len(tracks))
+			}
+			for i, index := range tt.expected {
+				if tracks[i].ID != trackIDs[index] {
+					t.Errorf("Expected track %s at index %d, got %s", trackIDs[index], i, tracks[i].ID)
+				}
+			}
+		})
+	}
+}
+
+func TestPlaylistRepository_GetTrackSpotifyIDs(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewPlaylistRepository(testDB)
+	ctx := context.Background()
+
+	playlistID, trackIDs, cleanup := setupPlaylistTrackFixtures(t, ctx, 3)
+	defer cleanup()
+
+	ids, err := repo.GetTrackSpotifyIDs(ctx, playlistID)
+	if err != nil {
+		t.Fatalf("Failed to get track spotify IDs: %v", err)
+	}
+	if ids == nil || len(ids) != 0 {
+		t.Errorf("Expected an empty, non-nil slice for an empty playlist, got %v", ids)
+	}
+
+	// Add in reverse so position order differs from creation order
+	for i := len(trackIDs) - 1; i >= 0; i-- {
+		if err := repo.AddTrack(ctx, playlistID, trackIDs[i], 0); err != nil {
+			t.Fatalf("Failed to add track: %v", err)
+		}
+	}
+
+	ids, err = repo.GetTrackSpotifyIDs(ctx, playlistID)
+	if err != nil {
+		t.Fatalf("Failed to get track spotify IDs: %v", err)
+	}
+	if len(ids) != len(trackIDs) {
+		t.Fatalf("Expected %d spotify IDs, got %d", len(trackIDs), len(ids))
+	}
+	for i, id := range ids {
+		// Fixture tracks use their own UUID as their Spotify ID
+		if expected := trackIDs[len(trackIDs)-1-i].String(); id != expected {
+			t.Errorf("Expected spotify ID %s at position %d, got %s", expected, i+1, id)
+		}
+	}
+}
+
+func TestPlaylistRepository_GetTrackCounts(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewPlaylistRepository(testDB)
+	ctx := context.Background()
+
+	sizes := []int{3, 1, 0}
+	playlistIDs := make([]uuid.UUID, len(sizes))
+	for i, size := range sizes {
+		playlistID, trackIDs, cleanup := setupPlaylistTrackFixtures(t, ctx, size)
+		defer cleanup()
+
+		for _, trackID := range trackIDs {
+			if err := repo.AddTrack(ctx, playlistID, trackID, 0); err != nil {
+				t.Fatalf("Failed to add track: %v", err)
+			}
+		}
+		playlistIDs[i] = playlistID
+	}
+
+	counts, err := repo.GetTrackCounts(ctx, playlistIDs)
+	if err != nil {
+		t.Fatalf("Failed to get track counts: %v", err)
+	}
+
+	if len(counts) != len(playlistIDs) {
+		t.Fatalf("Expected %d counts, got %d", len(playlistIDs), len(counts))
+	}
+	for i, playlistID := range playlistIDs {
+		count, ok := counts[playlistID]
+		if !ok {
+			t.Errorf("Expected a count for playlist %s", playlistID)
+		}
+		if count != sizes[i] {
+			t.Errorf("Expected %d tracks in playlist %d, got %d", sizes[i], i, count)
+		}
+	}
+
+	counts, err = repo.GetTrackCounts(ctx, nil)
+	if err != nil || len(counts) != 0 {
+		t.Errorf("Expected no counts and no error for no playlists, got %v, %v", counts, err)
+	}
+}
+
+func TestPlaylistRepository_Collaborators(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewPlaylistRepository(testDB)
+	userRepo := NewUserRepository(testDB)
+	ctx := context.Background()
+
+	users := make([]*models.User, 3)
+	for i := range users {
+		users[i] = setupTestUser(t)
+		if err := userRepo.Create(ctx, users[i]); err != nil {
+			t.Fatalf("Failed to create test user: %v", err)
+		}
+		defer cleanupTestUser(t, ctx, users[i].ID)
+	}
+	creator, collaborator, stranger := users[0], users[1], users[2]
+
+	playlist := setupTestPlaylist(t, creator.ID, "Shared")
+	if err := repo.Create(ctx, playlist); err != nil {
+		t.Fatalf("Failed to create playlist: %v", err)
+	}
+
+	// Adding twice is a no-op
+	for i := 0; i < 2; i++ {
+		if err := repo.AddCollaborator(ctx, playlist.ID, collaborator.ID); err != nil {
+			t.Fatalf("Failed to add collaborator: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name           string
+		userID         uuid.UUID
+		isCollaborator bool
+		canEdit        bool
+	}{
+		{name: "creator", userID: creator.ID, isCollaborator: false, canEdit: true},
+		{name: "collaborator", userID: collaborator.ID, isCollaborator: true, canEdit: true},
+		{name: "non-collaborator", userID: stranger.ID, isCollaborator: false, canEdit: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isCollaborator, err := repo.IsCollaborator(ctx, playlist.ID, tt.userID)
+			if err != nil {
+				t.Fatalf("Failed to check collaborator: %v", err)
+			}
+			if isCollaborator != tt.isCollaborator {
+				t.Errorf("Expected IsCollaborator %t, got %t", tt.isCollaborator, isCollaborator)
+			}
+
+			canEdit, err := repo.CanEdit(ctx, playlist.ID, tt.userID)
+			if err != nil {
+				t.Fatalf("Failed to check edit access: %v", err)
+			}
+			if canEdit != tt.canEdit {
+				t.Errorf("Expected CanEdit %t, got %t", tt.canEdit, canEdit)
+			}
+		})
+	}
+
+	// Removing the collaborator revokes their access
+	if err := repo.RemoveCollaborator(ctx, playlist.ID, collaborator.ID); err != nil {
+		t.Fatalf("Failed to remove collaborator: %v", err)
+	}
+	if canEdit, err := repo.CanEdit(ctx, playlist.ID, collaborator.ID); err != nil || canEdit {
+		t.Errorf("Expected removed collaborator to lose edit access, got %t, %v", canEdit, err)
+	}
+
+	// Nobody edits a deleted playlist
+	if err := repo.Delete(ctx, playlist.ID); err != nil {
+		t.Fatalf("Failed to delete playlist: %v", err)
+	}
+	if canEdit, err := repo.CanEdit(ctx, playlist.ID, creator.ID); err != nil || canEdit {
+		t.Errorf("Expected no edit access to a deleted playlist, got %t, %v", canEdit, err)
+	}
+}
+
+const benchPlaylistTracks = 300
+
+func BenchmarkPlaylistRepository_AddTracks(b *testing.B) {
+	if testDB == nil {
+		b.Skip("Database not available")
+	}
+
+	repo := NewPlaylistRepository(testDB)
+	ctx := context.Background()
+
+	playlistID, trackIDs, cleanup := setupPlaylistTrackFixtures(b, ctx, benchPlaylistTracks)
+	defer cleanup()
+
+	batch := make([]repository.TrackInsert, len(trackIDs))
+	for i, trackID := range trackIDs {
+		batch[i] = repository.TrackInsert{TrackID: trackID}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := repo.AddTracks(ctx, playlistID, batch); err != nil {
+			b.Fatalf("Failed to add tracks: %v", err)
+		}
+
+		b.StopTimer()
+		if _, err := testDB.Pool.Exec(ctx, "DELETE FROM playlist_tracks WHERE playlist_id = $1", playlistID); err != nil {
+			b.Fatalf("Failed to reset playlist: %v", err)
+		}
+		b.StartTimer()
+	}
+}
+
+// BenchmarkPlaylistRepository_AddTrackLoop imports the same tracks the old
+// way, one AddTrack call per track
+func BenchmarkPlaylistRepository_AddTrackLoop(b *testing.B) {
+	if testDB == nil {
+		b.Skip("Database not available")
+	}
+
+	repo := NewPlaylistRepository(testDB)
+	ctx := context.Background()
+
+	playlistID, trackIDs, cleanup := setupPlaylistTrackFixtures(b, ctx, benchPlaylistTracks)
+	defer cleanup()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, trackID := range trackIDs {
+			if err := repo.AddTrack(ctx, playlistID, trackID, 0); err != nil {
+				b.Fatalf("Failed to add track: %v", err)
+			}
+		}
+
+		b.StopTimer()
+		if _, err := testDB.Pool.Exec(ctx, "DELETE FROM playlist_tracks WHERE playlist_id = $1", playlistID); err != nil {
+			b.Fatalf("Failed to reset playlist: %v", err)
+		}
+		b.StartTimer()
+	}
+}
@@ -3,6 +3,8 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/daedal00/muse/backend/internal/database"
 	"github.com/daedal00/muse/backend/internal/models"
@@ -12,47 +14,115 @@ import (
 )
 
 type reviewRepository struct {
-	db *database.PostgresDB
+	db *dbConn
 }
 
 func NewReviewRepository(db *database.PostgresDB) repository.ReviewRepository {
-	return &reviewRepository{db: db}
+	return &reviewRepository{db: newDBConn(db)}
+}
+
+func newReviewRepository(q querier) *reviewRepository {
+	return &reviewRepository{db: &dbConn{Pool: q}}
 }
 
 func (r *reviewRepository) Create(ctx context.Context, review *models.Review) error {
 	query := `
-		INSERT INTO reviews (id, user_id, album_id, rating, review_text, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO reviews (id, user_id, album_id, rating, review_text, has_spoiler, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	`
 
-	_, err := r.db.Pool.Exec(ctx, query,
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	_, err = tx.Exec(ctx, query,
 		review.ID, review.UserID, review.AlbumID, review.Rating,
-		review.ReviewText, review.CreatedAt, review.UpdatedAt,
+		review.ReviewText, review.HasSpoiler, review.CreatedAt, review.UpdatedAt,
 	)
 
 	if err != nil {
+		if isConstraintViolation(err, activeReviewIndex) {
+			return repository.ErrDuplicateReview
+		}
+		if isUniqueViolation(err) {
+			return fmt.Errorf("review %w", repository.ErrDuplicate)
+		}
 		return fmt.Errorf("failed to create review: %w", err)
 	}
 
+	if err := recordSearchEvent(ctx, tx, review.ID, models.ReviewSearchEventCreated); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Upsert creates the user's review of an album or, if one already exists,
+// updates its rating and text. The review's ID and timestamps are set to the
+// stored row's, so an update keeps the original review ID.
+func (r *reviewRepository) Upsert(ctx context.Context, review *models.Review) error {
+	query := `
+		INSERT INTO reviews (id, user_id, album_id, rating, review_text, has_spoiler, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (user_id, album_id) WHERE deleted_at IS NULL DO UPDATE
+		SET rating = EXCLUDED.rating, review_text = EXCLUDED.review_text,
+			has_spoiler = EXCLUDED.has_spoiler, updated_at = NOW()
+		RETURNING id, created_at, updated_at, (xmax = 0) AS inserted
+	`
+
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var inserted bool
+	err = tx.QueryRow(ctx, query,
+		review.ID, review.UserID, review.AlbumID, review.Rating,
+		review.ReviewText, review.HasSpoiler, review.CreatedAt, review.UpdatedAt,
+	).Scan(&review.ID, &review.CreatedAt, &review.UpdatedAt, &inserted)
+
+	if err != nil {
+		return fmt.Errorf("failed to upsert review: %w", err)
+	}
+
+	eventType := models.ReviewSearchEventUpdated
+	if inserted {
+		eventType = models.ReviewSearchEventCreated
+	}
+	if err := recordSearchEvent(ctx, tx, review.ID, eventType); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return nil
 }
 
 func (r *reviewRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Review, error) {
 	query := `
-		SELECT id, user_id, album_id, rating, review_text, created_at, updated_at
+		SELECT id, user_id, album_id, rating, review_text, has_spoiler, created_at, updated_at
 		FROM reviews 
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	review := &models.Review{}
-	err := r.db.Pool.QueryRow(ctx, query, id).Scan(
+	err := r.db.Reader().QueryRow(ctx, query, id).Scan(
 		&review.ID, &review.UserID, &review.AlbumID, &review.Rating,
-		&review.ReviewText, &review.CreatedAt, &review.UpdatedAt,
+		&review.ReviewText, &review.HasSpoiler, &review.CreatedAt, &review.UpdatedAt,
 	)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("review not found")
+			return nil, fmt.Errorf("review %w", repository.ErrNotFound)
 		}
 		return nil, fmt.Errorf("failed to get review: %w", err)
 	}
@@ -60,16 +130,43 @@ func (r *reviewRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.R
 	return review, nil
 }
 
+// GetByIDWithVoteCount returns a review together with how many users have
+// marked it helpful
+func (r *reviewRepository) GetByIDWithVoteCount(ctx context.Context, id uuid.UUID) (*models.Review, int, error) {
+	query := `
+		SELECT id, user_id, album_id, rating, review_text, has_spoiler, created_at, updated_at,
+			(SELECT COUNT(*) FROM review_votes v WHERE v.review_id = reviews.id)
+		FROM reviews
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	review := &models.Review{}
+	var votes int
+	err := r.db.Reader().QueryRow(ctx, query, id).Scan(
+		&review.ID, &review.UserID, &review.AlbumID, &review.Rating,
+		&review.ReviewText, &review.HasSpoiler, &review.CreatedAt, &review.UpdatedAt, &votes,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, 0, fmt.Errorf("review %w", repository.ErrNotFound)
+		}
+		return nil, 0, fmt.Errorf("failed to get review: %w", err)
+	}
+
+	return review, votes, nil
+}
+
 func (r *reviewRepository) GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.Review, error) {
 	query := `
-		SELECT id, user_id, album_id, rating, review_text, created_at, updated_at
+		SELECT id, user_id, album_id, rating, review_text, has_spoiler, created_at, updated_at
 		FROM reviews 
-		WHERE user_id = $1
+		WHERE user_id = $1 AND deleted_at IS NULL
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query, userID, limit, offset)
+	rows, err := r.db.Reader().Query(ctx, query, userID, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list reviews by user: %w", err)
 	}
@@ -80,7 +177,51 @@ func (r *reviewRepository) GetByUserID(ctx context.Context, userID uuid.UUID, li
 		review := &models.Review{}
 		err := rows.Scan(
 			&review.ID, &review.UserID, &review.AlbumID, &review.Rating,
-			&review.ReviewText, &review.CreatedAt, &review.UpdatedAt,
+			&review.ReviewText, &review.HasSpoiler, &review.CreatedAt, &review.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan review: %w", err)
+		}
+		reviews = append(reviews, review)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reviews: %w", err)
+	}
+
+	return reviews, nil
+}
+
+// GetFeedReviews returns recent reviews by the users userID follows, newest
+// first, with each review's author attached
+func (r *reviewRepository) GetFeedReviews(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.Review, error) {
+	limit, offset = clampLimitOffset(limit, offset)
+
+	query := `
+		SELECT r.id, r.user_id, r.album_id, r.rating, r.review_text, r.has_spoiler, r.created_at, r.updated_at,
+			u.id, u.name, u.email, u.password_hash, u.bio, u.avatar, u.country, u.role, u.last_active_at, u.last_login_at, u.created_at, u.updated_at
+		FROM reviews r
+		JOIN users u ON u.id = r.user_id
+		WHERE r.user_id IN (SELECT followee_id FROM follows WHERE follower_id = $1) AND r.deleted_at IS NULL
+		ORDER BY r.created_at DESC, r.id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Reader().Query(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feed reviews: %w", err)
+	}
+	defer rows.Close()
+
+	reviews := []*models.Review{}
+	for rows.Next() {
+		review := &models.Review{User: &models.User{}}
+		err := rows.Scan(
+			&review.ID, &review.UserID, &review.AlbumID, &review.Rating,
+			&review.ReviewText, &review.HasSpoiler, &review.CreatedAt, &review.UpdatedAt,
+			&review.User.ID, &review.User.Name, &review.User.Email, &review.User.PasswordHash,
+			&review.User.Bio, &review.User.Avatar, &review.User.Country, &review.User.Role, &review.User.LastActiveAt, &review.User.LastLoginAt,
+			&review.User.CreatedAt, &review.User.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan review: %w", err)
@@ -97,14 +238,14 @@ func (r *reviewRepository) GetByUserID(ctx context.Context, userID uuid.UUID, li
 
 func (r *reviewRepository) GetByAlbumID(ctx context.Context, albumID uuid.UUID, limit, offset int) ([]*models.Review, error) {
 	query := `
-		SELECT id, user_id, album_id, rating, review_text, created_at, updated_at
+		SELECT id, user_id, album_id, rating, review_text, has_spoiler, created_at, updated_at
 		FROM reviews 
-		WHERE album_id = $1
+		WHERE album_id = $1 AND deleted_at IS NULL
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query, albumID, limit, offset)
+	rows, err := r.db.Reader().Query(ctx, query, albumID, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list reviews by album: %w", err)
 	}
@@ -115,7 +256,7 @@ func (r *reviewRepository) GetByAlbumID(ctx context.Context, albumID uuid.UUID,
 		review := &models.Review{}
 		err := rows.Scan(
 			&review.ID, &review.UserID, &review.AlbumID, &review.Rating,
-			&review.ReviewText, &review.CreatedAt, &review.UpdatedAt,
+			&review.ReviewText, &review.HasSpoiler, &review.CreatedAt, &review.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan review: %w", err)
@@ -130,22 +271,65 @@ func (r *reviewRepository) GetByAlbumID(ctx context.Context, albumID uuid.UUID,
 	return reviews, nil
 }
 
+// GetByAlbumIDWithSpoilerFlag lists an album's reviews like GetByAlbumID, but
+// unless includeSpoilers is set the text of reviews flagged as spoilers is
+// left nil. HasSpoiler stays set so callers can tell a redacted review from
+// one without text.
+func (r *reviewRepository) GetByAlbumIDWithSpoilerFlag(ctx context.Context, albumID uuid.UUID, includeSpoilers bool, limit, offset int) ([]*models.Review, error) {
+	query := `
+		SELECT id, user_id, album_id, rating,
+			CASE WHEN has_spoiler AND NOT $2 THEN NULL ELSE review_text END,
+			has_spoiler, created_at, updated_at
+		FROM reviews
+		WHERE album_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.db.Reader().Query(ctx, query, albumID, includeSpoilers, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reviews by album: %w", err)
+	}
+	defer rows.Close()
+
+	var reviews []*models.Review
+	for rows.Next() {
+		review := &models.Review{}
+		err := rows.Scan(
+			&review.ID, &review.UserID, &review.AlbumID, &review.Rating,
+			&review.ReviewText, &review.HasSpoiler, &review.CreatedAt, &review.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan review: %w", err)
+		}
+		reviews = append(reviews, review)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reviews: %w", err)
+	}
+
+	return reviews, nil
+}
+
+// GetByUserAndAlbum reads from the primary since it is the check for an
+// existing review before writing one, where replica lag would miss it
 func (r *reviewRepository) GetByUserAndAlbum(ctx context.Context, userID, albumID uuid.UUID) (*models.Review, error) {
 	query := `
-		SELECT id, user_id, album_id, rating, review_text, created_at, updated_at
+		SELECT id, user_id, album_id, rating, review_text, has_spoiler, created_at, updated_at
 		FROM reviews 
-		WHERE user_id = $1 AND album_id = $2
+		WHERE user_id = $1 AND album_id = $2 AND deleted_at IS NULL
 	`
 
 	review := &models.Review{}
 	err := r.db.Pool.QueryRow(ctx, query, userID, albumID).Scan(
 		&review.ID, &review.UserID, &review.AlbumID, &review.Rating,
-		&review.ReviewText, &review.CreatedAt, &review.UpdatedAt,
+		&review.ReviewText, &review.HasSpoiler, &review.CreatedAt, &review.UpdatedAt,
 	)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("review not found")
+			return nil, fmt.Errorf("review %w", repository.ErrNotFound)
 		}
 		return nil, fmt.Errorf("failed to get review: %w", err)
 	}
@@ -156,12 +340,18 @@ func (r *reviewRepository) GetByUserAndAlbum(ctx context.Context, userID, albumI
 func (r *reviewRepository) Update(ctx context.Context, review *models.Review) error {
 	query := `
 		UPDATE reviews 
-		SET rating = $2, review_text = $3, updated_at = NOW()
-		WHERE id = $1
+		SET rating = $2, review_text = $3, has_spoiler = $4, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
-	result, err := r.db.Pool.Exec(ctx, query,
-		review.ID, review.Rating, review.ReviewText,
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	result, err := tx.Exec(ctx, query,
+		review.ID, review.Rating, review.ReviewText, review.HasSpoiler,
 	)
 
 	if err != nil {
@@ -169,36 +359,107 @@ func (r *reviewRepository) Update(ctx context.Context, review *models.Review) er
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("review not found")
+		return fmt.Errorf("review %w", repository.ErrNotFound)
+	}
+
+	if err := recordSearchEvent(ctx, tx, review.ID, models.ReviewSearchEventUpdated); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	return nil
 }
 
+// Delete soft-deletes a review so it can be restored until it is purged
 func (r *reviewRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM reviews WHERE id = $1`
+	query := `UPDATE reviews SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
+
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
 
-	result, err := r.db.Pool.Exec(ctx, query, id)
+	result, err := tx.Exec(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete review: %w", err)
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("review not found")
+		return fmt.Errorf("review %w", repository.ErrNotFound)
+	}
+
+	if err := recordSearchEvent(ctx, tx, id, models.ReviewSearchEventDeleted); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Restore undoes a soft delete. It fails with ErrDuplicateReview if the user
+// has since written a new review of the same album.
+func (r *reviewRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE reviews SET deleted_at = NULL, updated_at = NOW() WHERE id = $1 AND deleted_at IS NOT NULL`
+
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	result, err := tx.Exec(ctx, query, id)
+	if err != nil {
+		if isConstraintViolation(err, activeReviewIndex) {
+			return repository.ErrDuplicateReview
+		}
+		return fmt.Errorf("failed to restore review: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("review %w", repository.ErrNotFound)
+	}
+
+	if err := recordSearchEvent(ctx, tx, id, models.ReviewSearchEventCreated); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	return nil
 }
 
+// PurgeSoftDeleted permanently removes reviews soft-deleted before olderThan
+// and returns how many were removed
+func (r *reviewRepository) PurgeSoftDeleted(ctx context.Context, olderThan time.Time) (int64, error) {
+	query := `DELETE FROM reviews WHERE deleted_at IS NOT NULL AND deleted_at < $1`
+
+	result, err := r.db.Pool.Exec(ctx, query, olderThan)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted reviews: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
 func (r *reviewRepository) List(ctx context.Context, limit, offset int) ([]*models.Review, error) {
 	query := `
-		SELECT id, user_id, album_id, rating, review_text, created_at, updated_at
+		SELECT id, user_id, album_id, rating, review_text, has_spoiler, created_at, updated_at
 		FROM reviews 
+		WHERE deleted_at IS NULL
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query, limit, offset)
+	rows, err := r.db.Reader().Query(ctx, query, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list reviews: %w", err)
 	}
@@ -209,7 +470,70 @@ func (r *reviewRepository) List(ctx context.Context, limit, offset int) ([]*mode
 		review := &models.Review{}
 		err := rows.Scan(
 			&review.ID, &review.UserID, &review.AlbumID, &review.Rating,
-			&review.ReviewText, &review.CreatedAt, &review.UpdatedAt,
+			&review.ReviewText, &review.HasSpoiler, &review.CreatedAt, &review.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan review: %w", err)
+		}
+		reviews = append(reviews, review)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reviews: %w", err)
+	}
+
+	return reviews, nil
+}
+
+// ListFiltered returns reviews matching every non-nil field of filter, newest first
+func (r *reviewRepository) ListFiltered(ctx context.Context, filter repository.ReviewFilter, limit, offset int) ([]*models.Review, error) {
+	conditions := []string{"deleted_at IS NULL"}
+	var args []interface{}
+
+	addCondition := func(clause string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(clause, len(args)))
+	}
+
+	if filter.UserID != nil {
+		addCondition("user_id = $%d", *filter.UserID)
+	}
+	if filter.AlbumID != nil {
+		addCondition("album_id = $%d", *filter.AlbumID)
+	}
+	if filter.MinRating != nil {
+		addCondition("rating >= $%d", *filter.MinRating)
+	}
+	if filter.MaxRating != nil {
+		addCondition("rating <= $%d", *filter.MaxRating)
+	}
+	if filter.Since != nil {
+		addCondition("created_at >= $%d", *filter.Since)
+	}
+	if filter.Until != nil {
+		addCondition("created_at < $%d", *filter.Until)
+	}
+
+	query := `
+		SELECT id, user_id, album_id, rating, review_text, has_spoiler, created_at, updated_at
+		FROM reviews
+	`
+	query += " WHERE " + strings.Join(conditions, " AND ")
+	args = append(args, limit, offset)
+	query += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := r.db.Reader().Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list filtered reviews: %w", err)
+	}
+	defer rows.Close()
+
+	var reviews []*models.Review
+	for rows.Next() {
+		review := &models.Review{}
+		err := rows.Scan(
+			&review.ID, &review.UserID, &review.AlbumID, &review.Rating,
+			&review.ReviewText, &review.HasSpoiler, &review.CreatedAt, &review.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan review: %w", err)
@@ -223,3 +547,602 @@ func (r *reviewRepository) List(ctx context.Context, limit, offset int) ([]*mode
 
 	return reviews, nil
 }
+
+// SearchByText finds live reviews whose text matches query, most relevant
+// first. The query is parsed with websearch_to_tsquery, the forgiving
+// to_tsquery variant: words must all appear (in any form English stemming
+// maps together), "quoted phrases" must appear in order, and stray
+// punctuation cannot cause a syntax error. A blank query matches nothing.
+func (r *reviewRepository) SearchByText(ctx context.Context, query string, limit, offset int) ([]*models.Review, error) {
+	if strings.TrimSpace(query) == "" {
+		return []*models.Review{}, nil
+	}
+	limit, offset = clampLimitOffset(limit, offset)
+
+	sqlQuery := `
+		SELECT id, user_id, album_id, rating, review_text, has_spoiler, created_at, updated_at
+		FROM reviews, websearch_to_tsquery('english', $1) AS q
+		WHERE deleted_at IS NULL AND review_text_search @@ q
+		ORDER BY ts_rank(review_text_search, q) DESC, created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Reader().Query(ctx, sqlQuery, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search reviews: %w", err)
+	}
+	defer rows.Close()
+
+	reviews := []*models.Review{}
+	for rows.Next() {
+		review := &models.Review{}
+		err := rows.Scan(
+			&review.ID, &review.UserID, &review.AlbumID, &review.Rating,
+			&review.ReviewText, &review.HasSpoiler, &review.CreatedAt, &review.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan review: %w", err)
+		}
+		reviews = append(reviews, review)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reviews: %w", err)
+	}
+
+	return reviews, nil
+}
+
+// GetRatingTrend returns the average rating of an album for each of the last
+// `buckets` windows of width bucketSize, oldest first. Buckets without reviews
+// are included with a zero count and average.
+func (r *reviewRepository) GetRatingTrend(ctx context.Context, albumID uuid.UUID, buckets int, bucketSize time.Duration) ([]models.RatingPoint, error) {
+	if buckets <= 0 {
+		return nil, fmt.Errorf("buckets must be positive")
+	}
+	if bucketSize <= 0 {
+		return nil, fmt.Errorf("bucket size must be positive")
+	}
+
+	query := `
+		WITH series AS (
+			SELECT $2::timestamptz - ($3::int - i) * make_interval(secs => $4::float8) AS bucket_start
+			FROM generate_series(0, $3::int - 1) AS i
+		)
+		SELECT s.bucket_start, COALESCE(AVG(r.rating), 0)::float8, COUNT(r.id)
+		FROM series s
+		LEFT JOIN reviews r
+			ON r.album_id = $1
+			AND r.deleted_at IS NULL
+			AND r.created_at >= s.bucket_start
+			AND r.created_at < s.bucket_start + make_interval(secs => $4::float8)
+		GROUP BY s.bucket_start
+		ORDER BY s.bucket_start
+	`
+
+	rows, err := r.db.Reader().Query(ctx, query, albumID, time.Now(), buckets, bucketSize.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rating trend: %w", err)
+	}
+	defer rows.Close()
+
+	points := make([]models.RatingPoint, 0, buckets)
+	for rows.Next() {
+		var point models.RatingPoint
+		if err := rows.Scan(&point.BucketStart, &point.AverageRating, &point.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan rating point: %w", err)
+		}
+		points = append(points, point)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rating points: %w", err)
+	}
+
+	return points, nil
+}
+
+// GetRatingMatrix returns the ratings each of the given users left on each of
+// the given albums, keyed by user ID then album ID. Albums a user has not
+// reviewed are absent from that user's map.
+func (r *reviewRepository) GetRatingMatrix(ctx context.Context, userIDs, albumIDs []uuid.UUID) (map[uuid.UUID]map[uuid.UUID]int, error) {
+	matrix := make(map[uuid.UUID]map[uuid.UUID]int)
+	if len(userIDs) == 0 || len(albumIDs) == 0 {
+		return matrix, nil
+	}
+
+	query := `
+		SELECT user_id, album_id, rating
+		FROM reviews
+		WHERE user_id = ANY($1) AND album_id = ANY($2) AND deleted_at IS NULL
+	`
+
+	rows, err := r.db.Reader().Query(ctx, query, userIDs, albumIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rating matrix: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID, albumID uuid.UUID
+		var rating int
+		if err := rows.Scan(&userID, &albumID, &rating); err != nil {
+			return nil, fmt.Errorf("failed to scan rating: %w", err)
+		}
+		if matrix[userID] == nil {
+			matrix[userID] = make(map[uuid.UUID]int)
+		}
+		matrix[userID][albumID] = rating
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating ratings: %w", err)
+	}
+
+	return matrix, nil
+}
+
+// GetAlbumStats reads an album's review count and average rating from the
+// album_review_stats row that a trigger keeps current, so it costs one
+// primary key lookup however many reviews the album has
+func (r *reviewRepository) GetAlbumStats(ctx context.Context, albumID uuid.UUID) (models.ReviewStats, error) {
+	query := `
+		SELECT review_count, COALESCE(rating_sum::float8 / NULLIF(review_count, 0), 0)
+		FROM album_review_stats
+		WHERE album_id = $1
+	`
+
+	var stats models.ReviewStats
+	err := r.db.Reader().QueryRow(ctx, query, albumID).Scan(&stats.Count, &stats.AverageRating)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.ReviewStats{}, nil // No reviews yet
+		}
+		return models.ReviewStats{}, fmt.Errorf("failed to get album stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// GetRatingDistribution counts an album's reviews per rating. Every rating
+// from 1 to 5 is present in the result, with zero for ratings nobody gave.
+func (r *reviewRepository) GetRatingDistribution(ctx context.Context, albumID uuid.UUID) (*models.RatingDistribution, error) {
+	query := `
+		SELECT rating, COUNT(*)
+		FROM reviews
+		WHERE album_id = $1 AND deleted_at IS NULL
+		GROUP BY rating
+	`
+
+	rows, err := r.db.Reader().Query(ctx, query, albumID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rating distribution: %w", err)
+	}
+	defer rows.Close()
+
+	distribution := &models.RatingDistribution{
+		Counts: map[int]int{1: 0, 2: 0, 3: 0, 4: 0, 5: 0},
+	}
+	for rows.Next() {
+		var rating, count int
+		if err := rows.Scan(&rating, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan rating count: %w", err)
+		}
+		distribution.Counts[rating] = count
+		distribution.Total += count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rating counts: %w", err)
+	}
+
+	return distribution, nil
+}
+
+// GetRatingByCountry groups an album's reviews by the reviewer's country.
+// Reviewers without a known country are left out.
+func (r *reviewRepository) GetRatingByCountry(ctx context.Context, albumID uuid.UUID) (map[string]models.ReviewStats, error) {
+	query := `
+		SELECT u.country, AVG(r.rating)::float8, COUNT(*)
+		FROM reviews r
+		JOIN users u ON u.id = r.user_id
+		WHERE r.album_id = $1 AND r.deleted_at IS NULL AND u.country IS NOT NULL
+		GROUP BY u.country
+	`
+
+	rows, err := r.db.Reader().Query(ctx, query, albumID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ratings by country: %w", err)
+	}
+	defer rows.Close()
+
+	stats := make(map[string]models.ReviewStats)
+	for rows.Next() {
+		var country string
+		var stat models.ReviewStats
+		if err := rows.Scan(&country, &stat.AverageRating, &stat.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan country stats: %w", err)
+		}
+		stats[country] = stat
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating country stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// GetReviewSummary returns a page of an album's reviews along with the
+// album's average rating, review count and rating distribution, all from a
+// single query
+func (r *reviewRepository) GetReviewSummary(ctx context.Context, albumID uuid.UUID, limit, offset int) (*models.ReviewSummary, error) {
+	query := `
+		WITH counts AS (
+			SELECT rating, COUNT(*)::int AS n
+			FROM reviews
+			WHERE album_id = $1 AND deleted_at IS NULL
+			GROUP BY rating
+		), distribution AS (
+			SELECT COALESCE(array_agg(rating), '{}') AS ratings, COALESCE(array_agg(n), '{}') AS counts
+			FROM counts
+		), page AS (
+			SELECT id, user_id, album_id, rating, review_text, has_spoiler, created_at, updated_at
+			FROM reviews
+			WHERE album_id = $1 AND deleted_at IS NULL
+			ORDER BY created_at DESC
+			LIMIT $2 OFFSET $3
+		)
+		SELECT d.ratings, d.counts,
+			p.id, p.user_id, p.album_id, p.rating, p.review_text, p.has_spoiler, p.created_at, p.updated_at
+		FROM distribution d
+		LEFT JOIN page p ON TRUE
+		ORDER BY p.created_at DESC NULLS LAST
+	`
+
+	rows, err := r.db.Reader().Query(ctx, query, albumID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review summary: %w", err)
+	}
+	defer rows.Close()
+
+	summary := &models.ReviewSummary{
+		Reviews:      []*models.Review{},
+		Distribution: map[int]int{1: 0, 2: 0, 3: 0, 4: 0, 5: 0},
+	}
+
+	first := true
+	for rows.Next() {
+		var ratings, counts []int
+		var id, userID, reviewAlbumID *uuid.UUID
+		var rating *int
+		var reviewText *string
+		var hasSpoiler *bool
+		var createdAt, updatedAt *time.Time
+
+		err := rows.Scan(
+			&ratings, &counts,
+			&id, &userID, &reviewAlbumID, &rating, &reviewText, &hasSpoiler, &createdAt, &updatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan review summary: %w", err)
+		}
+
+		// The aggregate columns repeat on every row; read them once
+		if first {
+			sum := 0
+			for i, value := range ratings {
+				summary.Distribution[value] = counts[i]
+				summary.Count += counts[i]
+				sum += value * counts[i]
+			}
+			if summary.Count > 0 {
+				summary.AverageRating = float64(sum) / float64(summary.Count)
+			}
+			first = false
+		}
+
+		// A row without a review means the page is empty
+		if id == nil {
+			continue
+		}
+
+		summary.Reviews = append(summary.Reviews, &models.Review{
+			ID:         *id,
+			UserID:     *userID,
+			AlbumID:    *reviewAlbumID,
+			Rating:     *rating,
+			ReviewText: reviewText,
+			HasSpoiler: *hasSpoiler,
+			CreatedAt:  *createdAt,
+			UpdatedAt:  *updatedAt,
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating review summary: %w", err)
+	}
+
+	return summary, nil
+}
+
+// GetMostReviewed returns the Spotify items with the most reviews written
+// since the given time, along with their average rating. Only albums can be
+// reviewed, so any other item type is rejected.
+func (r *reviewRepository) GetMostReviewed(ctx context.Context, spotifyType string, since time.Time, limit int) ([]models.ItemReviewAggregate, error) {
+	if spotifyType != "album" {
+		return nil, fmt.Errorf("unsupported review item type %q", spotifyType)
+	}
+	limit, _ = clampLimitOffset(limit, 0)
+
+	query := `
+		SELECT a.spotify_id, COUNT(*), AVG(r.rating)::float8
+		FROM reviews r
+		JOIN albums a ON a.id = r.album_id
+		WHERE r.deleted_at IS NULL AND r.created_at >= $1 AND a.spotify_id IS NOT NULL
+		GROUP BY a.spotify_id
+		ORDER BY COUNT(*) DESC, a.spotify_id
+		LIMIT $2
+	`
+
+	rows, err := r.db.Reader().Query(ctx, query, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get most reviewed items: %w", err)
+	}
+	defer rows.Close()
+
+	aggregates := []models.ItemReviewAggregate{}
+	for rows.Next() {
+		var aggregate models.ItemReviewAggregate
+		if err := rows.Scan(&aggregate.SpotifyID, &aggregate.Count, &aggregate.AverageRating); err != nil {
+			return nil, fmt.Errorf("failed to scan review aggregate: %w", err)
+		}
+		aggregates = append(aggregates, aggregate)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating review aggregates: %w", err)
+	}
+
+	return aggregates, nil
+}
+
+// GetTopRated returns the Spotify items with the highest average rating,
+// counting only items with at least minReviews reviews so a single 5-star
+// review cannot top the chart. Ties are broken by review count.
+func (r *reviewRepository) GetTopRated(ctx context.Context, spotifyType string, minReviews int, limit int) ([]models.ItemReviewAggregate, error) {
+	if spotifyType != "album" {
+		return nil, fmt.Errorf("unsupported review item type %q", spotifyType)
+	}
+	limit, _ = clampLimitOffset(limit, 0)
+	minReviews = max(minReviews, 1)
+
+	query := `
+		SELECT a.spotify_id, COUNT(*), AVG(r.rating)::float8
+		FROM reviews r
+		JOIN albums a ON a.id = r.album_id
+		WHERE r.deleted_at IS NULL AND a.spotify_id IS NOT NULL
+		GROUP BY a.spotify_id
+		HAVING COUNT(*) >= $1
+		ORDER BY AVG(r.rating) DESC, COUNT(*) DESC, a.spotify_id
+		LIMIT $2
+	`
+
+	rows, err := r.db.Reader().Query(ctx, query, minReviews, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top rated items: %w", err)
+	}
+	defer rows.Close()
+
+	aggregates := []models.ItemReviewAggregate{}
+	for rows.Next() {
+		var aggregate models.ItemReviewAggregate
+		if err := rows.Scan(&aggregate.SpotifyID, &aggregate.Count, &aggregate.AverageRating); err != nil {
+			return nil, fmt.Errorf("failed to scan review aggregate: %w", err)
+		}
+		aggregates = append(aggregates, aggregate)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating review aggregates: %w", err)
+	}
+
+	return aggregates, nil
+}
+
+// GetAverageRatings returns the average rating of each of the given Spotify
+// items in one query, keyed by Spotify ID. Items without reviews map to 0.
+// Only albums can be reviewed, so other item types are rejected.
+func (r *reviewRepository) GetAverageRatings(ctx context.Context, spotifyType string, spotifyIDs []string) (map[string]float64, error) {
+	if spotifyType != "album" {
+		return nil, fmt.Errorf("unsupported review item type %q", spotifyType)
+	}
+
+	ratings := make(map[string]float64, len(spotifyIDs))
+	for _, id := range spotifyIDs {
+		ratings[id] = 0
+	}
+	if len(spotifyIDs) == 0 {
+		return ratings, nil
+	}
+
+	query := `
+		SELECT a.spotify_id, AVG(r.rating)::float8
+		FROM reviews r
+		JOIN albums a ON a.id = r.album_id
+		WHERE r.deleted_at IS NULL AND a.spotify_id = ANY($1)
+		GROUP BY a.spotify_id
+	`
+
+	rows, err := r.db.Reader().Query(ctx, query, spotifyIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get average ratings: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var spotifyID string
+		var average float64
+		if err := rows.Scan(&spotifyID, &average); err != nil {
+			return nil, fmt.Errorf("failed to scan average rating: %w", err)
+		}
+		ratings[spotifyID] = average
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating average ratings: %w", err)
+	}
+
+	return ratings, nil
+}
+
+// Search outbox
+
+// recordSearchEvent appends a review change to the search outbox inside the
+// transaction making the change, so the two are committed together
+func recordSearchEvent(ctx context.Context, tx pgx.Tx, reviewID uuid.UUID, eventType string) error {
+	query := `INSERT INTO review_search_events (review_id, event_type) VALUES ($1, $2)`
+
+	if _, err := tx.Exec(ctx, query, reviewID, eventType); err != nil {
+		return fmt.Errorf("failed to record search event: %w", err)
+	}
+
+	return nil
+}
+
+// FetchPendingSearchEvents returns unprocessed search events, oldest first
+func (r *reviewRepository) FetchPendingSearchEvents(ctx context.Context, limit int) ([]*models.ReviewSearchEvent, error) {
+	query := `
+		SELECT id, review_id, event_type, created_at, processed_at
+		FROM review_search_events
+		WHERE processed_at IS NULL
+		ORDER BY id
+		LIMIT $1
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch search events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*models.ReviewSearchEvent
+	for rows.Next() {
+		event := &models.ReviewSearchEvent{}
+		err := rows.Scan(&event.ID, &event.ReviewID, &event.EventType, &event.CreatedAt, &event.ProcessedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan search event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating search events: %w", err)
+	}
+
+	return events, nil
+}
+
+// MarkSearchEventsProcessed flags the given events as applied by the indexer
+func (r *reviewRepository) MarkSearchEventsProcessed(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query := `
+		UPDATE review_search_events
+		SET processed_at = NOW()
+		WHERE id = ANY($1) AND processed_at IS NULL
+	`
+
+	if _, err := r.db.Pool.Exec(ctx, query, ids); err != nil {
+		return fmt.Errorf("failed to mark search events processed: %w", err)
+	}
+
+	return nil
+}
+
+// ListConnection returns a page of reviews, newest first, using keyset
+// pagination on (created_at, id). Pages continue after the given cursor, or
+// with before set, return the reviews just newer than that cursor so callers
+// can walk back the way they came. Setting both cursors is an error.
+func (r *reviewRepository) ListConnection(ctx context.Context, first int, after, before *string) (*models.Connection[models.Review], error) {
+	first, _ = clampLimitOffset(first, 0)
+
+	if after != nil && *after != "" && before != nil && *before != "" {
+		return nil, fmt.Errorf("cannot paginate with both after and before")
+	}
+
+	afterCreatedAt, afterID, err := keysetArgs(after)
+	if err != nil {
+		return nil, err
+	}
+
+	beforeCreatedAt, beforeID, err := keysetArgs(before)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT id, user_id, album_id, rating, review_text, has_spoiler, created_at, updated_at,
+			(SELECT COUNT(*) FROM reviews WHERE deleted_at IS NULL)
+		FROM reviews
+		WHERE deleted_at IS NULL
+			AND ($1::timestamptz IS NULL OR (created_at, id) < ($1::timestamptz, $2::uuid))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3
+	`
+	args := []interface{}{afterCreatedAt, afterID, first + 1}
+
+	// Walking backwards reads ascending from the cursor so the LIMIT keeps
+	// the rows closest to it
+	if beforeCreatedAt != nil {
+		query = `
+			SELECT id, user_id, album_id, rating, review_text, has_spoiler, created_at, updated_at,
+				(SELECT COUNT(*) FROM reviews WHERE deleted_at IS NULL)
+			FROM reviews
+			WHERE deleted_at IS NULL
+				AND (created_at, id) > ($1::timestamptz, $2::uuid)
+			ORDER BY created_at ASC, id ASC
+			LIMIT $3
+		`
+		args = []interface{}{beforeCreatedAt, beforeID, first + 1}
+	}
+
+	rows, err := r.db.Reader().Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reviews: %w", err)
+	}
+	defer rows.Close()
+
+	var reviews []models.Review
+	var total int
+	for rows.Next() {
+		var review models.Review
+		err := rows.Scan(
+			&review.ID, &review.UserID, &review.AlbumID, &review.Rating,
+			&review.ReviewText, &review.HasSpoiler, &review.CreatedAt, &review.UpdatedAt, &total,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan review: %w", err)
+		}
+		reviews = append(reviews, review)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reviews: %w", err)
+	}
+
+	total, err = connectionTotal(ctx, r.db.Reader(), len(reviews), total, `SELECT COUNT(*) FROM reviews WHERE deleted_at IS NULL`)
+	if err != nil {
+		return nil, err
+	}
+
+	key := func(review models.Review) (time.Time, uuid.UUID) {
+		return review.CreatedAt, review.ID
+	}
+	if beforeCreatedAt != nil {
+		return newBackwardConnection(reviews, first, total, key), nil
+	}
+	return newConnection(reviews, first, total, afterCreatedAt != nil, key), nil
+}
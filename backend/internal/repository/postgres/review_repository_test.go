@@ -0,0 +1,1482 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/daedal00/muse/backend/internal/models"
+	"github.com/daedal00/muse/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// setupTestReview creates a test review for repository tests
+func setupTestReview(t testing.TB, userID, albumID uuid.UUID, rating int, createdAt time.Time) *models.Review {
+	t.Helper()
+
+	return &models.Review{
+		ID:         uuid.New(),
+		UserID:     userID,
+		AlbumID:    albumID,
+		Rating:     rating,
+		ReviewText: stringPtr("Test review"),
+		CreatedAt:  createdAt,
+		UpdatedAt:  createdAt,
+	}
+}
+
+// setupReviewFixtures creates an artist, an album and the given number of users
+// to review it, returning the album and user IDs along with a cleanup function
+func setupReviewFixtures(t testing.TB, ctx context.Context, users int) (uuid.UUID, []uuid.UUID, func()) {
+	t.Helper()
+
+	artist := setupTestArtist(t)
+	if err := NewArtistRepository(testDB).Create(ctx, artist); err != nil {
+		t.Fatalf("Failed to create test artist: %v", err)
+	}
+
+	album := setupTestAlbum(t, artist.ID)
+	if err := NewAlbumRepository(testDB).Create(ctx, album); err != nil {
+		cleanupTestArtist(t, ctx, artist.ID)
+		t.Fatalf("Failed to create test album: %v", err)
+	}
+
+	userRepo := NewUserRepository(testDB)
+	var userIDs []uuid.UUID
+	cleanup := func() {
+		for _, id := range userIDs {
+			cleanupTestUser(t, ctx, id)
+		}
+		cleanupTestAlbum(t, ctx, album.ID)
+		cleanupTestArtist(t, ctx, artist.ID)
+	}
+
+	for i := 0; i < users; i++ {
+		user := setupTestUser(t)
+		if err := userRepo.Create(ctx, user); err != nil {
+			cleanup()
+			t.Fatalf("Failed to create test user: %v", err)
+		}
+		userIDs = append(userIDs, user.ID)
+	}
+
+	return album.ID, userIDs, cleanup
+}
+
+func TestReviewRepository_GetByID_NotFound(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewReviewRepository(testDB)
+	ctx := context.Background()
+
+	_, err := repo.GetByID(ctx, uuid.New())
+	if !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestReviewRepository_Create_Duplicate(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewReviewRepository(testDB)
+	ctx := context.Background()
+
+	albumID, userIDs, cleanup := setupReviewFixtures(t, ctx, 1)
+	defer cleanup()
+
+	first := setupTestReview(t, userIDs[0], albumID, 4, time.Now())
+	if err := repo.Create(ctx, first); err != nil {
+		t.Fatalf("Failed to create review: %v", err)
+	}
+
+	// A second review of the same album by the same user must be rejected
+	second := setupTestReview(t, userIDs[0], albumID, 2, time.Now())
+	err := repo.Create(ctx, second)
+	if err == nil {
+		t.Fatal("Expected error for duplicate review")
+	}
+
+	if !errors.Is(err, repository.ErrDuplicateReview) {
+		t.Errorf("Expected ErrDuplicateReview, got %v", err)
+	}
+	if !errors.Is(err, repository.ErrDuplicate) {
+		t.Errorf("Expected ErrDuplicateReview to match ErrDuplicate, got %v", err)
+	}
+}
+
+func TestReviewRepository_Create_ReusedID(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewReviewRepository(testDB)
+	ctx := context.Background()
+
+	albumID, userIDs, cleanup := setupReviewFixtures(t, ctx, 2)
+	defer cleanup()
+
+	first := setupTestReview(t, userIDs[0], albumID, 4, time.Now())
+	if err := repo.Create(ctx, first); err != nil {
+		t.Fatalf("Failed to create review: %v", err)
+	}
+
+	// Another user's review that collides on the primary key is a duplicate,
+	// but not a second review of the album
+	reused := setupTestReview(t, userIDs[1], albumID, 2, time.Now())
+	reused.ID = first.ID
+	err := repo.Create(ctx, reused)
+	if !errors.Is(err, repository.ErrDuplicate) || errors.Is(err, repository.ErrDuplicateReview) {
+		t.Errorf("Expected ErrDuplicate but not ErrDuplicateReview for a reused ID, got %v", err)
+	}
+}
+
+func TestReviewRepository_Upsert(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewReviewRepository(testDB)
+	ctx := context.Background()
+
+	albumID, userIDs, cleanup := setupReviewFixtures(t, ctx, 1)
+	defer cleanup()
+
+	original := setupTestReview(t, userIDs[0], albumID, 3, time.Now())
+	if err := repo.Upsert(ctx, original); err != nil {
+		t.Fatalf("Failed to upsert review: %v", err)
+	}
+
+	// Upserting again with a fresh ID must update the existing row in place
+	edited := setupTestReview(t, userIDs[0], albumID, 5, time.Now())
+	edited.ReviewText = stringPtr("Changed my mind")
+	if err := repo.Upsert(ctx, edited); err != nil {
+		t.Fatalf("Failed to upsert review: %v", err)
+	}
+
+	if edited.ID != original.ID {
+		t.Errorf("Expected upsert to keep original ID %s, got %s", original.ID, edited.ID)
+	}
+
+	stored, err := repo.GetByUserAndAlbum(ctx, userIDs[0], albumID)
+	if err != nil {
+		t.Fatalf("Failed to get review: %v", err)
+	}
+
+	if stored.Rating != 5 {
+		t.Errorf("Expected rating 5, got %d", stored.Rating)
+	}
+
+	if stored.ReviewText == nil || *stored.ReviewText != "Changed my mind" {
+		t.Errorf("Expected updated review text, got %v", stored.ReviewText)
+	}
+}
+
+func TestReviewRepository_GetFeedReviews(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewReviewRepository(testDB)
+	follows := NewFollowRepository(testDB)
+	ctx := context.Background()
+
+	albumID, userIDs, cleanup := setupReviewFixtures(t, ctx, 4)
+	defer cleanup()
+	viewer, older, newer, stranger := userIDs[0], userIDs[1], userIDs[2], userIDs[3]
+
+	// A user who follows nobody gets an empty feed
+	feed, err := repo.GetFeedReviews(ctx, viewer, 10, 0)
+	if err != nil {
+		t.Fatalf("Failed to get feed: %v", err)
+	}
+	if feed == nil || len(feed) != 0 {
+		t.Errorf("Expected empty non-nil feed, got %v", feed)
+	}
+
+	for _, id := range []uuid.UUID{older, newer} {
+		if err := follows.Follow(ctx, viewer, id); err != nil {
+			t.Fatalf("Failed to follow user: %v", err)
+		}
+	}
+
+	now := time.Now()
+	olderReview := setupTestReview(t, older, albumID, 3, now.Add(-2*time.Hour))
+	newerReview := setupTestReview(t, newer, albumID, 4, now.Add(-time.Hour))
+	strangerReview := setupTestReview(t, stranger, albumID, 5, now)
+	for _, review := range []*models.Review{olderReview, newerReview, strangerReview} {
+		if err := repo.Create(ctx, review); err != nil {
+			t.Fatalf("Failed to create review: %v", err)
+		}
+	}
+
+	feed, err = repo.GetFeedReviews(ctx, viewer, 10, 0)
+	if err != nil {
+		t.Fatalf("Failed to get feed: %v", err)
+	}
+	if len(feed) != 2 {
+		t.Fatalf("Expected 2 feed reviews, got %d", len(feed))
+	}
+	if feed[0].ID != newerReview.ID || feed[1].ID != olderReview.ID {
+		t.Errorf("Expected feed in recency order [%s %s], got [%s %s]", newerReview.ID, olderReview.ID, feed[0].ID, feed[1].ID)
+	}
+	if feed[0].User == nil || feed[0].User.ID != newer {
+		t.Errorf("Expected feed review author %s to be hydrated, got %v", newer, feed[0].User)
+	}
+}
+
+func TestReviewRepository_GetByAlbumIDWithSpoilerFlag(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewReviewRepository(testDB)
+	ctx := context.Background()
+
+	albumID, userIDs, cleanup := setupReviewFixtures(t, ctx, 2)
+	defer cleanup()
+
+	spoiler := setupTestReview(t, userIDs[0], albumID, 5, time.Now().Add(-time.Minute))
+	spoiler.ReviewText = stringPtr("The narrator was dead all along")
+	spoiler.HasSpoiler = true
+	plain := setupTestReview(t, userIDs[1], albumID, 4, time.Now())
+
+	for _, review := range []*models.Review{spoiler, plain} {
+		if err := repo.Create(ctx, review); err != nil {
+			t.Fatalf("Failed to create review: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name            string
+		includeSpoilers bool
+		wantSpoilerText bool
+	}{
+		{name: "redacted by default", includeSpoilers: false, wantSpoilerText: false},
+		{name: "opted in", includeSpoilers: true, wantSpoilerText: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reviews, err := repo.GetByAlbumIDWithSpoilerFlag(ctx, albumID, tt.includeSpoilers, 10, 0)
+			if err != nil {
+				t.Fatalf("Failed to list reviews: %v", err)
+			}
+
+			if len(reviews) != 2 {
+				t.Fatalf("Expected 2 reviews, got %d", len(reviews))
+			}
+
+			// Newest first: the plain review, then the spoiler
+			if reviews[0].HasSpoiler || reviews[0].ReviewText == nil {
+				t.Error("Expected review without spoilers to keep its text")
+			}
+
+			if !reviews[1].HasSpoiler {
+				t.Error("Expected spoiler flag to be set")
+			}
+			if gotText := reviews[1].ReviewText != nil; gotText != tt.wantSpoilerText {
+				t.Errorf("Expected spoiler text present = %v, got %v", tt.wantSpoilerText, gotText)
+			}
+		})
+	}
+
+	// The unredacted read path keeps returning the flag with the text
+	stored, err := repo.GetByID(ctx, spoiler.ID)
+	if err != nil {
+		t.Fatalf("Failed to get review: %v", err)
+	}
+	if !stored.HasSpoiler || stored.ReviewText == nil {
+		t.Error("Expected GetByID to return the spoiler text and flag")
+	}
+}
+
+func TestReviewRepository_ListFiltered(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewReviewRepository(testDB)
+	ctx := context.Background()
+
+	albumID, userIDs, cleanup := setupReviewFixtures(t, ctx, 3)
+	defer cleanup()
+
+	now := time.Now()
+	recentFive := setupTestReview(t, userIDs[0], albumID, 5, now.Add(-24*time.Hour))
+	oldFive := setupTestReview(t, userIDs[1], albumID, 5, now.Add(-60*24*time.Hour))
+	recentTwo := setupTestReview(t, userIDs[2], albumID, 2, now.Add(-24*time.Hour))
+	for _, review := range []*models.Review{recentFive, oldFive, recentTwo} {
+		if err := repo.Create(ctx, review); err != nil {
+			t.Fatalf("Failed to create review: %v", err)
+		}
+	}
+
+	// All 5-star reviews of the album from the last month
+	minRating := 5
+	since := now.Add(-30 * 24 * time.Hour)
+	reviews, err := repo.ListFiltered(ctx, repository.ReviewFilter{
+		AlbumID:   &albumID,
+		MinRating: &minRating,
+		Since:     &since,
+	}, 10, 0)
+	if err != nil {
+		t.Fatalf("Failed to list filtered reviews: %v", err)
+	}
+
+	if len(reviews) != 1 || reviews[0].ID != recentFive.ID {
+		t.Fatalf("Expected only the recent 5-star review, got %d reviews", len(reviews))
+	}
+
+	// Only the album filter set returns everything for the album
+	reviews, err = repo.ListFiltered(ctx, repository.ReviewFilter{AlbumID: &albumID}, 10, 0)
+	if err != nil {
+		t.Fatalf("Failed to list filtered reviews: %v", err)
+	}
+
+	if len(reviews) != 3 {
+		t.Errorf("Expected 3 reviews, got %d", len(reviews))
+	}
+}
+
+func TestReviewRepository_SearchByText(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewReviewRepository(testDB)
+	ctx := context.Background()
+
+	albumID, userIDs, cleanup := setupReviewFixtures(t, ctx, 3)
+	defer cleanup()
+
+	// A made-up word keeps other reviews in the database out of the results
+	marker := "zq" + uuid.New().String()[:8]
+	texts := []string{
+		marker + " an absolute masterpiece, a masterpiece from start to finish. Masterpieces like this are rare.",
+		marker + " some call it a masterpiece but the second half drags",
+		marker + " solid production, forgettable songs",
+	}
+	reviews := make([]*models.Review, len(texts))
+	for i, text := range texts {
+		review := setupTestReview(t, userIDs[i], albumID, 4, time.Now().Add(time.Duration(i)*time.Minute))
+		review.ReviewText = &text
+		if err := repo.Create(ctx, review); err != nil {
+			t.Fatalf("Failed to create review: %v", err)
+		}
+		reviews[i] = review
+	}
+
+	// Every word must match; the review that dwells on it ranks first
+	results, err := repo.SearchByText(ctx, marker+" masterpiece", 10, 0)
+	if err != nil {
+		t.Fatalf("Failed to search reviews: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 matching reviews, got %d", len(results))
+	}
+	if results[0].ID != reviews[0].ID || results[1].ID != reviews[1].ID {
+		t.Errorf("Expected the review mentioning masterpiece most to rank first, got %v then %v", results[0].ID, results[1].ID)
+	}
+
+	// Quoted phrases must appear in order
+	results, err = repo.SearchByText(ctx, marker+` "absolute masterpiece"`, 10, 0)
+	if err != nil {
+		t.Fatalf("Failed to search reviews: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != reviews[0].ID {
+		t.Errorf("Expected only the review with the phrase, got %d results", len(results))
+	}
+
+	// Blank queries match nothing rather than everything
+	results, err = repo.SearchByText(ctx, "   ", 10, 0)
+	if err != nil {
+		t.Fatalf("Failed to search reviews: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("Expected no results for a blank query, got %d", len(results))
+	}
+}
+
+func TestReviewRepository_GetRatingTrend(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewReviewRepository(testDB)
+	ctx := context.Background()
+
+	albumID, userIDs, cleanup := setupReviewFixtures(t, ctx, 3)
+	defer cleanup()
+
+	week := 7 * 24 * time.Hour
+	now := time.Now()
+
+	// Two reviews in the most recent week, one two-and-a-half weeks ago
+	reviews := []*models.Review{
+		setupTestReview(t, userIDs[0], albumID, 4, now.Add(-3*24*time.Hour)),
+		setupTestReview(t, userIDs[1], albumID, 5, now.Add(-2*24*time.Hour)),
+		setupTestReview(t, userIDs[2], albumID, 2, now.Add(-week*5/2)),
+	}
+	for _, review := range reviews {
+		if err := repo.Create(ctx, review); err != nil {
+			t.Fatalf("Failed to create review: %v", err)
+		}
+	}
+
+	points, err := repo.GetRatingTrend(ctx, albumID, 4, week)
+	if err != nil {
+		t.Fatalf("Failed to get rating trend: %v", err)
+	}
+
+	if len(points) != 4 {
+		t.Fatalf("Expected 4 buckets, got %d", len(points))
+	}
+
+	expected := []struct {
+		count   int
+		average float64
+	}{
+		{0, 0},
+		{1, 2},
+		{0, 0},
+		{2, 4.5},
+	}
+
+	for i, want := range expected {
+		if points[i].Count != want.count {
+			t.Errorf("Bucket %d: expected count %d, got %d", i, want.count, points[i].Count)
+		}
+		if points[i].AverageRating != want.average {
+			t.Errorf("Bucket %d: expected average %v, got %v", i, want.average, points[i].AverageRating)
+		}
+		if i > 0 && !points[i].BucketStart.After(points[i-1].BucketStart) {
+			t.Errorf("Bucket %d: expected buckets ordered oldest first", i)
+		}
+	}
+}
+
+func TestReviewRepository_GetRatingTrend_InvalidArguments(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewReviewRepository(testDB)
+	ctx := context.Background()
+
+	if _, err := repo.GetRatingTrend(ctx, uuid.New(), 0, time.Hour); err == nil {
+		t.Error("Expected error for zero buckets")
+	}
+
+	if _, err := repo.GetRatingTrend(ctx, uuid.New(), 4, 0); err == nil {
+		t.Error("Expected error for zero bucket size")
+	}
+}
+
+func TestReviewRepository_GetRatingMatrix(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewReviewRepository(testDB)
+	albumRepo := NewAlbumRepository(testDB)
+	ctx := context.Background()
+
+	firstAlbumID, userIDs, cleanup := setupReviewFixtures(t, ctx, 2)
+	defer cleanup()
+
+	album, err := albumRepo.GetByID(ctx, firstAlbumID)
+	if err != nil {
+		t.Fatalf("Failed to get test album: %v", err)
+	}
+	secondAlbum := setupTestAlbum(t, album.ArtistID)
+	if err := albumRepo.Create(ctx, secondAlbum); err != nil {
+		t.Fatalf("Failed to create second album: %v", err)
+	}
+	defer cleanupTestAlbum(t, ctx, secondAlbum.ID)
+
+	// The first user rates both albums, the second only the first album
+	now := time.Now()
+	reviews := []*models.Review{
+		setupTestReview(t, userIDs[0], firstAlbumID, 5, now),
+		setupTestReview(t, userIDs[0], secondAlbum.ID, 3, now),
+		setupTestReview(t, userIDs[1], firstAlbumID, 1, now),
+	}
+	for _, review := range reviews {
+		if err := repo.Create(ctx, review); err != nil {
+			t.Fatalf("Failed to create review: %v", err)
+		}
+	}
+
+	matrix, err := repo.GetRatingMatrix(ctx, userIDs, []uuid.UUID{firstAlbumID, secondAlbum.ID})
+	if err != nil {
+		t.Fatalf("Failed to get rating matrix: %v", err)
+	}
+
+	if got := matrix[userIDs[0]][firstAlbumID]; got != 5 {
+		t.Errorf("Expected first user's first album rating 5, got %d", got)
+	}
+	if got := matrix[userIDs[0]][secondAlbum.ID]; got != 3 {
+		t.Errorf("Expected first user's second album rating 3, got %d", got)
+	}
+	if got := matrix[userIDs[1]][firstAlbumID]; got != 1 {
+		t.Errorf("Expected second user's first album rating 1, got %d", got)
+	}
+	if _, ok := matrix[userIDs[1]][secondAlbum.ID]; ok {
+		t.Error("Expected missing rating to be absent from the matrix")
+	}
+}
+
+func TestReviewRepository_GetAlbumStats(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewReviewRepository(testDB)
+	ctx := context.Background()
+
+	albumID, userIDs, cleanup := setupReviewFixtures(t, ctx, 4)
+	defer cleanup()
+
+	// assertStats compares the maintained row against a fresh aggregate
+	assertStats := func(step string) {
+		t.Helper()
+
+		var count int
+		var average float64
+		err := testDB.Pool.QueryRow(ctx,
+			`SELECT COUNT(*), COALESCE(AVG(rating), 0)::float8 FROM reviews WHERE album_id = $1 AND deleted_at IS NULL`,
+			albumID).Scan(&count, &average)
+		if err != nil {
+			t.Fatalf("Failed to recompute stats after %s: %v", step, err)
+		}
+
+		stats, err := repo.GetAlbumStats(ctx, albumID)
+		if err != nil {
+			t.Fatalf("Failed to get album stats after %s: %v", step, err)
+		}
+		if stats.Count != count || math.Abs(stats.AverageRating-average) > 1e-9 {
+			t.Errorf("After %s expected (%d, %.3f), got (%d, %.3f)", step, count, average, stats.Count, stats.AverageRating)
+		}
+	}
+
+	assertStats("no reviews")
+
+	var reviews []*models.Review
+	for i, rating := range []int{5, 4, 2, 1} {
+		review := setupTestReview(t, userIDs[i], albumID, rating, time.Now())
+		if err := repo.Create(ctx, review); err != nil {
+			t.Fatalf("Failed to create review: %v", err)
+		}
+		reviews = append(reviews, review)
+	}
+	assertStats("create")
+
+	reviews[0].Rating = 3
+	if err := repo.Update(ctx, reviews[0]); err != nil {
+		t.Fatalf("Failed to update review: %v", err)
+	}
+	assertStats("update")
+
+	if err := repo.Delete(ctx, reviews[1].ID); err != nil {
+		t.Fatalf("Failed to delete review: %v", err)
+	}
+	assertStats("soft delete")
+
+	if err := repo.Restore(ctx, reviews[1].ID); err != nil {
+		t.Fatalf("Failed to restore review: %v", err)
+	}
+	assertStats("restore")
+
+	replacement := setupTestReview(t, userIDs[2], albumID, 5, time.Now())
+	if err := repo.Upsert(ctx, replacement); err != nil {
+		t.Fatalf("Failed to upsert review: %v", err)
+	}
+	assertStats("upsert")
+
+	// Deleting the user cascades to their review
+	cleanupTestUser(t, ctx, userIDs[3])
+	assertStats("user delete")
+
+	if err := repo.Delete(ctx, reviews[0].ID); err != nil {
+		t.Fatalf("Failed to delete review: %v", err)
+	}
+	if _, err := repo.PurgeSoftDeleted(ctx, time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("Failed to purge reviews: %v", err)
+	}
+	assertStats("purge")
+}
+
+func TestReviewRepository_GetRatingDistribution_NoReviews(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewReviewRepository(testDB)
+	ctx := context.Background()
+
+	distribution, err := repo.GetRatingDistribution(ctx, uuid.New())
+	if err != nil {
+		t.Fatalf("Failed to get rating distribution: %v", err)
+	}
+
+	if distribution.Total != 0 {
+		t.Errorf("Expected total 0, got %d", distribution.Total)
+	}
+
+	for rating := 1; rating <= 5; rating++ {
+		count, ok := distribution.Counts[rating]
+		if !ok || count != 0 {
+			t.Errorf("Expected zero count for rating %d, got %d (present: %t)", rating, count, ok)
+		}
+	}
+}
+
+func TestReviewRepository_GetRatingDistribution(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewReviewRepository(testDB)
+	ctx := context.Background()
+
+	albumID, userIDs, cleanup := setupReviewFixtures(t, ctx, 4)
+	defer cleanup()
+
+	for i, rating := range []int{5, 5, 3, 1} {
+		review := setupTestReview(t, userIDs[i], albumID, rating, time.Now())
+		if err := repo.Create(ctx, review); err != nil {
+			t.Fatalf("Failed to create review: %v", err)
+		}
+	}
+
+	distribution, err := repo.GetRatingDistribution(ctx, albumID)
+	if err != nil {
+		t.Fatalf("Failed to get rating distribution: %v", err)
+	}
+
+	expected := map[int]int{1: 1, 2: 0, 3: 1, 4: 0, 5: 2}
+	for rating, want := range expected {
+		if got := distribution.Counts[rating]; got != want {
+			t.Errorf("Expected %d reviews with rating %d, got %d", want, rating, got)
+		}
+	}
+
+	if distribution.Total != 4 {
+		t.Errorf("Expected total 4, got %d", distribution.Total)
+	}
+}
+
+// pendingSearchEventTypes returns the types of unprocessed search events for a review
+func pendingSearchEventTypes(t *testing.T, ctx context.Context, repo repository.ReviewRepository, reviewID uuid.UUID) ([]string, []int64) {
+	t.Helper()
+
+	events, err := repo.FetchPendingSearchEvents(ctx, 1000)
+	if err != nil {
+		t.Fatalf("Failed to fetch search events: %v", err)
+	}
+
+	var types []string
+	var ids []int64
+	for _, event := range events {
+		if event.ReviewID == reviewID {
+			types = append(types, event.EventType)
+			ids = append(ids, event.ID)
+		}
+	}
+	return types, ids
+}
+
+func TestReviewRepository_SearchEvents(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewReviewRepository(testDB)
+	ctx := context.Background()
+
+	albumID, userIDs, cleanup := setupReviewFixtures(t, ctx, 1)
+	defer cleanup()
+
+	review := setupTestReview(t, userIDs[0], albumID, 3, time.Now())
+	defer func() {
+		_, _ = testDB.Pool.Exec(ctx, "DELETE FROM review_search_events WHERE review_id = $1", review.ID)
+	}()
+
+	if err := repo.Create(ctx, review); err != nil {
+		t.Fatalf("Failed to create review: %v", err)
+	}
+
+	review.Rating = 4
+	if err := repo.Update(ctx, review); err != nil {
+		t.Fatalf("Failed to update review: %v", err)
+	}
+
+	if err := repo.Delete(ctx, review.ID); err != nil {
+		t.Fatalf("Failed to delete review: %v", err)
+	}
+
+	types, ids := pendingSearchEventTypes(t, ctx, repo, review.ID)
+	expected := []string{
+		models.ReviewSearchEventCreated,
+		models.ReviewSearchEventUpdated,
+		models.ReviewSearchEventDeleted,
+	}
+	if len(types) != len(expected) {
+		t.Fatalf("Expected events %v, got %v", expected, types)
+	}
+	for i := range expected {
+		if types[i] != expected[i] {
+			t.Errorf("Expected event %d to be %s, got %s", i, expected[i], types[i])
+		}
+	}
+
+	// Draining the events leaves nothing pending for the review
+	if err := repo.MarkSearchEventsProcessed(ctx, ids); err != nil {
+		t.Fatalf("Failed to mark search events processed: %v", err)
+	}
+
+	types, _ = pendingSearchEventTypes(t, ctx, repo, review.ID)
+	if len(types) != 0 {
+		t.Errorf("Expected no pending events after draining, got %v", types)
+	}
+}
+
+func TestReviewRepository_SoftDelete(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewReviewRepository(testDB)
+	ctx := context.Background()
+
+	albumID, userIDs, cleanup := setupReviewFixtures(t, ctx, 1)
+	defer cleanup()
+
+	review := setupTestReview(t, userIDs[0], albumID, 4, time.Now())
+	if err := repo.Create(ctx, review); err != nil {
+		t.Fatalf("Failed to create review: %v", err)
+	}
+
+	if err := repo.Delete(ctx, review.ID); err != nil {
+		t.Fatalf("Failed to delete review: %v", err)
+	}
+
+	// Soft-deleted reviews are hidden from reads and aggregates
+	if _, err := repo.GetByID(ctx, review.ID); err == nil {
+		t.Error("Expected error when getting deleted review")
+	}
+
+	distribution, err := repo.GetRatingDistribution(ctx, albumID)
+	if err != nil {
+		t.Fatalf("Failed to get rating distribution: %v", err)
+	}
+	if distribution.Total != 0 {
+		t.Errorf("Expected deleted review to be excluded, got total %d", distribution.Total)
+	}
+
+	if err := repo.Delete(ctx, review.ID); err == nil {
+		t.Error("Expected error when deleting an already deleted review")
+	}
+
+	if err := repo.Restore(ctx, review.ID); err != nil {
+		t.Fatalf("Failed to restore review: %v", err)
+	}
+
+	restored, err := repo.GetByID(ctx, review.ID)
+	if err != nil {
+		t.Fatalf("Failed to get restored review: %v", err)
+	}
+	if restored.ReviewText == nil || *restored.ReviewText != *review.ReviewText {
+		t.Error("Expected restored review to keep its text")
+	}
+
+	// A deleted review does not block a new review of the same album, and
+	// the old one can then no longer be restored over it
+	if err := repo.Delete(ctx, review.ID); err != nil {
+		t.Fatalf("Failed to delete review: %v", err)
+	}
+
+	replacement := setupTestReview(t, userIDs[0], albumID, 2, time.Now())
+	if err := repo.Create(ctx, replacement); err != nil {
+		t.Fatalf("Failed to create replacement review: %v", err)
+	}
+
+	if err := repo.Restore(ctx, review.ID); !errors.Is(err, repository.ErrDuplicateReview) {
+		t.Errorf("Expected ErrDuplicateReview, got %v", err)
+	}
+}
+
+func TestReviewRepository_PurgeSoftDeleted(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewReviewRepository(testDB)
+	ctx := context.Background()
+
+	albumID, userIDs, cleanup := setupReviewFixtures(t, ctx, 2)
+	defer cleanup()
+
+	deleted := setupTestReview(t, userIDs[0], albumID, 3, time.Now())
+	kept := setupTestReview(t, userIDs[1], albumID, 5, time.Now())
+	for _, review := range []*models.Review{deleted, kept} {
+		if err := repo.Create(ctx, review); err != nil {
+			t.Fatalf("Failed to create review: %v", err)
+		}
+	}
+
+	if err := repo.Delete(ctx, deleted.ID); err != nil {
+		t.Fatalf("Failed to delete review: %v", err)
+	}
+
+	// Reviews deleted after the cutoff are kept
+	if _, err := repo.PurgeSoftDeleted(ctx, time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("Failed to purge reviews: %v", err)
+	}
+	if err := repo.Restore(ctx, deleted.ID); err != nil {
+		t.Fatalf("Expected recently deleted review to survive purge: %v", err)
+	}
+
+	if err := repo.Delete(ctx, deleted.ID); err != nil {
+		t.Fatalf("Failed to delete review: %v", err)
+	}
+
+	purged, err := repo.PurgeSoftDeleted(ctx, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Failed to purge reviews: %v", err)
+	}
+	if purged < 1 {
+		t.Errorf("Expected at least 1 purged review, got %d", purged)
+	}
+
+	if err := repo.Restore(ctx, deleted.ID); err == nil {
+		t.Error("Expected error when restoring a purged review")
+	}
+
+	if _, err := repo.GetByID(ctx, kept.ID); err != nil {
+		t.Errorf("Expected live review to survive purge: %v", err)
+	}
+}
+
+func TestReviewRepository_ListConnection(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewReviewRepository(testDB)
+	ctx := context.Background()
+
+	albumID, userIDs, cleanup := setupReviewFixtures(t, ctx, 6)
+	defer cleanup()
+
+	// Five reviews an hour apart, newest first in expected
+	now := time.Now()
+	var expected []uuid.UUID
+	for i := 0; i < 5; i++ {
+		review := setupTestReview(t, userIDs[i], albumID, 3, now.Add(-time.Duration(i)*time.Hour))
+		if err := repo.Create(ctx, review); err != nil {
+			t.Fatalf("Failed to create review: %v", err)
+		}
+		expected = append(expected, review.ID)
+	}
+
+	seen := make(map[uuid.UUID]int)
+	var order []uuid.UUID
+	var after *string
+	for page := 0; ; page++ {
+		connection, err := repo.ListConnection(ctx, 2, after, nil)
+		if err != nil {
+			t.Fatalf("Failed to list reviews: %v", err)
+		}
+
+		for _, edge := range connection.Edges {
+			seen[edge.Node.ID]++
+			if edge.Node.AlbumID == albumID {
+				order = append(order, edge.Node.ID)
+			}
+		}
+
+		// Inserting a newer review mid-walk must not shift later pages
+		if page == 0 {
+			late := setupTestReview(t, userIDs[5], albumID, 3, time.Now().Add(time.Minute))
+			if err := repo.Create(ctx, late); err != nil {
+				t.Fatalf("Failed to create review: %v", err)
+			}
+		}
+
+		if !connection.PageInfo.HasNextPage {
+			break
+		}
+		after = connection.PageInfo.EndCursor
+	}
+
+	for id, count := range seen {
+		if count != 1 {
+			t.Errorf("Expected review %s once, saw it %d times", id, count)
+		}
+	}
+
+	// The late insert sorts before the first page, so only the original five appear
+	if len(order) != len(expected) {
+		t.Fatalf("Expected %d reviews for the album, got %d", len(expected), len(order))
+	}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Errorf("Expected review %s at position %d, got %s", expected[i], i, order[i])
+		}
+	}
+}
+
+func TestReviewRepository_ListConnection_Backward(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewReviewRepository(testDB)
+	ctx := context.Background()
+
+	albumID, userIDs, cleanup := setupReviewFixtures(t, ctx, 5)
+	defer cleanup()
+
+	// Dated far in the future so they make up the first pages on their own
+	base := time.Now().Add(24 * time.Hour)
+	var expected []uuid.UUID
+	for i := 0; i < 5; i++ {
+		review := setupTestReview(t, userIDs[i], albumID, 3, base.Add(-time.Duration(i)*time.Hour))
+		if err := repo.Create(ctx, review); err != nil {
+			t.Fatalf("Failed to create review: %v", err)
+		}
+		expected = append(expected, review.ID)
+	}
+
+	// Walk forward over the five fixtures, two per page
+	var pages []*models.Connection[models.Review]
+	var after *string
+	for len(pages) < 3 {
+		connection, err := repo.ListConnection(ctx, 2, after, nil)
+		if err != nil {
+			t.Fatalf("Failed to list reviews: %v", err)
+		}
+		if connection.PageInfo.HasPreviousPage != (after != nil) {
+			t.Errorf("Expected HasPreviousPage %v on forward page %d", after != nil, len(pages))
+		}
+		pages = append(pages, connection)
+		after = connection.PageInfo.EndCursor
+	}
+
+	// Walk back from the last forward page to the start
+	var backward []uuid.UUID
+	before := pages[len(pages)-1].PageInfo.StartCursor
+	for {
+		connection, err := repo.ListConnection(ctx, 2, nil, before)
+		if err != nil {
+			t.Fatalf("Failed to list reviews backwards: %v", err)
+		}
+		if !connection.PageInfo.HasNextPage {
+			t.Error("Expected HasNextPage when paging backwards")
+		}
+
+		var page []uuid.UUID
+		for _, edge := range connection.Edges {
+			page = append(page, edge.Node.ID)
+		}
+		backward = append(page, backward...)
+
+		if !connection.PageInfo.HasPreviousPage {
+			break
+		}
+		before = connection.PageInfo.StartCursor
+	}
+
+	// Backward pages plus the page we started from cover the fixtures exactly
+	for _, edge := range pages[len(pages)-1].Edges {
+		backward = append(backward, edge.Node.ID)
+	}
+	if len(backward) < len(expected) {
+		t.Fatalf("Expected at least %d reviews, got %d", len(expected), len(backward))
+	}
+	for i := range expected {
+		if backward[i] != expected[i] {
+			t.Errorf("Expected review %s at position %d, got %s", expected[i], i, backward[i])
+		}
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	for _, id := range backward {
+		if seen[id] {
+			t.Errorf("Expected review %s once", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestReviewRepository_ListConnection_TotalCount(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewReviewRepository(testDB)
+	ctx := context.Background()
+
+	albumID, userIDs, cleanup := setupReviewFixtures(t, ctx, 5)
+	defer cleanup()
+
+	now := time.Now()
+	for i, userID := range userIDs {
+		review := setupTestReview(t, userID, albumID, 4, now.Add(-time.Duration(i)*time.Minute))
+		if err := repo.Create(ctx, review); err != nil {
+			t.Fatalf("Failed to create review: %v", err)
+		}
+	}
+
+	var want int
+	if err := testDB.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM reviews WHERE deleted_at IS NULL`).Scan(&want); err != nil {
+		t.Fatalf("Failed to count reviews: %v", err)
+	}
+
+	// Every page, in both directions and past the end, reports the same total
+	var first, last *models.Connection[models.Review]
+	var after *string
+	for {
+		connection, err := repo.ListConnection(ctx, 2, after, nil)
+		if err != nil {
+			t.Fatalf("Failed to list reviews: %v", err)
+		}
+		if connection.TotalCount != want {
+			t.Errorf("Expected total count %d, got %d", want, connection.TotalCount)
+		}
+		if first == nil {
+			first = connection
+		}
+		last = connection
+
+		if !connection.PageInfo.HasNextPage {
+			break
+		}
+		after = connection.PageInfo.EndCursor
+	}
+
+	if last != first {
+		connection, err := repo.ListConnection(ctx, 2, nil, last.PageInfo.StartCursor)
+		if err != nil {
+			t.Fatalf("Failed to list reviews backwards: %v", err)
+		}
+		if connection.TotalCount != want {
+			t.Errorf("Expected total count %d paging backwards, got %d", want, connection.TotalCount)
+		}
+	}
+
+	empty, err := repo.ListConnection(ctx, 2, last.PageInfo.EndCursor, nil)
+	if err != nil {
+		t.Fatalf("Failed to list reviews: %v", err)
+	}
+	if len(empty.Edges) != 0 || empty.TotalCount != want {
+		t.Errorf("Expected an empty page with total count %d, got %d edges and total %d", want, len(empty.Edges), empty.TotalCount)
+	}
+}
+
+func TestReviewRepository_ListConnection_BothCursors(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewReviewRepository(testDB)
+	cursor := encodeCursor(time.Now(), uuid.New())
+
+	if _, err := repo.ListConnection(context.Background(), 10, &cursor, &cursor); err == nil {
+		t.Error("Expected error when both cursors are set")
+	}
+}
+
+func TestReviewRepository_ListConnection_InvalidCursor(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewReviewRepository(testDB)
+	cursor := "not-a-cursor"
+
+	if _, err := repo.ListConnection(context.Background(), 10, &cursor, nil); err == nil {
+		t.Error("Expected error for invalid cursor")
+	}
+}
+
+func TestReviewRepository_GetRatingByCountry(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewReviewRepository(testDB)
+	userRepo := NewUserRepository(testDB)
+	ctx := context.Background()
+
+	albumID, userIDs, cleanup := setupReviewFixtures(t, ctx, 4)
+	defer cleanup()
+
+	// Two reviewers from the US, one from Japan and one with no country
+	countries := []*string{stringPtr("US"), stringPtr("US"), stringPtr("JP"), nil}
+	ratings := []int{4, 2, 5, 1}
+	for i, userID := range userIDs {
+		user, err := userRepo.GetByID(ctx, userID)
+		if err != nil {
+			t.Fatalf("Failed to get user: %v", err)
+		}
+		user.Country = countries[i]
+		if err := userRepo.Update(ctx, user); err != nil {
+			t.Fatalf("Failed to update user: %v", err)
+		}
+
+		review := setupTestReview(t, userID, albumID, ratings[i], time.Now())
+		if err := repo.Create(ctx, review); err != nil {
+			t.Fatalf("Failed to create review: %v", err)
+		}
+	}
+
+	stats, err := repo.GetRatingByCountry(ctx, albumID)
+	if err != nil {
+		t.Fatalf("Failed to get ratings by country: %v", err)
+	}
+
+	if len(stats) != 2 {
+		t.Fatalf("Expected stats for 2 countries, got %d", len(stats))
+	}
+
+	if us := stats["US"]; us.Count != 2 || us.AverageRating != 3 {
+		t.Errorf("Expected US count 2 average 3, got count %d average %v", us.Count, us.AverageRating)
+	}
+
+	if jp := stats["JP"]; jp.Count != 1 || jp.AverageRating != 5 {
+		t.Errorf("Expected JP count 1 average 5, got count %d average %v", jp.Count, jp.AverageRating)
+	}
+}
+
+func TestReviewRepository_GetReviewSummary(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewReviewRepository(testDB)
+	ctx := context.Background()
+
+	albumID, userIDs, cleanup := setupReviewFixtures(t, ctx, 3)
+	defer cleanup()
+
+	now := time.Now()
+	for i, rating := range []int{5, 4, 3} {
+		review := setupTestReview(t, userIDs[i], albumID, rating, now.Add(-time.Duration(i)*time.Minute))
+		if err := repo.Create(ctx, review); err != nil {
+			t.Fatalf("Failed to create review: %v", err)
+		}
+	}
+
+	summary, err := repo.GetReviewSummary(ctx, albumID, 2, 0)
+	if err != nil {
+		t.Fatalf("Failed to get review summary: %v", err)
+	}
+
+	if len(summary.Reviews) != 2 {
+		t.Errorf("Expected page of 2 reviews, got %d", len(summary.Reviews))
+	}
+
+	if summary.Count != 3 {
+		t.Errorf("Expected count 3, got %d", summary.Count)
+	}
+
+	if summary.AverageRating != 4 {
+		t.Errorf("Expected average 4, got %v", summary.AverageRating)
+	}
+
+	expected := map[int]int{1: 0, 2: 0, 3: 1, 4: 1, 5: 1}
+	for rating, want := range expected {
+		if got := summary.Distribution[rating]; got != want {
+			t.Errorf("Expected %d reviews with rating %d, got %d", want, rating, got)
+		}
+	}
+
+	// Paging past the end still returns the overall stats
+	summary, err = repo.GetReviewSummary(ctx, albumID, 2, 10)
+	if err != nil {
+		t.Fatalf("Failed to get review summary: %v", err)
+	}
+
+	if len(summary.Reviews) != 0 || summary.Count != 3 {
+		t.Errorf("Expected empty page with count 3, got %d reviews and count %d", len(summary.Reviews), summary.Count)
+	}
+}
+
+// setupBenchReviews seeds an album with reviews for the summary benchmarks
+func setupBenchReviews(b *testing.B, ctx context.Context) (uuid.UUID, func()) {
+	b.Helper()
+
+	repo := NewReviewRepository(testDB)
+	albumID, userIDs, cleanup := setupReviewFixtures(b, ctx, 20)
+
+	for i, userID := range userIDs {
+		review := setupTestReview(b, userID, albumID, i%5+1, time.Now())
+		if err := repo.Create(ctx, review); err != nil {
+			cleanup()
+			b.Fatalf("Failed to create review: %v", err)
+		}
+	}
+
+	return albumID, cleanup
+}
+
+func BenchmarkReviewRepository_GetReviewSummary(b *testing.B) {
+	if testDB == nil {
+		b.Skip("Database not available")
+	}
+
+	repo := NewReviewRepository(testDB)
+	ctx := context.Background()
+
+	albumID, cleanup := setupBenchReviews(b, ctx)
+	defer cleanup()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.GetReviewSummary(ctx, albumID, 10, 0); err != nil {
+			b.Fatalf("Failed to get review summary: %v", err)
+		}
+	}
+}
+
+// BenchmarkReviewRepository_SeparateCalls fetches the same data as
+// GetReviewSummary the old way: a page of reviews plus a separate stats query
+func BenchmarkReviewRepository_SeparateCalls(b *testing.B) {
+	if testDB == nil {
+		b.Skip("Database not available")
+	}
+
+	repo := NewReviewRepository(testDB)
+	ctx := context.Background()
+
+	albumID, cleanup := setupBenchReviews(b, ctx)
+	defer cleanup()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.GetByAlbumID(ctx, albumID, 10, 0); err != nil {
+			b.Fatalf("Failed to get reviews: %v", err)
+		}
+		if _, err := repo.GetRatingDistribution(ctx, albumID); err != nil {
+			b.Fatalf("Failed to get rating distribution: %v", err)
+		}
+	}
+}
+
+func TestReviewRepository_GetMostReviewed(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewReviewRepository(testDB)
+	albumRepo := NewAlbumRepository(testDB)
+	ctx := context.Background()
+
+	popularID, popularUsers, cleanupPopular := setupReviewFixtures(t, ctx, 3)
+	defer cleanupPopular()
+	quietID, quietUsers, cleanupQuiet := setupReviewFixtures(t, ctx, 2)
+	defer cleanupQuiet()
+
+	// Reviews are dated far in the future so other test data falls outside
+	// the window
+	since := time.Now().AddDate(100, 0, 0)
+
+	for i, userID := range popularUsers {
+		review := setupTestReview(t, userID, popularID, []int{5, 4, 3}[i], since.Add(time.Duration(i)*time.Minute))
+		if err := repo.Create(ctx, review); err != nil {
+			t.Fatalf("Failed to create review: %v", err)
+		}
+	}
+
+	// One of the quiet album's reviews predates the window
+	for i, userID := range quietUsers {
+		createdAt := since.Add(time.Minute)
+		if i == 0 {
+			createdAt = since.Add(-time.Hour)
+		}
+		review := setupTestReview(t, userID, quietID, []int{1, 2}[i], createdAt)
+		if err := repo.Create(ctx, review); err != nil {
+			t.Fatalf("Failed to create review: %v", err)
+		}
+	}
+
+	aggregates, err := repo.GetMostReviewed(ctx, "album", since, 10)
+	if err != nil {
+		t.Fatalf("Failed to get most reviewed: %v", err)
+	}
+
+	if len(aggregates) != 2 {
+		t.Fatalf("Expected 2 reviewed albums, got %d", len(aggregates))
+	}
+
+	popular, err := albumRepo.GetByID(ctx, popularID)
+	if err != nil {
+		t.Fatalf("Failed to get album: %v", err)
+	}
+	quiet, err := albumRepo.GetByID(ctx, quietID)
+	if err != nil {
+		t.Fatalf("Failed to get album: %v", err)
+	}
+
+	if aggregates[0].SpotifyID != *popular.SpotifyID || aggregates[0].Count != 3 || aggregates[0].AverageRating != 4 {
+		t.Errorf("Expected popular album first with 3 reviews averaging 4, got %+v", aggregates[0])
+	}
+	if aggregates[1].SpotifyID != *quiet.SpotifyID || aggregates[1].Count != 1 || aggregates[1].AverageRating != 2 {
+		t.Errorf("Expected quiet album second with 1 review averaging 2, got %+v", aggregates[1])
+	}
+
+	// Narrowing the limit keeps the most reviewed album
+	top, err := repo.GetMostReviewed(ctx, "album", since, 1)
+	if err != nil {
+		t.Fatalf("Failed to get most reviewed: %v", err)
+	}
+	if len(top) != 1 || top[0].SpotifyID != *popular.SpotifyID {
+		t.Errorf("Expected only the popular album, got %+v", top)
+	}
+
+	if _, err := repo.GetMostReviewed(ctx, "track", since, 10); err == nil {
+		t.Error("Expected an error for an item type that cannot be reviewed")
+	}
+}
+
+func TestReviewRepository_GetTopRated(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewReviewRepository(testDB)
+	albumRepo := NewAlbumRepository(testDB)
+	ctx := context.Background()
+
+	// A well reviewed album, a slightly lower rated one with the same number
+	// of reviews, and a single perfect review that is below the threshold
+	albumRatings := [][]int{{5, 5, 4}, {4, 4, 4}, {5}}
+
+	spotifyIDs := make([]string, len(albumRatings))
+	for i, ratings := range albumRatings {
+		albumID, userIDs, cleanup := setupReviewFixtures(t, ctx, len(ratings))
+		defer cleanup()
+
+		for j, userID := range userIDs {
+			review := setupTestReview(t, userID, albumID, ratings[j], time.Now())
+			if err := repo.Create(ctx, review); err != nil {
+				t.Fatalf("Failed to create review: %v", err)
+			}
+		}
+
+		album, err := albumRepo.GetByID(ctx, albumID)
+		if err != nil {
+			t.Fatalf("Failed to get album: %v", err)
+		}
+		spotifyIDs[i] = *album.SpotifyID
+	}
+
+	aggregates, err := repo.GetTopRated(ctx, "album", 3, 100)
+	if err != nil {
+		t.Fatalf("Failed to get top rated: %v", err)
+	}
+
+	positions := make(map[string]int)
+	for i, aggregate := range aggregates {
+		if aggregate.Count < 3 {
+			t.Errorf("Expected only items with at least 3 reviews, got %+v", aggregate)
+		}
+		positions[aggregate.SpotifyID] = i
+	}
+
+	best, ok := positions[spotifyIDs[0]]
+	if !ok {
+		t.Fatal("Expected the well reviewed album to be included")
+	}
+	second, ok := positions[spotifyIDs[1]]
+	if !ok {
+		t.Fatal("Expected the lower rated album to be included")
+	}
+	if best >= second {
+		t.Errorf("Expected the higher average to rank first, got positions %d and %d", best, second)
+	}
+	if _, ok := positions[spotifyIDs[2]]; ok {
+		t.Error("Expected the album below the review threshold to be excluded")
+	}
+
+	if aggregates[best].Count != 3 || aggregates[best].AverageRating < 4.66 || aggregates[best].AverageRating > 4.67 {
+		t.Errorf("Expected 3 reviews averaging about 4.67, got %+v", aggregates[best])
+	}
+
+	// Lowering the threshold lets the single review in
+	aggregates, err = repo.GetTopRated(ctx, "album", 1, 100)
+	if err != nil {
+		t.Fatalf("Failed to get top rated: %v", err)
+	}
+	found := false
+	for _, aggregate := range aggregates {
+		if aggregate.SpotifyID == spotifyIDs[2] {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected the single review album with a threshold of 1")
+	}
+}
+
+func TestReviewRepository_GetAverageRatings(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewReviewRepository(testDB)
+	albumRepo := NewAlbumRepository(testDB)
+	ctx := context.Background()
+
+	// The last album has no reviews
+	albumRatings := [][]int{{4, 5}, {3}, {}}
+
+	spotifyIDs := make([]string, len(albumRatings))
+	for i, ratings := range albumRatings {
+		albumID, userIDs, cleanup := setupReviewFixtures(t, ctx, len(ratings))
+		defer cleanup()
+
+		for j, userID := range userIDs {
+			review := setupTestReview(t, userID, albumID, ratings[j], time.Now())
+			if err := repo.Create(ctx, review); err != nil {
+				t.Fatalf("Failed to create review: %v", err)
+			}
+		}
+
+		album, err := albumRepo.GetByID(ctx, albumID)
+		if err != nil {
+			t.Fatalf("Failed to get album: %v", err)
+		}
+		spotifyIDs[i] = *album.SpotifyID
+	}
+
+	ratings, err := repo.GetAverageRatings(ctx, "album", spotifyIDs)
+	if err != nil {
+		t.Fatalf("Failed to get average ratings: %v", err)
+	}
+
+	if len(ratings) != len(spotifyIDs) {
+		t.Fatalf("Expected a rating for each of %d albums, got %v", len(spotifyIDs), ratings)
+	}
+	if ratings[spotifyIDs[0]] != 4.5 {
+		t.Errorf("Expected average 4.5, got %v", ratings[spotifyIDs[0]])
+	}
+	if ratings[spotifyIDs[1]] != 3 {
+		t.Errorf("Expected average 3, got %v", ratings[spotifyIDs[1]])
+	}
+	if rating, ok := ratings[spotifyIDs[2]]; !ok || rating != 0 {
+		t.Errorf("Expected the unreviewed album to default to 0, got %v (present: %v)", rating, ok)
+	}
+
+	if _, err := repo.GetAverageRatings(ctx, "track", spotifyIDs); err == nil {
+		t.Error("Expected an error for an item type that cannot be reviewed")
+	}
+}
@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/daedal00/muse/backend/internal/database"
+	"github.com/daedal00/muse/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+type reviewVoteRepository struct {
+	db *dbConn
+}
+
+func NewReviewVoteRepository(db *database.PostgresDB) repository.ReviewVoteRepository {
+	return &reviewVoteRepository{db: newDBConn(db)}
+}
+
+func newReviewVoteRepository(q querier) *reviewVoteRepository {
+	return &reviewVoteRepository{db: &dbConn{Pool: q}}
+}
+
+// Vote marks the review helpful for userID. Voting again is a no-op; voting
+// on a missing or deleted review returns ErrNotFound.
+func (r *reviewVoteRepository) Vote(ctx context.Context, reviewID, userID uuid.UUID) error {
+	query := `
+		WITH live AS (
+			SELECT id FROM reviews WHERE id = $1 AND deleted_at IS NULL
+		), inserted AS (
+			INSERT INTO review_votes (review_id, user_id)
+			SELECT id, $2 FROM live
+			ON CONFLICT (review_id, user_id) DO NOTHING
+		)
+		SELECT EXISTS (SELECT 1 FROM live)
+	`
+
+	var found bool
+	if err := r.db.Pool.QueryRow(ctx, query, reviewID, userID).Scan(&found); err != nil {
+		return fmt.Errorf("failed to vote on review: %w", err)
+	}
+	if !found {
+		return fmt.Errorf("review %w", repository.ErrNotFound)
+	}
+
+	return nil
+}
+
+// Unvote removes userID's vote if there is one
+func (r *reviewVoteRepository) Unvote(ctx context.Context, reviewID, userID uuid.UUID) error {
+	query := `DELETE FROM review_votes WHERE review_id = $1 AND user_id = $2`
+
+	if _, err := r.db.Pool.Exec(ctx, query, reviewID, userID); err != nil {
+		return fmt.Errorf("failed to remove review vote: %w", err)
+	}
+
+	return nil
+}
+
+// GetVoteCount counts the votes on a review; a deleted review has none
+func (r *reviewVoteRepository) GetVoteCount(ctx context.Context, reviewID uuid.UUID) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM review_votes v
+		JOIN reviews rv ON rv.id = v.review_id
+		WHERE v.review_id = $1 AND rv.deleted_at IS NULL
+	`
+
+	var count int
+	if err := r.db.Reader().QueryRow(ctx, query, reviewID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count review votes: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *reviewVoteRepository) HasVoted(ctx context.Context, reviewID, userID uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM review_votes v
+			JOIN reviews rv ON rv.id = v.review_id
+			WHERE v.review_id = $1 AND v.user_id = $2 AND rv.deleted_at IS NULL
+		)
+	`
+
+	var voted bool
+	if err := r.db.Reader().QueryRow(ctx, query, reviewID, userID).Scan(&voted); err != nil {
+		return false, fmt.Errorf("failed to check review vote: %w", err)
+	}
+
+	return voted, nil
+}
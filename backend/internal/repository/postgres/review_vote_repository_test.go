@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/daedal00/muse/backend/internal/repository"
+)
+
+func TestReviewVoteRepository_VoteUnvote(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewReviewVoteRepository(testDB)
+	reviews := NewReviewRepository(testDB)
+	ctx := context.Background()
+
+	albumID, userIDs, cleanup := setupReviewFixtures(t, ctx, 2)
+	defer cleanup()
+	author, voter := userIDs[0], userIDs[1]
+
+	review := setupTestReview(t, author, albumID, 4, time.Now())
+	if err := reviews.Create(ctx, review); err != nil {
+		t.Fatalf("Failed to create review: %v", err)
+	}
+
+	// Voting twice counts once
+	for i := 0; i < 2; i++ {
+		if err := repo.Vote(ctx, review.ID, voter); err != nil {
+			t.Fatalf("Failed to vote: %v", err)
+		}
+	}
+
+	voted, err := repo.HasVoted(ctx, review.ID, voter)
+	if err != nil {
+		t.Fatalf("Failed to check vote: %v", err)
+	}
+	if !voted {
+		t.Error("Expected voter to have voted")
+	}
+
+	count, err := repo.GetVoteCount(ctx, review.ID)
+	if err != nil {
+		t.Fatalf("Failed to count votes: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 vote, got %d", count)
+	}
+
+	// Unvoting twice is also a no-op the second time
+	for i := 0; i < 2; i++ {
+		if err := repo.Unvote(ctx, review.ID, voter); err != nil {
+			t.Fatalf("Failed to unvote: %v", err)
+		}
+	}
+
+	voted, err = repo.HasVoted(ctx, review.ID, voter)
+	if err != nil {
+		t.Fatalf("Failed to check vote: %v", err)
+	}
+	if voted {
+		t.Error("Expected vote to be removed")
+	}
+
+	count, err = repo.GetVoteCount(ctx, review.ID)
+	if err != nil {
+		t.Fatalf("Failed to count votes: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("Expected 0 votes, got %d", count)
+	}
+}
+
+func TestReviewVoteRepository_CountsAcrossUsers(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewReviewVoteRepository(testDB)
+	reviews := NewReviewRepository(testDB)
+	ctx := context.Background()
+
+	albumID, userIDs, cleanup := setupReviewFixtures(t, ctx, 4)
+	defer cleanup()
+
+	review := setupTestReview(t, userIDs[0], albumID, 5, time.Now())
+	if err := reviews.Create(ctx, review); err != nil {
+		t.Fatalf("Failed to create review: %v", err)
+	}
+
+	for _, id := range userIDs[1:] {
+		if err := repo.Vote(ctx, review.ID, id); err != nil {
+			t.Fatalf("Failed to vote: %v", err)
+		}
+	}
+	if err := repo.Unvote(ctx, review.ID, userIDs[1]); err != nil {
+		t.Fatalf("Failed to unvote: %v", err)
+	}
+
+	count, err := repo.GetVoteCount(ctx, review.ID)
+	if err != nil {
+		t.Fatalf("Failed to count votes: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 votes, got %d", count)
+	}
+
+	found, votes, err := reviews.GetByIDWithVoteCount(ctx, review.ID)
+	if err != nil {
+		t.Fatalf("Failed to get review with vote count: %v", err)
+	}
+	if found.ID != review.ID || votes != 2 {
+		t.Errorf("Expected review %s with 2 votes, got %s with %d", review.ID, found.ID, votes)
+	}
+
+	// A soft-deleted review counts no votes and takes no new ones
+	if err := reviews.Delete(ctx, review.ID); err != nil {
+		t.Fatalf("Failed to delete review: %v", err)
+	}
+
+	count, err = repo.GetVoteCount(ctx, review.ID)
+	if err != nil {
+		t.Fatalf("Failed to count votes: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("Expected no votes on a deleted review, got %d", count)
+	}
+	if err := repo.Vote(ctx, review.ID, userIDs[1]); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound voting on a deleted review, got %v", err)
+	}
+
+	// Hard-deleting the review removes its votes with it. Only this test's
+	// review is removed, so other tests' soft-deleted rows are left alone.
+	if _, err := testDB.Pool.Exec(ctx, `DELETE FROM reviews WHERE id = $1`, review.ID); err != nil {
+		t.Fatalf("Failed to purge review: %v", err)
+	}
+
+	// GetVoteCount hides votes on deleted reviews, so count the rows directly
+	if err := testDB.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM review_votes WHERE review_id = $1`, review.ID).Scan(&count); err != nil {
+		t.Fatalf("Failed to count votes: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("Expected votes to cascade with the review, got %d", count)
+	}
+}
@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/daedal00/muse/backend/internal/database"
 	"github.com/daedal00/muse/backend/internal/models"
@@ -12,21 +13,22 @@ import (
 )
 
 type sessionRepository struct {
-	db *database.PostgresDB
+	db *dbConn
 }
 
 func NewSessionRepository(db *database.PostgresDB) repository.SessionRepository {
-	return &sessionRepository{db: db}
+	return &sessionRepository{db: newDBConn(db)}
 }
 
 func (r *sessionRepository) Create(ctx context.Context, session *models.Session) error {
 	query := `
-		INSERT INTO sessions (id, user_id, expires_at, created_at)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO sessions (id, user_id, expires_at, created_at, user_agent, ip_address, last_seen_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $4)
 	`
 
 	_, err := r.db.Pool.Exec(ctx, query,
 		session.ID, session.UserID, session.ExpiresAt, session.CreatedAt,
+		session.UserAgent, session.IPAddress,
 	)
 
 	if err != nil {
@@ -38,7 +40,7 @@ func (r *sessionRepository) Create(ctx context.Context, session *models.Session)
 
 func (r *sessionRepository) GetByID(ctx context.Context, id string) (*models.Session, error) {
 	query := `
-		SELECT id, user_id, expires_at, created_at
+		SELECT id, user_id, expires_at, created_at, user_agent, ip_address, last_seen_at
 		FROM sessions 
 		WHERE id = $1 AND expires_at > NOW()
 	`
@@ -46,11 +48,12 @@ func (r *sessionRepository) GetByID(ctx context.Context, id string) (*models.Ses
 	session := &models.Session{}
 	err := r.db.Pool.QueryRow(ctx, query, id).Scan(
 		&session.ID, &session.UserID, &session.ExpiresAt, &session.CreatedAt,
+		&session.UserAgent, &session.IPAddress, &session.LastSeenAt,
 	)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("session not found or expired")
+			return nil, fmt.Errorf("session %w or expired", repository.ErrNotFound)
 		}
 		return nil, fmt.Errorf("failed to get session: %w", err)
 	}
@@ -60,7 +63,7 @@ func (r *sessionRepository) GetByID(ctx context.Context, id string) (*models.Ses
 
 func (r *sessionRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*models.Session, error) {
 	query := `
-		SELECT id, user_id, expires_at, created_at
+		SELECT id, user_id, expires_at, created_at, user_agent, ip_address, last_seen_at
 		FROM sessions 
 		WHERE user_id = $1 AND expires_at > NOW()
 		ORDER BY created_at DESC
@@ -77,6 +80,7 @@ func (r *sessionRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (
 		session := &models.Session{}
 		err := rows.Scan(
 			&session.ID, &session.UserID, &session.ExpiresAt, &session.CreatedAt,
+			&session.UserAgent, &session.IPAddress, &session.LastSeenAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan session: %w", err)
@@ -91,6 +95,36 @@ func (r *sessionRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (
 	return sessions, nil
 }
 
+func (r *sessionRepository) Refresh(ctx context.Context, id string, newExpiry time.Time) error {
+	query := `UPDATE sessions SET expires_at = $2 WHERE id = $1 AND expires_at > NOW()`
+
+	result, err := r.db.Pool.Exec(ctx, query, id, newExpiry)
+	if err != nil {
+		return fmt.Errorf("failed to refresh session: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("session %w", repository.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *sessionRepository) UpdateLastSeen(ctx context.Context, id string) error {
+	query := `UPDATE sessions SET last_seen_at = NOW() WHERE id = $1 AND expires_at > NOW()`
+
+	result, err := r.db.Pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to update session last seen: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("session %w", repository.ErrNotFound)
+	}
+
+	return nil
+}
+
 func (r *sessionRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM sessions WHERE id = $1`
 
@@ -100,7 +134,7 @@ func (r *sessionRepository) Delete(ctx context.Context, id string) error {
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("session not found")
+		return fmt.Errorf("session %w", repository.ErrNotFound)
 	}
 
 	return nil
@@ -127,3 +161,49 @@ func (r *sessionRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID
 
 	return nil
 }
+
+func (r *sessionRepository) DeleteByUserIDExcept(ctx context.Context, userID uuid.UUID, keepSessionID string) error {
+	query := `DELETE FROM sessions WHERE user_id = $1 AND id <> $2`
+
+	_, err := r.db.Pool.Exec(ctx, query, userID, keepSessionID)
+	if err != nil {
+		return fmt.Errorf("failed to delete other sessions by user: %w", err)
+	}
+
+	return nil
+}
+
+func (r *sessionRepository) StoreRefreshToken(ctx context.Context, tokenHash, sessionID string, expiresAt time.Time) error {
+	query := `INSERT INTO refresh_tokens (token_hash, session_id, expires_at) VALUES ($1, $2, $3)`
+
+	if _, err := r.db.Pool.Exec(ctx, query, tokenHash, sessionID, expiresAt); err != nil {
+		return fmt.Errorf("failed to store refresh token: %w", err)
+	}
+
+	return nil
+}
+
+// RotateRefreshToken deletes the old token and inserts its replacement in
+// one statement, so two concurrent uses of a token cannot both succeed
+func (r *sessionRepository) RotateRefreshToken(ctx context.Context, oldHash, newHash string, expiresAt time.Time) (string, error) {
+	query := `
+		WITH used AS (
+			DELETE FROM refresh_tokens
+			WHERE token_hash = $1 AND expires_at > NOW()
+			RETURNING session_id
+		)
+		INSERT INTO refresh_tokens (token_hash, session_id, expires_at)
+		SELECT $2, session_id, $3 FROM used
+		RETURNING session_id
+	`
+
+	var sessionID string
+	if err := r.db.Pool.QueryRow(ctx, query, oldHash, newHash, expiresAt).Scan(&sessionID); err != nil {
+		if err == pgx.ErrNoRows {
+			return "", fmt.Errorf("refresh token %w", repository.ErrNotFound)
+		}
+		return "", fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+
+	return sessionID, nil
+}
@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// timeoutQuerier gives each statement a deadline of timeout when the caller's
+// context has none, so a hung connection cannot block a request forever.
+// Rows and row results stay usable until they are closed or scanned. Transactions
+// are passed through: their statements are bounded by the server-side
+// statement_timeout and the caller's context.
+type timeoutQuerier struct {
+	q       querier
+	timeout time.Duration
+}
+
+// withStatementTimeout wraps q so statements without a deadline get one;
+// a non-positive timeout leaves q unchanged
+func withStatementTimeout(q querier, timeout time.Duration) querier {
+	if timeout <= 0 {
+		return q
+	}
+	return &timeoutQuerier{q: q, timeout: timeout}
+}
+
+func (t *timeoutQuerier) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, t.timeout)
+}
+
+func (t *timeoutQuerier) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	ctx, cancel := t.context(ctx)
+	defer cancel()
+	return t.q.Exec(ctx, sql, args...)
+}
+
+func (t *timeoutQuerier) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	ctx, cancel := t.context(ctx)
+	rows, err := t.q.Query(ctx, sql, args...)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &timeoutRows{Rows: rows, cancel: cancel}, nil
+}
+
+func (t *timeoutQuerier) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	ctx, cancel := t.context(ctx)
+	return &timeoutRow{row: t.q.QueryRow(ctx, sql, args...), cancel: cancel}
+}
+
+func (t *timeoutQuerier) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	ctx, cancel := t.context(ctx)
+	defer cancel()
+	return t.q.CopyFrom(ctx, tableName, columnNames, rowSrc)
+}
+
+func (t *timeoutQuerier) Begin(ctx context.Context) (pgx.Tx, error) {
+	return t.q.Begin(ctx)
+}
+
+// timeoutRows releases its statement's deadline once the rows are done with
+type timeoutRows struct {
+	pgx.Rows
+	cancel context.CancelFunc
+}
+
+func (r *timeoutRows) Next() bool {
+	if r.Rows.Next() {
+		return true
+	}
+	r.cancel()
+	return false
+}
+
+func (r *timeoutRows) Close() {
+	r.Rows.Close()
+	r.cancel()
+}
+
+// timeoutRow releases its statement's deadline once the row is scanned
+type timeoutRow struct {
+	row    pgx.Row
+	cancel context.CancelFunc
+}
+
+func (r *timeoutRow) Scan(dest ...any) error {
+	defer r.cancel()
+	return r.row.Scan(dest...)
+}
@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/daedal00/muse/backend/internal/database"
+)
+
+func TestStatementTimeout_CancelsSlowQuery(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	conn := newDBConn(&database.PostgresDB{Pool: testDB.Pool, StatementTimeout: 200 * time.Millisecond})
+	ctx := context.Background()
+
+	start := time.Now()
+	_, err := conn.Pool.Exec(ctx, "SELECT pg_sleep(5)")
+	if err == nil {
+		t.Fatal("Expected slow query to be cancelled")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected query to be cancelled after about 200ms, took %s", elapsed)
+	}
+
+	// Rows stay readable after the query returns, until they are closed
+	rows, err := conn.Reader().Query(ctx, "SELECT generate_series(1, 3)")
+	if err != nil {
+		t.Fatalf("Failed to run query: %v", err)
+	}
+	defer rows.Close()
+
+	var count int
+	for rows.Next() {
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("Failed to read rows: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Expected 3 rows, got %d", count)
+	}
+
+	// A caller's own deadline is left in charge
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	var slept string
+	if err := conn.Pool.QueryRow(ctx, "SELECT pg_sleep(0.5)::text").Scan(&slept); err != nil {
+		t.Errorf("Expected query within the caller's deadline to succeed: %v", err)
+	}
+}
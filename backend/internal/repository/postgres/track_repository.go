@@ -12,11 +12,15 @@ import (
 )
 
 type trackRepository struct {
-	db *database.PostgresDB
+	db *dbConn
 }
 
 func NewTrackRepository(db *database.PostgresDB) repository.TrackRepository {
-	return &trackRepository{db: db}
+	return &trackRepository{db: newDBConn(db)}
+}
+
+func newTrackRepository(q querier) *trackRepository {
+	return &trackRepository{db: &dbConn{Pool: q}}
 }
 
 func (r *trackRepository) Create(ctx context.Context, track *models.Track) error {
@@ -31,28 +35,52 @@ func (r *trackRepository) Create(ctx context.Context, track *models.Track) error
 	)
 
 	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("track %w", repository.ErrDuplicate)
+		}
 		return fmt.Errorf("failed to create track: %w", err)
 	}
 
 	return nil
 }
 
-func (r *trackRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Track, error) {
-	query := `
-		SELECT id, spotify_id, title, album_id, duration_ms, track_number, created_at, updated_at
-		FROM tracks 
-		WHERE id = $1
-	`
-
-	track := &models.Track{}
-	err := r.db.Pool.QueryRow(ctx, query, id).Scan(
+// trackWithAlbumQuery selects tracks joined with their album and the album's
+// artist, in the column order scanTrackWithAlbum expects
+const trackWithAlbumQuery = `
+	SELECT t.id, t.spotify_id, t.title, t.album_id, t.duration_ms, t.track_number, t.created_at, t.updated_at,
+		a.id, a.spotify_id, a.title, a.artist_id, a.release_date, a.cover_image, a.created_at, a.updated_at,
+		ar.id, ar.spotify_id, ar.name, ar.created_at, ar.updated_at
+	FROM tracks t
+	JOIN albums a ON a.id = t.album_id
+	JOIN artists ar ON ar.id = a.artist_id
+`
+
+// scanTrackWithAlbum scans a row of trackWithAlbumQuery into a track with its
+// album and artist populated
+func scanTrackWithAlbum(row pgx.Row) (*models.Track, error) {
+	track := &models.Track{Album: &models.Album{Artist: &models.Artist{}}}
+	album, artist := track.Album, track.Album.Artist
+	err := row.Scan(
 		&track.ID, &track.SpotifyID, &track.Title, &track.AlbumID,
 		&track.DurationMs, &track.TrackNumber, &track.CreatedAt, &track.UpdatedAt,
+		&album.ID, &album.SpotifyID, &album.Title, &album.ArtistID,
+		&album.ReleaseDate, &album.CoverImage, &album.CreatedAt, &album.UpdatedAt,
+		&artist.ID, &artist.SpotifyID, &artist.Name, &artist.CreatedAt, &artist.UpdatedAt,
 	)
+	if err != nil {
+		return nil, err
+	}
+	return track, nil
+}
 
+// GetByID returns a track with its album and the album's artist populated
+func (r *trackRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Track, error) {
+	query := trackWithAlbumQuery + `WHERE t.id = $1`
+
+	track, err := scanTrackWithAlbum(r.db.Reader().QueryRow(ctx, query, id))
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("track not found")
+			return nil, fmt.Errorf("track %w", repository.ErrNotFound)
 		}
 		return nil, fmt.Errorf("failed to get track: %w", err)
 	}
@@ -60,6 +88,38 @@ func (r *trackRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Tr
 	return track, nil
 }
 
+// GetByIDs fetches several tracks in one query, keyed by ID, each with its
+// album and artist populated. IDs with no matching track are left out of
+// the map.
+func (r *trackRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.Track, error) {
+	tracks := make(map[uuid.UUID]*models.Track, len(ids))
+	if len(ids) == 0 {
+		return tracks, nil
+	}
+
+	query := trackWithAlbumQuery + `WHERE t.id = ANY($1)`
+
+	rows, err := r.db.Reader().Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tracks: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		track, err := scanTrackWithAlbum(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan track: %w", err)
+		}
+		tracks[track.ID] = track
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tracks: %w", err)
+	}
+
+	return tracks, nil
+}
+
 func (r *trackRepository) GetBySpotifyID(ctx context.Context, spotifyID string) (*models.Track, error) {
 	query := `
 		SELECT id, spotify_id, title, album_id, duration_ms, track_number, created_at, updated_at
@@ -68,14 +128,14 @@ func (r *trackRepository) GetBySpotifyID(ctx context.Context, spotifyID string)
 	`
 
 	track := &models.Track{}
-	err := r.db.Pool.QueryRow(ctx, query, spotifyID).Scan(
+	err := r.db.Reader().QueryRow(ctx, query, spotifyID).Scan(
 		&track.ID, &track.SpotifyID, &track.Title, &track.AlbumID,
 		&track.DurationMs, &track.TrackNumber, &track.CreatedAt, &track.UpdatedAt,
 	)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("track not found")
+			return nil, fmt.Errorf("track %w", repository.ErrNotFound)
 		}
 		return nil, fmt.Errorf("failed to get track: %w", err)
 	}
@@ -92,7 +152,7 @@ func (r *trackRepository) GetByAlbumID(ctx context.Context, albumID uuid.UUID, l
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query, albumID, limit, offset)
+	rows, err := r.db.Reader().Query(ctx, query, albumID, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list tracks by album: %w", err)
 	}
@@ -134,7 +194,7 @@ func (r *trackRepository) Update(ctx context.Context, track *models.Track) error
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("track not found")
+		return fmt.Errorf("track %w", repository.ErrNotFound)
 	}
 
 	return nil
@@ -149,7 +209,7 @@ func (r *trackRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("track not found")
+		return fmt.Errorf("track %w", repository.ErrNotFound)
 	}
 
 	return nil
@@ -163,7 +223,7 @@ func (r *trackRepository) List(ctx context.Context, limit, offset int) ([]*model
 		LIMIT $1 OFFSET $2
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query, limit, offset)
+	rows, err := r.db.Reader().Query(ctx, query, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list tracks: %w", err)
 	}
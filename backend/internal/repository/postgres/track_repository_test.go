@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/daedal00/muse/backend/internal/models"
+	"github.com/daedal00/muse/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// setupTrackChain creates an artist with one album holding the given number
+// of tracks. Deleting the artist cascades to the album and tracks.
+func setupTrackChain(t *testing.T, ctx context.Context, tracks int) (*models.Artist, *models.Album, []*models.Track) {
+	t.Helper()
+
+	artist := setupTestArtist(t)
+	if err := NewArtistRepository(testDB).Create(ctx, artist); err != nil {
+		t.Fatalf("Failed to create test artist: %v", err)
+	}
+	t.Cleanup(func() { cleanupTestArtist(t, ctx, artist.ID) })
+
+	album := setupTestAlbum(t, artist.ID)
+	if err := NewAlbumRepository(testDB).Create(ctx, album); err != nil {
+		t.Fatalf("Failed to create test album: %v", err)
+	}
+
+	trackRepo := NewTrackRepository(testDB)
+	created := make([]*models.Track, tracks)
+	for i := range created {
+		duration := 180000 + i
+		number := i + 1
+		track := &models.Track{
+			ID:          uuid.New(),
+			SpotifyID:   stringPtr(fmt.Sprintf("spotify_track_%s", uuid.New().String()[:8])),
+			Title:       fmt.Sprintf("Track %d", number),
+			AlbumID:     album.ID,
+			DurationMs:  &duration,
+			TrackNumber: &number,
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		}
+		if err := trackRepo.Create(ctx, track); err != nil {
+			t.Fatalf("Failed to create test track: %v", err)
+		}
+		created[i] = track
+	}
+
+	return artist, album, created
+}
+
+func TestTrackRepository_GetByID(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewTrackRepository(testDB)
+	ctx := context.Background()
+
+	artist, album, tracks := setupTrackChain(t, ctx, 1)
+
+	found, err := repo.GetByID(ctx, tracks[0].ID)
+	if err != nil {
+		t.Fatalf("Failed to get track by ID: %v", err)
+	}
+
+	if found.Title != tracks[0].Title || found.DurationMs == nil || *found.DurationMs != *tracks[0].DurationMs {
+		t.Errorf("Expected track %+v, got %+v", tracks[0], found)
+	}
+	if found.Album == nil || found.Album.ID != album.ID || found.Album.Title != album.Title {
+		t.Fatalf("Expected album %s to be populated, got %+v", album.ID, found.Album)
+	}
+	if found.Album.Artist == nil || found.Album.Artist.ID != artist.ID || found.Album.Artist.Name != artist.Name {
+		t.Errorf("Expected artist %s to be populated, got %+v", artist.ID, found.Album.Artist)
+	}
+
+	_, err = repo.GetByID(ctx, uuid.New())
+	if !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing track, got %v", err)
+	}
+}
+
+func TestTrackRepository_GetByIDs(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewTrackRepository(testDB)
+	ctx := context.Background()
+
+	artist, album, tracks := setupTrackChain(t, ctx, 3)
+
+	missing := uuid.New()
+	found, err := repo.GetByIDs(ctx, []uuid.UUID{tracks[0].ID, tracks[2].ID, missing})
+	if err != nil {
+		t.Fatalf("Failed to get tracks by IDs: %v", err)
+	}
+
+	if len(found) != 2 {
+		t.Fatalf("Expected 2 tracks, got %d", len(found))
+	}
+	if _, ok := found[missing]; ok {
+		t.Error("Expected the missing ID to be left out")
+	}
+	for _, track := range []*models.Track{tracks[0], tracks[2]} {
+		got, ok := found[track.ID]
+		if !ok {
+			t.Fatalf("Expected track %s in results", track.ID)
+		}
+		if got.Album == nil || got.Album.ID != album.ID || got.Album.Artist == nil || got.Album.Artist.ID != artist.ID {
+			t.Errorf("Expected track %s with album and artist populated, got %+v", track.ID, got)
+		}
+	}
+
+	empty, err := repo.GetByIDs(ctx, nil)
+	if err != nil || len(empty) != 0 {
+		t.Errorf("Expected no tracks for no IDs, got %v (err %v)", empty, err)
+	}
+}
@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/daedal00/muse/backend/internal/database"
+	"github.com/daedal00/muse/backend/internal/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// querier is the part of the pgx API the repositories use. It is satisfied by
+// both *pgxpool.Pool and pgx.Tx; Begin on a pgx.Tx opens a savepoint, so
+// repository methods that run their own transaction nest inside an outer one.
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// dbConn is what a repository runs its queries against. Pool takes writes and
+// Reader() read-only queries, mirroring database.PostgresDB; inside a
+// transaction both are the transaction.
+type dbConn struct {
+	Pool querier
+	read querier
+}
+
+func newDBConn(db *database.PostgresDB) *dbConn {
+	return &dbConn{
+		Pool: withStatementTimeout(db.Pool, db.StatementTimeout),
+		read: withStatementTimeout(db.Reader(), db.StatementTimeout),
+	}
+}
+
+// Reader returns the querier for read-only queries
+func (c *dbConn) Reader() querier {
+	if c.read != nil {
+		return c.read
+	}
+	return c.Pool
+}
+
+type txManager struct {
+	db   *database.PostgresDB
+	base repository.Repositories
+}
+
+// NewTxManager returns a TxManager for the given database. Repositories in
+// base that are not backed by PostgreSQL, such as the Redis session store and
+// music cache, are handed to WithTx callbacks unchanged.
+func NewTxManager(db *database.PostgresDB, base repository.Repositories) repository.TxManager {
+	return &txManager{db: db, base: base}
+}
+
+// WithTx runs fn with PostgreSQL repositories bound to a single transaction,
+// committing if fn returns nil and rolling back otherwise. fn's error is
+// returned as is so callers can surface it to clients.
+func (m *txManager) WithTx(ctx context.Context, fn func(repository.Repositories) error) error {
+	return m.db.WithTransaction(ctx, func(tx pgx.Tx) error {
+		repos := m.base
+		repos.User = newUserRepository(tx)
+		repos.UserPreferences = newUserPreferencesRepository(tx)
+		repos.Follow = newFollowRepository(tx)
+		repos.Artist = newArtistRepository(tx)
+		repos.Album = newAlbumRepository(tx)
+		repos.Track = newTrackRepository(tx)
+		repos.Review = newReviewRepository(tx)
+		repos.ReviewVote = newReviewVoteRepository(tx)
+		repos.Playlist = newPlaylistRepository(tx)
+
+		return fn(repos)
+	})
+}
@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/daedal00/muse/backend/internal/repository"
+)
+
+func newTestTxManager() repository.TxManager {
+	return NewTxManager(testDB, repository.Repositories{
+		User:     NewUserRepository(testDB),
+		Playlist: NewPlaylistRepository(testDB),
+	})
+}
+
+func TestTxManager_WithTx_RollsBackOnError(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	ctx := context.Background()
+	user := setupTestUser(t)
+	playlist := setupTestPlaylist(t, user.ID, "Rolled back")
+	injected := errors.New("injected failure")
+
+	err := newTestTxManager().WithTx(ctx, func(repos repository.Repositories) error {
+		if err := repos.User.Create(ctx, user); err != nil {
+			return err
+		}
+		if err := repos.Playlist.Create(ctx, playlist); err != nil {
+			return err
+		}
+
+		// Writes are visible inside the transaction
+		if _, err := repos.Playlist.GetByID(ctx, playlist.ID); err != nil {
+			t.Errorf("Expected playlist to be visible inside the transaction: %v", err)
+		}
+
+		return injected
+	})
+
+	if !errors.Is(err, injected) {
+		t.Fatalf("Expected injected error, got %v", err)
+	}
+
+	// Neither write survives the rollback
+	if _, err := NewUserRepository(testDB).GetByID(ctx, user.ID); err == nil {
+		cleanupTestUser(t, ctx, user.ID)
+		t.Error("Expected user to be rolled back")
+	}
+	if _, err := NewPlaylistRepository(testDB).GetByID(ctx, playlist.ID); err == nil {
+		t.Error("Expected playlist to be rolled back")
+	}
+}
+
+func TestTxManager_WithTx_Commits(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	ctx := context.Background()
+	user := setupTestUser(t)
+	playlist := setupTestPlaylist(t, user.ID, "Committed")
+
+	err := newTestTxManager().WithTx(ctx, func(repos repository.Repositories) error {
+		if err := repos.User.Create(ctx, user); err != nil {
+			return err
+		}
+		return repos.Playlist.Create(ctx, playlist)
+	})
+	if err != nil {
+		t.Fatalf("Failed to run transaction: %v", err)
+	}
+	defer cleanupTestUser(t, ctx, user.ID)
+
+	if _, err := NewPlaylistRepository(testDB).GetByID(ctx, playlist.ID); err != nil {
+		t.Errorf("Expected committed playlist to be found: %v", err)
+	}
+}
@@ -0,0 +1,168 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/daedal00/muse/backend/internal/database"
+	"github.com/daedal00/muse/backend/internal/models"
+	"github.com/daedal00/muse/backend/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type userPreferencesRepository struct {
+	db *dbConn
+}
+
+func NewUserPreferencesRepository(db *database.PostgresDB) repository.UserPreferencesRepository {
+	return &userPreferencesRepository{db: newDBConn(db)}
+}
+
+func newUserPreferencesRepository(q querier) *userPreferencesRepository {
+	return &userPreferencesRepository{db: &dbConn{Pool: q}}
+}
+
+func (r *userPreferencesRepository) Create(ctx context.Context, prefs *models.UserPreferences) error {
+	query := `
+		INSERT INTO user_preferences (user_id, preferred_genres, favorite_artist_ids, notification_settings, privacy_settings, created_at, updated_at)
+		VALUES ($1, COALESCE($2::text[], '{}'), COALESCE($3::text[], '{}'), COALESCE($4::jsonb, '{}'), COALESCE($5::jsonb, '{}'), $6, $7)
+	`
+
+	_, err := r.db.Pool.Exec(ctx, query,
+		prefs.UserID, prefs.PreferredGenres, prefs.FavoriteArtistIDs,
+		prefs.NotificationSettings, prefs.PrivacySettings, prefs.CreatedAt, prefs.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create user preferences: %w", err)
+	}
+
+	return nil
+}
+
+func (r *userPreferencesRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*models.UserPreferences, error) {
+	query := `
+		SELECT user_id, preferred_genres, favorite_artist_ids, notification_settings, privacy_settings, created_at, updated_at
+		FROM user_preferences
+		WHERE user_id = $1
+	`
+
+	prefs := &models.UserPreferences{}
+	err := r.db.Reader().QueryRow(ctx, query, userID).Scan(
+		&prefs.UserID, &prefs.PreferredGenres, &prefs.FavoriteArtistIDs,
+		&prefs.NotificationSettings, &prefs.PrivacySettings, &prefs.CreatedAt, &prefs.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("user preferences %w", repository.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get user preferences: %w", err)
+	}
+
+	return prefs, nil
+}
+
+func (r *userPreferencesRepository) Update(ctx context.Context, prefs *models.UserPreferences) error {
+	query := `
+		UPDATE user_preferences
+		SET preferred_genres = COALESCE($2::text[], '{}'), favorite_artist_ids = COALESCE($3::text[], '{}'),
+			notification_settings = COALESCE($4::jsonb, '{}'), privacy_settings = COALESCE($5::jsonb, '{}'),
+			updated_at = NOW()
+		WHERE user_id = $1
+	`
+
+	result, err := r.db.Pool.Exec(ctx, query,
+		prefs.UserID, prefs.PreferredGenres, prefs.FavoriteArtistIDs,
+		prefs.NotificationSettings, prefs.PrivacySettings,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to update user preferences: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("user preferences %w", repository.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *userPreferencesRepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	query := `DELETE FROM user_preferences WHERE user_id = $1`
+
+	result, err := r.db.Pool.Exec(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete user preferences: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("user preferences %w", repository.ErrNotFound)
+	}
+
+	return nil
+}
+
+// AddFavoriteArtist appends the artist to the user's favourites. Adding an
+// artist that is already a favourite leaves the list unchanged.
+func (r *userPreferencesRepository) AddFavoriteArtist(ctx context.Context, userID uuid.UUID, artistID string) error {
+	query := `
+		UPDATE user_preferences
+		SET favorite_artist_ids = CASE
+				WHEN $2 = ANY(favorite_artist_ids) THEN favorite_artist_ids
+				ELSE array_append(favorite_artist_ids, $2)
+			END,
+			updated_at = NOW()
+		WHERE user_id = $1
+	`
+
+	result, err := r.db.Pool.Exec(ctx, query, userID, artistID)
+	if err != nil {
+		return fmt.Errorf("failed to add favorite artist: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("user preferences %w", repository.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *userPreferencesRepository) RemoveFavoriteArtist(ctx context.Context, userID uuid.UUID, artistID string) error {
+	query := `
+		UPDATE user_preferences
+		SET favorite_artist_ids = array_remove(favorite_artist_ids, $2), updated_at = NOW()
+		WHERE user_id = $1
+	`
+
+	result, err := r.db.Pool.Exec(ctx, query, userID, artistID)
+	if err != nil {
+		return fmt.Errorf("failed to remove favorite artist: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("user preferences %w", repository.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *userPreferencesRepository) UpdatePreferredGenres(ctx context.Context, userID uuid.UUID, genres []string) error {
+	query := `
+		UPDATE user_preferences
+		SET preferred_genres = COALESCE($2::text[], '{}'), updated_at = NOW()
+		WHERE user_id = $1
+	`
+
+	result, err := r.db.Pool.Exec(ctx, query, userID, genres)
+	if err != nil {
+		return fmt.Errorf("failed to update preferred genres: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("user preferences %w", repository.ErrNotFound)
+	}
+
+	return nil
+}
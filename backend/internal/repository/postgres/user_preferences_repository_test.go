@@ -0,0 +1,268 @@
+package postgres
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/daedal00/muse/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+// setupTestUserPreferences creates a user and returns unsaved preferences for
+// them along with a cleanup function
+func setupTestUserPreferences(t testing.TB, ctx context.Context) (*models.UserPreferences, func()) {
+	t.Helper()
+
+	user := setupTestUser(t)
+	if err := NewUserRepository(testDB).Create(ctx, user); err != nil {
+		t.Fatalf("Failed to create test user: %v", err)
+	}
+
+	prefs := &models.UserPreferences{
+		UserID:               user.ID,
+		PreferredGenres:      []string{"jazz", "ambient"},
+		FavoriteArtistIDs:    []string{"spotify_artist_1"},
+		NotificationSettings: map[string]bool{"new_follower": true, "review_likes": false},
+		PrivacySettings:      map[string]bool{"show_reviews": true},
+		CreatedAt:            time.Now(),
+		UpdatedAt:            time.Now(),
+	}
+
+	return prefs, func() { cleanupTestUser(t, ctx, user.ID) }
+}
+
+func TestUserPreferencesRepository_Create(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewUserPreferencesRepository(testDB)
+	ctx := context.Background()
+
+	prefs, cleanup := setupTestUserPreferences(t, ctx)
+	defer cleanup()
+
+	if err := repo.Create(ctx, prefs); err != nil {
+		t.Fatalf("Failed to create user preferences: %v", err)
+	}
+
+	created, err := repo.GetByUserID(ctx, prefs.UserID)
+	if err != nil {
+		t.Fatalf("Failed to get created user preferences: %v", err)
+	}
+
+	if !reflect.DeepEqual(created.PreferredGenres, prefs.PreferredGenres) {
+		t.Errorf("Expected genres %v, got %v", prefs.PreferredGenres, created.PreferredGenres)
+	}
+	if !reflect.DeepEqual(created.FavoriteArtistIDs, prefs.FavoriteArtistIDs) {
+		t.Errorf("Expected favorite artists %v, got %v", prefs.FavoriteArtistIDs, created.FavoriteArtistIDs)
+	}
+	if !reflect.DeepEqual(created.NotificationSettings, prefs.NotificationSettings) {
+		t.Errorf("Expected notification settings %v, got %v", prefs.NotificationSettings, created.NotificationSettings)
+	}
+	if !reflect.DeepEqual(created.PrivacySettings, prefs.PrivacySettings) {
+		t.Errorf("Expected privacy settings %v, got %v", prefs.PrivacySettings, created.PrivacySettings)
+	}
+}
+
+func TestUserPreferencesRepository_Create_Defaults(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewUserPreferencesRepository(testDB)
+	ctx := context.Background()
+
+	prefs, cleanup := setupTestUserPreferences(t, ctx)
+	defer cleanup()
+
+	// Unset lists and maps are stored empty rather than NULL
+	prefs.PreferredGenres = nil
+	prefs.FavoriteArtistIDs = nil
+	prefs.NotificationSettings = nil
+	prefs.PrivacySettings = nil
+	if err := repo.Create(ctx, prefs); err != nil {
+		t.Fatalf("Failed to create user preferences: %v", err)
+	}
+
+	created, err := repo.GetByUserID(ctx, prefs.UserID)
+	if err != nil {
+		t.Fatalf("Failed to get created user preferences: %v", err)
+	}
+
+	if len(created.PreferredGenres) != 0 || len(created.FavoriteArtistIDs) != 0 {
+		t.Errorf("Expected empty lists, got genres %v and artists %v", created.PreferredGenres, created.FavoriteArtistIDs)
+	}
+	if created.NotificationSettings == nil || len(created.NotificationSettings) != 0 {
+		t.Errorf("Expected empty notification settings, got %v", created.NotificationSettings)
+	}
+	if created.PrivacySettings == nil || len(created.PrivacySettings) != 0 {
+		t.Errorf("Expected empty privacy settings, got %v", created.PrivacySettings)
+	}
+}
+
+func TestUserPreferencesRepository_GetByUserID_NotFound(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewUserPreferencesRepository(testDB)
+	ctx := context.Background()
+
+	if _, err := repo.GetByUserID(ctx, uuid.New()); err == nil {
+		t.Error("Expected error when getting non-existent user preferences")
+	}
+}
+
+func TestUserPreferencesRepository_Update(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewUserPreferencesRepository(testDB)
+	ctx := context.Background()
+
+	prefs, cleanup := setupTestUserPreferences(t, ctx)
+	defer cleanup()
+
+	if err := repo.Create(ctx, prefs); err != nil {
+		t.Fatalf("Failed to create user preferences: %v", err)
+	}
+
+	prefs.PreferredGenres = []string{"techno"}
+	prefs.NotificationSettings = map[string]bool{"new_follower": false}
+	if err := repo.Update(ctx, prefs); err != nil {
+		t.Fatalf("Failed to update user preferences: %v", err)
+	}
+
+	updated, err := repo.GetByUserID(ctx, prefs.UserID)
+	if err != nil {
+		t.Fatalf("Failed to get updated user preferences: %v", err)
+	}
+
+	if !reflect.DeepEqual(updated.PreferredGenres, []string{"techno"}) {
+		t.Errorf("Expected genres [techno], got %v", updated.PreferredGenres)
+	}
+	if !reflect.DeepEqual(updated.NotificationSettings, map[string]bool{"new_follower": false}) {
+		t.Errorf("Expected updated notification settings, got %v", updated.NotificationSettings)
+	}
+
+	missing := *prefs
+	missing.UserID = uuid.New()
+	if err := repo.Update(ctx, &missing); err == nil {
+		t.Error("Expected error when updating non-existent user preferences")
+	}
+}
+
+func TestUserPreferencesRepository_Delete(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewUserPreferencesRepository(testDB)
+	ctx := context.Background()
+
+	prefs, cleanup := setupTestUserPreferences(t, ctx)
+	defer cleanup()
+
+	if err := repo.Create(ctx, prefs); err != nil {
+		t.Fatalf("Failed to create user preferences: %v", err)
+	}
+
+	if err := repo.Delete(ctx, prefs.UserID); err != nil {
+		t.Fatalf("Failed to delete user preferences: %v", err)
+	}
+
+	if _, err := repo.GetByUserID(ctx, prefs.UserID); err == nil {
+		t.Error("Expected error when getting deleted user preferences")
+	}
+
+	if err := repo.Delete(ctx, prefs.UserID); err == nil {
+		t.Error("Expected error when deleting already deleted user preferences")
+	}
+}
+
+func TestUserPreferencesRepository_FavoriteArtists(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewUserPreferencesRepository(testDB)
+	ctx := context.Background()
+
+	prefs, cleanup := setupTestUserPreferences(t, ctx)
+	defer cleanup()
+
+	if err := repo.Create(ctx, prefs); err != nil {
+		t.Fatalf("Failed to create user preferences: %v", err)
+	}
+
+	// Adding the same artist twice keeps a single entry
+	for i := 0; i < 2; i++ {
+		if err := repo.AddFavoriteArtist(ctx, prefs.UserID, "spotify_artist_2"); err != nil {
+			t.Fatalf("Failed to add favorite artist: %v", err)
+		}
+	}
+
+	found, err := repo.GetByUserID(ctx, prefs.UserID)
+	if err != nil {
+		t.Fatalf("Failed to get user preferences: %v", err)
+	}
+	if want := []string{"spotify_artist_1", "spotify_artist_2"}; !reflect.DeepEqual(found.FavoriteArtistIDs, want) {
+		t.Errorf("Expected favorite artists %v, got %v", want, found.FavoriteArtistIDs)
+	}
+
+	if err := repo.RemoveFavoriteArtist(ctx, prefs.UserID, "spotify_artist_1"); err != nil {
+		t.Fatalf("Failed to remove favorite artist: %v", err)
+	}
+
+	found, err = repo.GetByUserID(ctx, prefs.UserID)
+	if err != nil {
+		t.Fatalf("Failed to get user preferences: %v", err)
+	}
+	if want := []string{"spotify_artist_2"}; !reflect.DeepEqual(found.FavoriteArtistIDs, want) {
+		t.Errorf("Expected favorite artists %v, got %v", want, found.FavoriteArtistIDs)
+	}
+
+	if err := repo.AddFavoriteArtist(ctx, uuid.New(), "spotify_artist_1"); err == nil {
+		t.Error("Expected error when adding a favorite for a user without preferences")
+	}
+}
+
+func TestUserPreferencesRepository_UpdatePreferredGenres(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewUserPreferencesRepository(testDB)
+	ctx := context.Background()
+
+	prefs, cleanup := setupTestUserPreferences(t, ctx)
+	defer cleanup()
+
+	if err := repo.Create(ctx, prefs); err != nil {
+		t.Fatalf("Failed to create user preferences: %v", err)
+	}
+
+	if err := repo.UpdatePreferredGenres(ctx, prefs.UserID, []string{"rock", "folk"}); err != nil {
+		t.Fatalf("Failed to update preferred genres: %v", err)
+	}
+
+	found, err := repo.GetByUserID(ctx, prefs.UserID)
+	if err != nil {
+		t.Fatalf("Failed to get user preferences: %v", err)
+	}
+	if want := []string{"rock", "folk"}; !reflect.DeepEqual(found.PreferredGenres, want) {
+		t.Errorf("Expected genres %v, got %v", want, found.PreferredGenres)
+	}
+	// Other preferences are untouched
+	if !reflect.DeepEqual(found.FavoriteArtistIDs, prefs.FavoriteArtistIDs) {
+		t.Errorf("Expected favorite artists %v, got %v", prefs.FavoriteArtistIDs, found.FavoriteArtistIDs)
+	}
+
+	if err := repo.UpdatePreferredGenres(ctx, uuid.New(), []string{"rock"}); err == nil {
+		t.Error("Expected error when updating genres for a user without preferences")
+	}
+}
@@ -3,6 +3,8 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/daedal00/muse/backend/internal/database"
 	"github.com/daedal00/muse/backend/internal/models"
@@ -12,25 +14,46 @@ import (
 )
 
 type userRepository struct {
-	db *database.PostgresDB
+	db *dbConn
 }
 
 func NewUserRepository(db *database.PostgresDB) repository.UserRepository {
-	return &userRepository{db: db}
+	return &userRepository{db: newDBConn(db)}
+}
+
+func newUserRepository(q querier) *userRepository {
+	return &userRepository{db: &dbConn{Pool: q}}
+}
+
+// normalizeEmail lowercases an email so lookups and the unique index treat
+// addresses that differ only by case as the same account
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
 }
 
 func (r *userRepository) Create(ctx context.Context, user *models.User) error {
+	user.Email = normalizeEmail(user.Email)
+	if user.Role == "" {
+		user.Role = models.RoleUser
+	}
+
 	query := `
-		INSERT INTO users (id, name, email, password_hash, bio, avatar, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO users (id, name, email, password_hash, bio, avatar, country, role, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`
 
 	_, err := r.db.Pool.Exec(ctx, query,
 		user.ID, user.Name, user.Email, user.PasswordHash,
-		user.Bio, user.Avatar, user.CreatedAt, user.UpdatedAt,
+		user.Bio, user.Avatar, user.Country, user.Role, user.CreatedAt, user.UpdatedAt,
 	)
 
 	if err != nil {
+		if isConstraintViolation(err, userEmailIndex) {
+			return fmt.Errorf("user with this email %w", repository.ErrDuplicate)
+		}
+		if isUniqueViolation(err) {
+			return fmt.Errorf("user %w", repository.ErrDuplicate)
+		}
 		return fmt.Errorf("failed to create user: %w", err)
 	}
 
@@ -39,20 +62,20 @@ func (r *userRepository) Create(ctx context.Context, user *models.User) error {
 
 func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	query := `
-		SELECT id, name, email, password_hash, bio, avatar, created_at, updated_at
+		SELECT id, name, email, password_hash, bio, avatar, country, role, last_active_at, last_login_at, created_at, updated_at
 		FROM users 
 		WHERE id = $1
 	`
 
 	user := &models.User{}
-	err := r.db.Pool.QueryRow(ctx, query, id).Scan(
+	err := r.db.Reader().QueryRow(ctx, query, id).Scan(
 		&user.ID, &user.Name, &user.Email, &user.PasswordHash,
-		&user.Bio, &user.Avatar, &user.CreatedAt, &user.UpdatedAt,
+		&user.Bio, &user.Avatar, &user.Country, &user.Role, &user.LastActiveAt, &user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt,
 	)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("user not found")
+			return nil, fmt.Errorf("user %w", repository.ErrNotFound)
 		}
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
@@ -60,22 +83,63 @@ func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Use
 	return user, nil
 }
 
+// GetByIDs fetches several users in one query, keyed by ID. IDs with no
+// matching user are left out of the map rather than reported as errors.
+func (r *userRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.User, error) {
+	users := make(map[uuid.UUID]*models.User, len(ids))
+	if len(ids) == 0 {
+		return users, nil
+	}
+
+	query := `
+		SELECT id, name, email, password_hash, bio, avatar, country, role, last_active_at, last_login_at, created_at, updated_at
+		FROM users
+		WHERE id = ANY($1)
+	`
+
+	rows, err := r.db.Reader().Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		user := &models.User{}
+		err := rows.Scan(
+			&user.ID, &user.Name, &user.Email, &user.PasswordHash,
+			&user.Bio, &user.Avatar, &user.Country, &user.Role, &user.LastActiveAt, &user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users[user.ID] = user
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating users: %w", err)
+	}
+
+	return users, nil
+}
+
+// GetByEmail reads from the primary because it backs login, which must see
+// an account or password change made moments ago
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	query := `
-		SELECT id, name, email, password_hash, bio, avatar, created_at, updated_at
+		SELECT id, name, email, password_hash, bio, avatar, country, role, last_active_at, last_login_at, created_at, updated_at
 		FROM users 
-		WHERE email = $1
+		WHERE LOWER(email) = $1
 	`
 
 	user := &models.User{}
-	err := r.db.Pool.QueryRow(ctx, query, email).Scan(
+	err := r.db.Pool.QueryRow(ctx, query, normalizeEmail(email)).Scan(
 		&user.ID, &user.Name, &user.Email, &user.PasswordHash,
-		&user.Bio, &user.Avatar, &user.CreatedAt, &user.UpdatedAt,
+		&user.Bio, &user.Avatar, &user.Country, &user.Role, &user.LastActiveAt, &user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt,
 	)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("user not found")
+			return nil, fmt.Errorf("user %w", repository.ErrNotFound)
 		}
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
@@ -84,22 +148,27 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.
 }
 
 func (r *userRepository) Update(ctx context.Context, user *models.User) error {
+	user.Email = normalizeEmail(user.Email)
+
 	query := `
 		UPDATE users 
-		SET name = $2, email = $3, password_hash = $4, bio = $5, avatar = $6, updated_at = NOW()
+		SET name = $2, email = $3, password_hash = $4, bio = $5, avatar = $6, country = $7, updated_at = NOW()
 		WHERE id = $1
 	`
 
 	result, err := r.db.Pool.Exec(ctx, query,
-		user.ID, user.Name, user.Email, user.PasswordHash, user.Bio, user.Avatar,
+		user.ID, user.Name, user.Email, user.PasswordHash, user.Bio, user.Avatar, user.Country,
 	)
 
 	if err != nil {
+		if isConstraintViolation(err, userEmailIndex) {
+			return fmt.Errorf("user with this email %w", repository.ErrDuplicate)
+		}
 		return fmt.Errorf("failed to update user: %w", err)
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("user not found")
+		return fmt.Errorf("user %w", repository.ErrNotFound)
 	}
 
 	return nil
@@ -114,7 +183,61 @@ func (r *userRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("user not found")
+		return fmt.Errorf("user %w", repository.ErrNotFound)
+	}
+
+	return nil
+}
+
+// DeleteAccount removes a user together with everything they own or took
+// part in, all in one transaction. Foreign keys would cascade most of this,
+// but deleting explicitly keeps the search outbox informed about the removed
+// reviews and does not depend on every table's ON DELETE rule.
+func (r *userRepository) DeleteAccount(ctx context.Context, id uuid.UUID) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var locked uuid.UUID
+	if err := tx.QueryRow(ctx, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, id).Scan(&locked); err != nil {
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("user %w", repository.ErrNotFound)
+		}
+		return fmt.Errorf("failed to lock user: %w", err)
+	}
+
+	recordDeletedReviews := `
+		INSERT INTO review_search_events (review_id, event_type)
+		SELECT id, $2 FROM reviews WHERE user_id = $1 AND deleted_at IS NULL
+	`
+	if _, err := tx.Exec(ctx, recordDeletedReviews, id, models.ReviewSearchEventDeleted); err != nil {
+		return fmt.Errorf("failed to record search events: %w", err)
+	}
+
+	steps := []struct {
+		name  string
+		query string
+	}{
+		{"review votes", `DELETE FROM review_votes WHERE user_id = $1 OR review_id IN (SELECT id FROM reviews WHERE user_id = $1)`},
+		{"reviews", `DELETE FROM reviews WHERE user_id = $1`},
+		{"playlist tracks", `DELETE FROM playlist_tracks WHERE playlist_id IN (SELECT id FROM playlists WHERE creator_id = $1)`},
+		{"playlist collaborators", `DELETE FROM playlist_collaborators WHERE user_id = $1 OR playlist_id IN (SELECT id FROM playlists WHERE creator_id = $1)`},
+		{"playlists", `DELETE FROM playlists WHERE creator_id = $1`},
+		{"preferences", `DELETE FROM user_preferences WHERE user_id = $1`},
+		{"follows", `DELETE FROM follows WHERE follower_id = $1 OR followee_id = $1`},
+		{"sessions", `DELETE FROM sessions WHERE user_id = $1`},
+		{"user", `DELETE FROM users WHERE id = $1`},
+	}
+	for _, step := range steps {
+		if _, err := tx.Exec(ctx, step.query, id); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", step.name, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	return nil
@@ -122,13 +245,13 @@ func (r *userRepository) Delete(ctx context.Context, id uuid.UUID) error {
 
 func (r *userRepository) List(ctx context.Context, limit, offset int) ([]*models.User, error) {
 	query := `
-		SELECT id, name, email, password_hash, bio, avatar, created_at, updated_at
+		SELECT id, name, email, password_hash, bio, avatar, country, role, last_active_at, last_login_at, created_at, updated_at
 		FROM users 
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query, limit, offset)
+	rows, err := r.db.Reader().Query(ctx, query, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list users: %w", err)
 	}
@@ -139,7 +262,7 @@ func (r *userRepository) List(ctx context.Context, limit, offset int) ([]*models
 		user := &models.User{}
 		err := rows.Scan(
 			&user.ID, &user.Name, &user.Email, &user.PasswordHash,
-			&user.Bio, &user.Avatar, &user.CreatedAt, &user.UpdatedAt,
+			&user.Bio, &user.Avatar, &user.Country, &user.Role, &user.LastActiveAt, &user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan user: %w", err)
@@ -153,3 +276,180 @@ func (r *userRepository) List(ctx context.Context, limit, offset int) ([]*models
 
 	return users, nil
 }
+
+// minUserSearchLength is the shortest name prefix SearchByName looks up;
+// anything shorter would match a large share of all users
+const minUserSearchLength = 2
+
+// SearchByName returns users whose name starts with query, ignoring case,
+// ordered by name. Password hashes are never selected.
+func (r *userRepository) SearchByName(ctx context.Context, query string, limit, offset int) ([]*models.User, error) {
+	query = strings.TrimSpace(query)
+	if len([]rune(query)) < minUserSearchLength {
+		return []*models.User{}, nil
+	}
+	limit, offset = clampLimitOffset(limit, offset)
+
+	sqlQuery := `
+		SELECT id, name, email, bio, avatar, country, role, last_active_at, last_login_at, created_at, updated_at
+		FROM users
+		WHERE name ILIKE $1 || '%'
+		ORDER BY name, id
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Reader().Query(ctx, sqlQuery, escapeLikePattern(query), limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search users: %w", err)
+	}
+	defer rows.Close()
+
+	users := []*models.User{}
+	for rows.Next() {
+		user := &models.User{}
+		err := rows.Scan(
+			&user.ID, &user.Name, &user.Email,
+			&user.Bio, &user.Avatar, &user.Country, &user.Role, &user.LastActiveAt, &user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating users: %w", err)
+	}
+
+	return users, nil
+}
+
+// TouchLastActive records that the user was just active. Writes are skipped
+// while the stored time is under a minute old so busy users do not cost a
+// write per request.
+func (r *userRepository) TouchLastActive(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET last_active_at = NOW()
+		WHERE id = $1 AND (last_active_at IS NULL OR last_active_at < NOW() - INTERVAL '1 minute')
+	`
+
+	if _, err := r.db.Pool.Exec(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to update last active time: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateLastLogin records that the user just signed in
+func (r *userRepository) UpdateLastLogin(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE users SET last_login_at = NOW() WHERE id = $1`
+
+	result, err := r.db.Pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to update last login time: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("user %w", repository.ErrNotFound)
+	}
+
+	return nil
+}
+
+// GetInactiveUsers returns users who signed up before inactiveSince and have
+// neither been active nor written or edited a review since, longest inactive
+// first
+func (r *userRepository) GetInactiveUsers(ctx context.Context, inactiveSince time.Time, limit, offset int) ([]*models.User, error) {
+	query := `
+		SELECT id, name, email, password_hash, bio, avatar, country, role, last_active_at, last_login_at, created_at, updated_at
+		FROM users u
+		WHERE u.created_at < $1
+			AND (u.last_active_at IS NULL OR u.last_active_at < $1)
+			AND NOT EXISTS (
+				SELECT 1 FROM reviews r
+				WHERE r.user_id = u.id AND r.deleted_at IS NULL
+					AND (r.created_at >= $1 OR r.updated_at >= $1)
+			)
+		ORDER BY COALESCE(u.last_active_at, u.created_at) ASC, u.id
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Reader().Query(ctx, query, inactiveSince, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list inactive users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*models.User
+	for rows.Next() {
+		user := &models.User{}
+		err := rows.Scan(
+			&user.ID, &user.Name, &user.Email, &user.PasswordHash,
+			&user.Bio, &user.Avatar, &user.Country, &user.Role, &user.LastActiveAt, &user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating users: %w", err)
+	}
+
+	return users, nil
+}
+
+// ListConnection returns a page of users, newest first, using keyset
+// pagination on (created_at, id) starting after the given cursor
+func (r *userRepository) ListConnection(ctx context.Context, first int, after *string) (*models.Connection[models.User], error) {
+	first, _ = clampLimitOffset(first, 0)
+
+	afterCreatedAt, afterID, err := keysetArgs(after)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT id, name, email, password_hash, bio, avatar, country, role, last_active_at, last_login_at, created_at, updated_at,
+			(SELECT COUNT(*) FROM users)
+		FROM users
+		WHERE $1::timestamptz IS NULL OR (created_at, id) < ($1::timestamptz, $2::uuid)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3
+	`
+
+	rows, err := r.db.Reader().Query(ctx, query, afterCreatedAt, afterID, first+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []models.User
+	var total int
+	for rows.Next() {
+		var user models.User
+		err := rows.Scan(
+			&user.ID, &user.Name, &user.Email, &user.PasswordHash,
+			&user.Bio, &user.Avatar, &user.Country, &user.Role, &user.LastActiveAt, &user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt, &total,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating users: %w", err)
+	}
+
+	total, err = connectionTotal(ctx, r.db.Reader(), len(users), total, `SELECT COUNT(*) FROM users`)
+	if err != nil {
+		return nil, err
+	}
+
+	return newConnection(users, first, total, afterCreatedAt != nil, func(user models.User) (time.Time, uuid.UUID) {
+		return user.CreatedAt, user.ID
+	}), nil
+}
@@ -2,13 +2,16 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/daedal00/muse/backend/internal/database"
 	"github.com/daedal00/muse/backend/internal/models"
+	"github.com/daedal00/muse/backend/internal/repository"
 	"github.com/google/uuid"
 	"github.com/joho/godotenv"
 )
@@ -73,7 +76,7 @@ func TestMain(m *testing.M) {
 }
 
 // setupTestUser creates a test user for repository tests
-func setupTestUser(t *testing.T) *models.User {
+func setupTestUser(t testing.TB) *models.User {
 	t.Helper()
 
 	return &models.User{
@@ -89,7 +92,7 @@ func setupTestUser(t *testing.T) *models.User {
 }
 
 // cleanupTestUser removes a test user from the database
-func cleanupTestUser(t *testing.T, ctx context.Context, userID uuid.UUID) {
+func cleanupTestUser(t testing.TB, ctx context.Context, userID uuid.UUID) {
 	t.Helper()
 
 	_, err := testDB.Pool.Exec(ctx, "DELETE FROM users WHERE id = $1", userID)
@@ -140,6 +143,9 @@ func TestUserRepository_Create(t *testing.T) {
 	if createdUser.Email != user.Email {
 		t.Errorf("Expected email %s, got %s", user.Email, createdUser.Email)
 	}
+	if createdUser.Role != models.RoleUser {
+		t.Errorf("Expected new users to get role %s, got %s", models.RoleUser, createdUser.Role)
+	}
 }
 
 func TestUserRepository_GetByID_NotFound(t *testing.T) {
@@ -154,7 +160,11 @@ func TestUserRepository_GetByID_NotFound(t *testing.T) {
 	nonExistentID := uuid.New()
 	_, err := repo.GetByID(ctx, nonExistentID)
 	if err == nil {
-		t.Error("Expected error when getting non-existent user")
+		t.Fatal("Expected error when getting non-existent user")
+	}
+
+	if !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
 	}
 }
 
@@ -222,6 +232,250 @@ func TestUserRepository_GetByEmail(t *testing.T) {
 	}
 }
 
+func TestUserRepository_EmailCaseInsensitive(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewUserRepository(testDB)
+	ctx := context.Background()
+
+	user := setupTestUser(t)
+	local := fmt.Sprintf("Mixed-%s", uuid.New().String()[:8])
+	user.Email = local + "@Example.com"
+	defer cleanupTestUser(t, ctx, user.ID)
+
+	if err := repo.Create(ctx, user); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	want := strings.ToLower(local) + "@example.com"
+	if user.Email != want {
+		t.Errorf("Expected email to be normalized to %s, got %s", want, user.Email)
+	}
+
+	// Lookups match regardless of case
+	found, err := repo.GetByEmail(ctx, strings.ToUpper(local)+"@EXAMPLE.COM")
+	if err != nil {
+		t.Fatalf("Failed to get user by mixed-case email: %v", err)
+	}
+	if found.ID != user.ID || found.Email != want {
+		t.Errorf("Expected user %s with email %s, got %s with %s", user.ID, want, found.ID, found.Email)
+	}
+
+	// An address differing only by case is a duplicate on create and update
+	duplicate := setupTestUser(t)
+	duplicate.Email = strings.ToUpper(want)
+	defer cleanupTestUser(t, ctx, duplicate.ID)
+
+	if err := repo.Create(ctx, duplicate); !errors.Is(err, repository.ErrDuplicate) {
+		t.Errorf("Expected ErrDuplicate for a mixed-case duplicate, got %v", err)
+	}
+
+	other := setupTestUser(t)
+	defer cleanupTestUser(t, ctx, other.ID)
+	if err := repo.Create(ctx, other); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	other.Email = strings.ToUpper(want)
+	if err := repo.Update(ctx, other); !errors.Is(err, repository.ErrDuplicate) {
+		t.Errorf("Expected ErrDuplicate when updating to a mixed-case duplicate, got %v", err)
+	}
+}
+
+func TestUserRepository_GetByIDs(t *testing.T) {
+	repo := NewUserRepository(testDB)
+	ctx := context.Background()
+
+	first := setupTestUser(t)
+	second := setupTestUser(t)
+	defer cleanupTestUser(t, ctx, first.ID)
+	defer cleanupTestUser(t, ctx, second.ID)
+
+	for _, user := range []*models.User{first, second} {
+		if err := repo.Create(ctx, user); err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+	}
+
+	missingID := uuid.New()
+	users, err := repo.GetByIDs(ctx, []uuid.UUID{first.ID, second.ID, missingID})
+	if err != nil {
+		t.Fatalf("Failed to get users by IDs: %v", err)
+	}
+
+	if len(users) != 2 {
+		t.Errorf("Expected 2 users, got %d", len(users))
+	}
+
+	if users[first.ID] == nil || users[second.ID] == nil {
+		t.Error("Expected both existing users in the result")
+	}
+
+	if _, ok := users[missingID]; ok {
+		t.Error("Expected missing ID to be absent from the result")
+	}
+}
+
+func TestUserRepository_GetInactiveUsers(t *testing.T) {
+	repo := NewUserRepository(testDB)
+	ctx := context.Background()
+
+	albumID, _, cleanup := setupReviewFixtures(t, ctx, 0)
+	defer cleanup()
+
+	var userIDs []uuid.UUID
+	defer func() {
+		for _, id := range userIDs {
+			cleanupTestUser(t, ctx, id)
+		}
+	}()
+
+	cutoff := time.Now().AddDate(0, 0, -30)
+	longAgo := time.Now().AddDate(0, 0, -60)
+
+	newUser := func(createdAt time.Time, lastActiveAt *time.Time) *models.User {
+		user := setupTestUser(t)
+		user.CreatedAt = createdAt
+		if err := repo.Create(ctx, user); err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+		userIDs = append(userIDs, user.ID)
+
+		if lastActiveAt != nil {
+			_, err := testDB.Pool.Exec(ctx, "UPDATE users SET last_active_at = $2 WHERE id = $1", user.ID, *lastActiveAt)
+			if err != nil {
+				t.Fatalf("Failed to set last active time: %v", err)
+			}
+		}
+		return user
+	}
+
+	lapsed := time.Now().AddDate(0, 0, -40)
+	dormant := newUser(longAgo, &lapsed)
+	neverActive := newUser(longAgo, nil)
+	recentlyActive := newUser(longAgo, &lapsed)
+	recentReviewer := newUser(longAgo, &lapsed)
+	deletedReviewer := newUser(longAgo, &lapsed)
+	newSignup := newUser(time.Now(), nil)
+
+	if err := repo.TouchLastActive(ctx, recentlyActive.ID); err != nil {
+		t.Fatalf("Failed to touch last active time: %v", err)
+	}
+
+	review := setupTestReview(t, recentReviewer.ID, albumID, 4, time.Now())
+	if err := NewReviewRepository(testDB).Create(ctx, review); err != nil {
+		t.Fatalf("Failed to create review: %v", err)
+	}
+
+	// A review that was since deleted no longer counts as activity
+	deletedReview := setupTestReview(t, deletedReviewer.ID, albumID, 2, time.Now())
+	if err := NewReviewRepository(testDB).Create(ctx, deletedReview); err != nil {
+		t.Fatalf("Failed to create review: %v", err)
+	}
+	if err := NewReviewRepository(testDB).Delete(ctx, deletedReview.ID); err != nil {
+		t.Fatalf("Failed to delete review: %v", err)
+	}
+
+	users, err := repo.GetInactiveUsers(ctx, cutoff, 1000, 0)
+	if err != nil {
+		t.Fatalf("Failed to get inactive users: %v", err)
+	}
+
+	found := make(map[uuid.UUID]bool)
+	for _, user := range users {
+		found[user.ID] = true
+	}
+
+	for _, user := range []*models.User{dormant, neverActive, deletedReviewer} {
+		if !found[user.ID] {
+			t.Errorf("Expected user %s to be inactive", user.ID)
+		}
+	}
+	for _, user := range []*models.User{recentlyActive, recentReviewer, newSignup} {
+		if found[user.ID] {
+			t.Errorf("Expected user %s not to be inactive", user.ID)
+		}
+	}
+
+	// Touching again within a minute leaves the stored time alone
+	touched, err := repo.GetByID(ctx, recentlyActive.ID)
+	if err != nil {
+		t.Fatalf("Failed to get user: %v", err)
+	}
+	if touched.LastActiveAt == nil || touched.LastActiveAt.Before(cutoff) {
+		t.Fatal("Expected last active time to be set")
+	}
+	if err := repo.TouchLastActive(ctx, recentlyActive.ID); err != nil {
+		t.Fatalf("Failed to touch last active time: %v", err)
+	}
+	retouched, err := repo.GetByID(ctx, recentlyActive.ID)
+	if err != nil {
+		t.Fatalf("Failed to get user: %v", err)
+	}
+	if !retouched.LastActiveAt.Equal(*touched.LastActiveAt) {
+		t.Error("Expected repeated touch within a minute to be skipped")
+	}
+}
+
+func TestUserRepository_UpdateLastLogin(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewUserRepository(testDB)
+	ctx := context.Background()
+
+	user := setupTestUser(t)
+	defer cleanupTestUser(t, ctx, user.ID)
+
+	if err := repo.Create(ctx, user); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	created, err := repo.GetByID(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("Failed to get user: %v", err)
+	}
+	if created.LastLoginAt != nil {
+		t.Errorf("Expected no last login for a new user, got %v", created.LastLoginAt)
+	}
+
+	before := time.Now().Add(-time.Second)
+	if err := repo.UpdateLastLogin(ctx, user.ID); err != nil {
+		t.Fatalf("Failed to update last login: %v", err)
+	}
+
+	first, err := repo.GetByID(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("Failed to get user: %v", err)
+	}
+	if first.LastLoginAt == nil || first.LastLoginAt.Before(before) {
+		t.Fatalf("Expected last login to be set after %v, got %v", before, first.LastLoginAt)
+	}
+
+	// Every login moves the timestamp forward, and it is returned by List too
+	time.Sleep(10 * time.Millisecond)
+	if err := repo.UpdateLastLogin(ctx, user.ID); err != nil {
+		t.Fatalf("Failed to update last login: %v", err)
+	}
+
+	users, err := repo.List(ctx, 100, 0)
+	if err != nil {
+		t.Fatalf("Failed to list users: %v", err)
+	}
+	for _, listed := range users {
+		if listed.ID == user.ID && (listed.LastLoginAt == nil || !listed.LastLoginAt.After(*first.LastLoginAt)) {
+			t.Errorf("Expected listed last login after %v, got %v", *first.LastLoginAt, listed.LastLoginAt)
+		}
+	}
+
+	if err := repo.UpdateLastLogin(ctx, uuid.New()); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for an unknown user, got %v", err)
+	}
+}
+
 func TestUserRepository_Update(t *testing.T) {
 	repo := NewUserRepository(testDB)
 	ctx := context.Background()
@@ -296,6 +550,134 @@ func TestUserRepository_Delete(t *testing.T) {
 	}
 }
 
+func TestUserRepository_DeleteAccount(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewUserRepository(testDB)
+	reviewRepo := NewReviewRepository(testDB)
+	voteRepo := NewReviewVoteRepository(testDB)
+	playlistRepo := NewPlaylistRepository(testDB)
+	followRepo := NewFollowRepository(testDB)
+	ctx := context.Background()
+
+	// The account being deleted owns a playlist with tracks; another user
+	// shares an album to review
+	playlistID, _, cleanupPlaylist := setupPlaylistTrackFixtures(t, ctx, 2)
+	defer cleanupPlaylist()
+	albumID, otherIDs, cleanupReviews := setupReviewFixtures(t, ctx, 1)
+	defer cleanupReviews()
+
+	playlist, err := playlistRepo.GetByID(ctx, playlistID)
+	if err != nil {
+		t.Fatalf("Failed to get playlist: %v", err)
+	}
+	userID, otherID := playlist.CreatorID, otherIDs[0]
+
+	review := setupTestReview(t, userID, albumID, 4, time.Now())
+	otherReview := setupTestReview(t, otherID, albumID, 2, time.Now())
+	for _, r := range []*models.Review{review, otherReview} {
+		if err := reviewRepo.Create(ctx, r); err != nil {
+			t.Fatalf("Failed to create review: %v", err)
+		}
+	}
+	if err := voteRepo.Vote(ctx, review.ID, otherID); err != nil {
+		t.Fatalf("Failed to vote: %v", err)
+	}
+	if err := voteRepo.Vote(ctx, otherReview.ID, userID); err != nil {
+		t.Fatalf("Failed to vote: %v", err)
+	}
+
+	if err := playlistRepo.AddCollaborator(ctx, playlistID, otherID); err != nil {
+		t.Fatalf("Failed to add collaborator: %v", err)
+	}
+	shared := setupTestPlaylist(t, otherID, "Shared")
+	if err := playlistRepo.Create(ctx, shared); err != nil {
+		t.Fatalf("Failed to create playlist: %v", err)
+	}
+	if err := playlistRepo.AddCollaborator(ctx, shared.ID, userID); err != nil {
+		t.Fatalf("Failed to add collaborator: %v", err)
+	}
+
+	prefs := &models.UserPreferences{UserID: userID, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := NewUserPreferencesRepository(testDB).Create(ctx, prefs); err != nil {
+		t.Fatalf("Failed to create preferences: %v", err)
+	}
+	if err := followRepo.Follow(ctx, userID, otherID); err != nil {
+		t.Fatalf("Failed to follow: %v", err)
+	}
+	if err := followRepo.Follow(ctx, otherID, userID); err != nil {
+		t.Fatalf("Failed to follow: %v", err)
+	}
+	session := &models.Session{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		ExpiresAt: time.Now().Add(time.Hour),
+		CreatedAt: time.Now(),
+	}
+	if err := NewSessionRepository(testDB).Create(ctx, session); err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	if err := repo.DeleteAccount(ctx, userID); err != nil {
+		t.Fatalf("Failed to delete account: %v", err)
+	}
+
+	remaining := map[string]string{
+		"user":                   "SELECT COUNT(*) FROM users WHERE id = $1",
+		"reviews":                "SELECT COUNT(*) FROM reviews WHERE user_id = $1",
+		"review votes":           "SELECT COUNT(*) FROM review_votes WHERE user_id = $1",
+		"playlists":              "SELECT COUNT(*) FROM playlists WHERE creator_id = $1",
+		"playlist collaborators": "SELECT COUNT(*) FROM playlist_collaborators WHERE user_id = $1",
+		"preferences":            "SELECT COUNT(*) FROM user_preferences WHERE user_id = $1",
+		"follows":                "SELECT COUNT(*) FROM follows WHERE follower_id = $1 OR followee_id = $1",
+		"sessions":               "SELECT COUNT(*) FROM sessions WHERE user_id = $1",
+	}
+	for name, query := range remaining {
+		var count int
+		if err := testDB.Pool.QueryRow(ctx, query, userID).Scan(&count); err != nil {
+			t.Fatalf("Failed to count %s: %v", name, err)
+		}
+		if count != 0 {
+			t.Errorf("Expected no %s left, got %d", name, count)
+		}
+	}
+
+	var tracks int
+	if err := testDB.Pool.QueryRow(ctx, "SELECT COUNT(*) FROM playlist_tracks WHERE playlist_id = $1", playlistID).Scan(&tracks); err != nil {
+		t.Fatalf("Failed to count playlist tracks: %v", err)
+	}
+	if tracks != 0 {
+		t.Errorf("Expected no playlist tracks left, got %d", tracks)
+	}
+
+	// The deleted review is announced to the search indexer
+	var events int
+	err = testDB.Pool.QueryRow(ctx,
+		"SELECT COUNT(*) FROM review_search_events WHERE review_id = $1 AND event_type = $2",
+		review.ID, models.ReviewSearchEventDeleted,
+	).Scan(&events)
+	if err != nil {
+		t.Fatalf("Failed to count search events: %v", err)
+	}
+	if events != 1 {
+		t.Errorf("Expected a deleted search event for the review, got %d", events)
+	}
+
+	// Other users' reviews and playlists survive
+	if _, err := reviewRepo.GetByID(ctx, otherReview.ID); err != nil {
+		t.Errorf("Expected the other user's review to remain: %v", err)
+	}
+	if _, err := playlistRepo.GetByID(ctx, shared.ID); err != nil {
+		t.Errorf("Expected the other user's playlist to remain: %v", err)
+	}
+
+	if err := repo.DeleteAccount(ctx, userID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound deleting a missing account, got %v", err)
+	}
+}
+
 func TestUserRepository_List(t *testing.T) {
 	repo := NewUserRepository(testDB)
 	ctx := context.Background()
@@ -325,6 +707,100 @@ func TestUserRepository_List(t *testing.T) {
 	}
 }
 
+func TestUserRepository_SearchByName(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewUserRepository(testDB)
+	ctx := context.Background()
+
+	marker := uuid.New().String()[:8]
+	names := []string{"Zed" + marker, "alice" + marker, "Bob" + marker}
+	for _, name := range names {
+		user := setupTestUser(t)
+		user.Name = name
+		defer cleanupTestUser(t, ctx, user.ID)
+
+		if err := repo.Create(ctx, user); err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+	}
+
+	other := setupTestUser(t)
+	other.Name = "Not" + marker
+	defer cleanupTestUser(t, ctx, other.ID)
+	if err := repo.Create(ctx, other); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	// Prefix match ignores case; a marker in the middle of a name does not match
+	results, err := repo.SearchByName(ctx, "ALICE"+marker, 10, 0)
+	if err != nil {
+		t.Fatalf("Failed to search users: %v", err)
+	}
+	if len(results) != 1 || results[0].Name != names[1] {
+		t.Fatalf("Expected only %s, got %d results", names[1], len(results))
+	}
+	if results[0].PasswordHash != "" {
+		t.Error("Expected password hash not to be populated")
+	}
+
+	results, err = repo.SearchByName(ctx, marker, 10, 0)
+	if err != nil {
+		t.Fatalf("Failed to search users: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("Expected no users for a non-prefix match, got %d", len(results))
+	}
+
+	for _, query := range []string{"", " ", "a"} {
+		results, err := repo.SearchByName(ctx, query, 10, 0)
+		if err != nil {
+			t.Fatalf("Failed to search users for %q: %v", query, err)
+		}
+		if results == nil || len(results) != 0 {
+			t.Errorf("Expected empty slice for query %q, got %d results", query, len(results))
+		}
+	}
+}
+
+func TestUserRepository_SearchByName_OrderedByName(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	repo := NewUserRepository(testDB)
+	ctx := context.Background()
+
+	prefix := "Search" + uuid.New().String()[:8]
+	for _, suffix := range []string{"c", "a", "b"} {
+		user := setupTestUser(t)
+		user.Name = prefix + suffix
+		defer cleanupTestUser(t, ctx, user.ID)
+
+		if err := repo.Create(ctx, user); err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+	}
+
+	results, err := repo.SearchByName(ctx, prefix, 10, 0)
+	if err != nil {
+		t.Fatalf("Failed to search users: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 users, got %d", len(results))
+	}
+	for i, suffix := range []string{"a", "b", "c"} {
+		if results[i].Name != prefix+suffix {
+			t.Errorf("Expected result %d to be %s, got %s", i, prefix+suffix, results[i].Name)
+		}
+		if results[i].PasswordHash != "" {
+			t.Errorf("Expected password hash not to be populated on %s", results[i].Name)
+		}
+	}
+}
+
 // Benchmark tests for performance monitoring
 func BenchmarkUserRepository_Create(b *testing.B) {
 	if testDB == nil {
@@ -0,0 +1,56 @@
+package redis
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+)
+
+// compressThreshold is the smallest payload worth compressing; below it the
+// gzip header costs more than it saves
+const compressThreshold = 1024
+
+// gzipMagic starts every gzip stream. JSON never begins with these bytes, so
+// they tell compressed values apart from raw JSON written before compression
+// was added.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// compressValue gzips payloads of at least compressThreshold bytes and
+// returns smaller ones unchanged
+func compressValue(data []byte) ([]byte, error) {
+	if len(data) < compressThreshold {
+		return data, nil
+	}
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to compress cache value: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress cache value: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// decompressValue reverses compressValue, passing uncompressed values through
+func decompressValue(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, gzipMagic) {
+		return data, nil
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress cache value: %w", err)
+	}
+	defer r.Close()
+
+	decompressed, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress cache value: %w", err)
+	}
+
+	return decompressed, nil
+}
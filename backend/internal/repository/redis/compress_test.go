@@ -0,0 +1,44 @@
+package redis
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressValue_RoundTrip(t *testing.T) {
+	large := bytes.Repeat([]byte(`{"id":"track","name":"Repeated"},`), 200)
+
+	compressed, err := compressValue(large)
+	require.NoError(t, err)
+	assert.Less(t, len(compressed), len(large))
+	assert.True(t, bytes.HasPrefix(compressed, gzipMagic))
+
+	decompressed, err := decompressValue(compressed)
+	require.NoError(t, err)
+	assert.Equal(t, large, decompressed)
+}
+
+func TestCompressValue_SmallValuesUnchanged(t *testing.T) {
+	small := []byte(`{"id":"track"}`)
+
+	compressed, err := compressValue(small)
+	require.NoError(t, err)
+	assert.Equal(t, small, compressed)
+}
+
+func TestDecompressValue_RawJSON(t *testing.T) {
+	// Values cached before compression was added are plain JSON
+	raw := bytes.Repeat([]byte(`{"id":"track"}`), 100)
+
+	decompressed, err := decompressValue(raw)
+	require.NoError(t, err)
+	assert.Equal(t, raw, decompressed)
+}
+
+func TestDecompressValue_Corrupt(t *testing.T) {
+	_, err := decompressValue(append(append([]byte{}, gzipMagic...), "not gzip"...))
+	assert.Error(t, err)
+}
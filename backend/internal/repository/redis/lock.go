@@ -0,0 +1,80 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/daedal00/muse/backend/internal/database"
+	"github.com/daedal00/muse/backend/internal/logging"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// lockReleaseTimeout bounds the unlock round trip, which runs even when the
+// caller's context has been cancelled
+const lockReleaseTimeout = 5 * time.Second
+
+// releaseScript deletes a lock only if it still holds the caller's token, so
+// a holder whose lock expired cannot release someone else's
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// RedisLock hands out short-lived locks shared by every server instance.
+// Wrap per-user operations that must not overlap, such as syncing a user's
+// Spotify playlists or refreshing their cached music data, keyed by the
+// operation and user:
+//
+//	release, ok, err := locks.Acquire(ctx, "refresh_user_data:"+userID.String(), time.Minute)
+//	if err != nil {
+//		return err
+//	}
+//	if !ok {
+//		return nil // another instance is already refreshing this user
+//	}
+//	defer release()
+//
+// The ttl should comfortably exceed the operation, since the lock is not
+// extended while it runs and another caller may take it once it expires.
+type RedisLock struct {
+	client *database.RedisClient
+}
+
+func NewRedisLock(client *database.RedisClient) *RedisLock {
+	return &RedisLock{client: client}
+}
+
+// Acquire takes the lock named key for up to ttl. ok is false if someone else
+// holds it. When ok is true, release frees the lock; calling it more than
+// once, or after the lock expired, is harmless.
+func (l *RedisLock) Acquire(ctx context.Context, key string, ttl time.Duration) (func(), bool, error) {
+	lockKey := fmt.Sprintf("lock:%s", key)
+	token := uuid.NewString()
+
+	ok, err := l.client.Client.SetNX(ctx, lockKey, token, ttl).Result()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	if !ok {
+		return nil, false, nil
+	}
+
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), lockReleaseTimeout)
+			defer cancel()
+
+			if err := releaseScript.Run(releaseCtx, l.client.Client, []string{lockKey}, token).Err(); err != nil {
+				logging.FromContext(ctx).Warn("failed to release lock", "key", key, "error", err)
+			}
+		})
+	}
+
+	return release, true, nil
+}
@@ -0,0 +1,76 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisLock_AcquireContendRelease(t *testing.T) {
+	if testRedis == nil {
+		t.Skip("Redis not available")
+	}
+
+	locks := NewRedisLock(testRedis)
+	ctx := context.Background()
+
+	// Clean up before test
+	testRedis.Client.FlushDB(ctx)
+
+	release, ok, err := locks.Acquire(ctx, "refresh_user_data:user-1", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// A second caller is turned away while the lock is held
+	_, ok, err = locks.Acquire(ctx, "refresh_user_data:user-1", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// Other keys are independent
+	releaseOther, ok, err := locks.Acquire(ctx, "refresh_user_data:user-2", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+	defer releaseOther()
+
+	release()
+	release() // releasing twice is harmless
+
+	release, ok, err = locks.Acquire(ctx, "refresh_user_data:user-1", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+	release()
+}
+
+func TestRedisLock_ExpiresAfterTTL(t *testing.T) {
+	if testRedis == nil {
+		t.Skip("Redis not available")
+	}
+
+	locks := NewRedisLock(testRedis)
+	ctx := context.Background()
+
+	// Clean up before test
+	testRedis.Client.FlushDB(ctx)
+
+	staleRelease, ok, err := locks.Acquire(ctx, "sync_spotify_playlist:user-1", 200*time.Millisecond)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// The lock frees itself once its TTL runs out
+	time.Sleep(400 * time.Millisecond)
+
+	release, ok, err := locks.Acquire(ctx, "sync_spotify_playlist:user-1", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+	defer release()
+
+	// The expired holder cannot release the new holder's lock
+	staleRelease()
+
+	_, ok, err = locks.Acquire(ctx, "sync_spotify_playlist:user-1", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/daedal00/muse/backend/internal/database"
 	"github.com/daedal00/muse/backend/internal/models"
+	"github.com/daedal00/muse/backend/internal/repository"
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
@@ -17,9 +19,11 @@ type MusicCacheRepository struct {
 	client *database.RedisClient
 }
 
-// MusicData represents cached music data for a user
+var _ repository.MusicCacheRepository = (*MusicCacheRepository)(nil)
+
+// MusicData represents cached music data for a user. Recently played tracks
+// are kept in their own list; see AddToRecentlyPlayed.
 type MusicData struct {
-	RecentlyPlayed []*models.Track `json:"recently_played"`
 	FavoriteAlbums []*models.Album `json:"favorite_albums"`
 	LastUpdated    time.Time       `json:"last_updated"`
 }
@@ -47,15 +51,23 @@ const (
 	SearchCacheTTL      = 30 * time.Minute // Search results cache for 30 minutes
 	HistoryCacheTTL     = 24 * time.Hour   // Listening history cache for 24 hours
 	PopularDataCacheTTL = 6 * time.Hour    // Popular content cache for 6 hours
+	SpotifyItemCacheTTL = 24 * time.Hour   // Spotify track/album/artist metadata cache for 24 hours
+	SpotifyNotFoundTTL  = 5 * time.Minute  // Spotify IDs known not to exist are remembered for 5 minutes
+	RecommendationsTTL  = 6 * time.Hour    // Generated recommendations are reused for 6 hours
+	PlaylistStatsTTL    = 1 * time.Hour    // Playlist audio feature stats cache for 1 hour
+	RecentlyPlayedTTL   = 24 * time.Hour   // Recently played tracks are kept for 24 hours after the last play
 )
 
+// MaxRecentlyPlayed is how many tracks a user's recently played list keeps
+const MaxRecentlyPlayed = 50
+
 func NewMusicCacheRepository(client *database.RedisClient) *MusicCacheRepository {
 	return &MusicCacheRepository{client: client}
 }
 
 // ============ User Music Data Caching ============
 
-// SetUserMusicData caches a user's music data (recently played, favorites, etc.)
+// SetUserMusicData caches a user's music data (favorites, etc.)
 func (r *MusicCacheRepository) SetUserMusicData(ctx context.Context, userID uuid.UUID, data interface{}) error {
 	key := fmt.Sprintf("user_music:%s", userID.String())
 
@@ -73,14 +85,19 @@ func (r *MusicCacheRepository) SetUserMusicData(ctx context.Context, userID uuid
 		return fmt.Errorf("failed to marshal user music data: %w", err)
 	}
 
-	return r.client.Client.Set(ctx, key, jsonData, MusicDataCacheTTL).Err()
+	value, err := compressValue(jsonData)
+	if err != nil {
+		return err
+	}
+
+	return r.client.Client.Set(ctx, key, value, MusicDataCacheTTL).Err()
 }
 
 // GetUserMusicData retrieves cached user music data
 func (r *MusicCacheRepository) GetUserMusicData(ctx context.Context, userID uuid.UUID) (interface{}, error) {
 	key := fmt.Sprintf("user_music:%s", userID.String())
 
-	data, err := r.client.Client.Get(ctx, key).Result()
+	stored, err := r.client.Client.Get(ctx, key).Bytes()
 	if err != nil {
 		if err == redis.Nil {
 			return nil, nil // Cache miss
@@ -88,54 +105,79 @@ func (r *MusicCacheRepository) GetUserMusicData(ctx context.Context, userID uuid
 		return nil, fmt.Errorf("failed to get user music data: %w", err)
 	}
 
+	data, err := decompressValue(stored)
+	if err != nil {
+		return nil, err
+	}
+
 	var musicData MusicData
-	if err := json.Unmarshal([]byte(data), &musicData); err != nil {
+	if err := json.Unmarshal(data, &musicData); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal user music data: %w", err)
 	}
 
 	return &musicData, nil
 }
 
-// AddToRecentlyPlayed adds a track to user's recently played list
+// recentlyPlayedKey holds a user's recently played tracks as a list of JSON
+// tracks, most recent first
+func recentlyPlayedKey(userID uuid.UUID) string {
+	return fmt.Sprintf("recently_played:%s", userID.String())
+}
+
+// AddToRecentlyPlayed pushes a track onto the front of the user's recently
+// played list, keeping only the latest MaxRecentlyPlayed. Concurrent calls
+// never lose each other's tracks since nothing is read back and rewritten.
 func (r *MusicCacheRepository) AddToRecentlyPlayed(ctx context.Context, userID uuid.UUID, track *models.Track) error {
-	// Get existing data
-	data, err := r.GetUserMusicData(ctx, userID)
+	data, err := json.Marshal(track)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to marshal track: %w", err)
 	}
 
-	var musicData *MusicData
-	if data == nil {
-		musicData = &MusicData{
-			RecentlyPlayed: []*models.Track{},
-			FavoriteAlbums: []*models.Album{},
-		}
-	} else {
-		// Cast the interface{} to *MusicData
-		var ok bool
-		musicData, ok = data.(*MusicData)
-		if !ok {
-			return fmt.Errorf("invalid data type in cache")
-		}
+	key := recentlyPlayedKey(userID)
+	pipe := r.client.Client.TxPipeline()
+	pipe.LPush(ctx, key, data)
+	pipe.LTrim(ctx, key, 0, MaxRecentlyPlayed-1)
+	pipe.Expire(ctx, key, RecentlyPlayedTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to add to recently played: %w", err)
 	}
 
-	// Add track to beginning of recently played (most recent first)
-	musicData.RecentlyPlayed = append([]*models.Track{track}, musicData.RecentlyPlayed...)
+	return nil
+}
+
+// GetRecentlyPlayed returns up to limit of the user's most recently played
+// tracks, newest first. A non-positive limit returns the whole list.
+func (r *MusicCacheRepository) GetRecentlyPlayed(ctx context.Context, userID uuid.UUID, limit int) ([]*models.Track, error) {
+	if limit <= 0 || limit > MaxRecentlyPlayed {
+		limit = MaxRecentlyPlayed
+	}
+
+	values, err := r.client.Client.LRange(ctx, recentlyPlayedKey(userID), 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recently played: %w", err)
+	}
 
-	// Keep only last 50 tracks
-	if len(musicData.RecentlyPlayed) > 50 {
-		musicData.RecentlyPlayed = musicData.RecentlyPlayed[:50]
+	tracks := make([]*models.Track, 0, len(values))
+	for _, value := range values {
+		var track models.Track
+		if err := json.Unmarshal([]byte(value), &track); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal recently played track: %w", err)
+		}
+		tracks = append(tracks, &track)
 	}
 
-	return r.SetUserMusicData(ctx, userID, musicData)
+	return tracks, nil
 }
 
 // ============ Search Results Caching ============
 
-// SetSearchResults caches search results for faster retrieval
-func (r *MusicCacheRepository) SetSearchResults(ctx context.Context, query string, resultType string, results interface{}) error {
-	key := fmt.Sprintf("search:%s:%s", resultType, query)
+// searchKey includes the page so each page of a query is cached separately
+func searchKey(resultType, query string, limit, offset int) string {
+	return fmt.Sprintf("search:%s:%s:%d:%d", resultType, query, limit, offset)
+}
 
+// SetSearchResults caches search results for faster retrieval
+func (r *MusicCacheRepository) SetSearchResults(ctx context.Context, query string, resultType string, limit, offset int, results interface{}) error {
 	cacheData := SearchCacheData{
 		Query:      query,
 		Results:    results,
@@ -148,27 +190,79 @@ func (r *MusicCacheRepository) SetSearchResults(ctx context.Context, query strin
 		return fmt.Errorf("failed to marshal search results: %w", err)
 	}
 
-	return r.client.Client.Set(ctx, key, jsonData, SearchCacheTTL).Err()
+	return r.client.Client.Set(ctx, searchKey(resultType, query, limit, offset), jsonData, SearchCacheTTL).Err()
 }
 
 // GetSearchResults retrieves cached search results
-func (r *MusicCacheRepository) GetSearchResults(ctx context.Context, query string, resultType string) (interface{}, error) {
-	key := fmt.Sprintf("search:%s:%s", resultType, query)
+func (r *MusicCacheRepository) GetSearchResults(ctx context.Context, query string, resultType string, limit, offset int) (interface{}, error) {
+	var searchData SearchCacheData
+	found, err := r.getSearchData(ctx, searchKey(resultType, query, limit, offset), &searchData)
+	if err != nil || !found {
+		return nil, err
+	}
 
+	return &searchData, nil
+}
+
+// getSearchData decodes the cached search entry at key into dest, reporting
+// false on a cache miss
+func (r *MusicCacheRepository) getSearchData(ctx context.Context, key string, dest interface{}) (bool, error) {
 	data, err := r.client.Client.Get(ctx, key).Result()
 	if err != nil {
 		if err == redis.Nil {
-			return nil, nil // Cache miss
+			return false, nil // Cache miss
 		}
-		return nil, fmt.Errorf("failed to get search results: %w", err)
+		return false, fmt.Errorf("failed to get search results: %w", err)
 	}
 
-	var searchData SearchCacheData
-	if err := json.Unmarshal([]byte(data), &searchData); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal search results: %w", err)
+	if err := json.Unmarshal([]byte(data), dest); err != nil {
+		return false, fmt.Errorf("failed to unmarshal search results: %w", err)
 	}
 
-	return &searchData, nil
+	return true, nil
+}
+
+// getTypedSearchResults reads search results cached by SetSearchResults back
+// into a concrete slice; a miss returns nil
+func getTypedSearchResults[T any](ctx context.Context, r *MusicCacheRepository, query, resultType string, limit, offset int) ([]T, error) {
+	var searchData struct {
+		Results []T `json:"results"`
+	}
+	if _, err := r.getSearchData(ctx, searchKey(resultType, query, limit, offset), &searchData); err != nil {
+		return nil, err
+	}
+
+	return searchData.Results, nil
+}
+
+// SetAlbumSearchResults caches one page of the albums found for a query
+func (r *MusicCacheRepository) SetAlbumSearchResults(ctx context.Context, query string, limit, offset int, results []models.SpotifyAlbum) error {
+	return r.SetSearchResults(ctx, query, "albums", limit, offset, results)
+}
+
+// GetAlbumSearchResults returns a cached page of albums for a query, or nil on a miss
+func (r *MusicCacheRepository) GetAlbumSearchResults(ctx context.Context, query string, limit, offset int) ([]models.SpotifyAlbum, error) {
+	return getTypedSearchResults[models.SpotifyAlbum](ctx, r, query, "albums", limit, offset)
+}
+
+// SetArtistSearchResults caches one page of the artists found for a query
+func (r *MusicCacheRepository) SetArtistSearchResults(ctx context.Context, query string, limit, offset int, results []models.SpotifyArtist) error {
+	return r.SetSearchResults(ctx, query, "artists", limit, offset, results)
+}
+
+// GetArtistSearchResults returns a cached page of artists for a query, or nil on a miss
+func (r *MusicCacheRepository) GetArtistSearchResults(ctx context.Context, query string, limit, offset int) ([]models.SpotifyArtist, error) {
+	return getTypedSearchResults[models.SpotifyArtist](ctx, r, query, "artists", limit, offset)
+}
+
+// SetTrackSearchResults caches one page of the tracks found for a query
+func (r *MusicCacheRepository) SetTrackSearchResults(ctx context.Context, query string, limit, offset int, results []models.SpotifyTrack) error {
+	return r.SetSearchResults(ctx, query, "tracks", limit, offset, results)
+}
+
+// GetTrackSearchResults returns a cached page of tracks for a query, or nil on a miss
+func (r *MusicCacheRepository) GetTrackSearchResults(ctx context.Context, query string, limit, offset int) ([]models.SpotifyTrack, error) {
+	return getTypedSearchResults[models.SpotifyTrack](ctx, r, query, "tracks", limit, offset)
 }
 
 // ============ Listening History ============
@@ -213,6 +307,92 @@ func (r *MusicCacheRepository) GetListeningHistory(ctx context.Context, userID u
 	return &history, nil
 }
 
+// ============ Recommendations Caching ============
+
+// SetRecommendations caches the recommendations generated for their user
+func (r *MusicCacheRepository) SetRecommendations(ctx context.Context, recommendations *models.CachedRecommendations) error {
+	key := fmt.Sprintf("recommendations:%s", recommendations.UserID.String())
+
+	jsonData, err := json.Marshal(recommendations)
+	if err != nil {
+		return fmt.Errorf("failed to marshal recommendations: %w", err)
+	}
+
+	value, err := compressValue(jsonData)
+	if err != nil {
+		return err
+	}
+
+	return r.client.Client.Set(ctx, key, value, RecommendationsTTL).Err()
+}
+
+// GetRecommendations retrieves a user's cached recommendations
+func (r *MusicCacheRepository) GetRecommendations(ctx context.Context, userID uuid.UUID) (*models.CachedRecommendations, error) {
+	key := fmt.Sprintf("recommendations:%s", userID.String())
+
+	stored, err := r.client.Client.Get(ctx, key).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil // Cache miss
+		}
+		return nil, fmt.Errorf("failed to get recommendations: %w", err)
+	}
+
+	data, err := decompressValue(stored)
+	if err != nil {
+		return nil, err
+	}
+
+	var recommendations models.CachedRecommendations
+	if err := json.Unmarshal(data, &recommendations); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal recommendations: %w", err)
+	}
+
+	return &recommendations, nil
+}
+
+// ============ Playlist Stats Caching ============
+
+// SetPlaylistAudioStats caches the audio feature averages computed for a playlist
+func (r *MusicCacheRepository) SetPlaylistAudioStats(ctx context.Context, stats *models.PlaylistAudioStats) error {
+	key := fmt.Sprintf("playlist_stats:%s", stats.PlaylistID.String())
+
+	jsonData, err := json.Marshal(stats)
+	if err != nil {
+		return fmt.Errorf("failed to marshal playlist stats: %w", err)
+	}
+
+	return r.client.Client.Set(ctx, key, jsonData, PlaylistStatsTTL).Err()
+}
+
+// GetPlaylistAudioStats retrieves a playlist's cached audio feature averages
+func (r *MusicCacheRepository) GetPlaylistAudioStats(ctx context.Context, playlistID uuid.UUID) (*models.PlaylistAudioStats, error) {
+	key := fmt.Sprintf("playlist_stats:%s", playlistID.String())
+
+	data, err := r.client.Client.Get(ctx, key).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil // Cache miss
+		}
+		return nil, fmt.Errorf("failed to get playlist stats: %w", err)
+	}
+
+	var stats models.PlaylistAudioStats
+	if err := json.Unmarshal([]byte(data), &stats); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal playlist stats: %w", err)
+	}
+
+	return &stats, nil
+}
+
+// DeletePlaylistAudioStats drops a playlist's cached averages so the next
+// read recomputes them from its current tracks
+func (r *MusicCacheRepository) DeletePlaylistAudioStats(ctx context.Context, playlistID uuid.UUID) error {
+	key := fmt.Sprintf("playlist_stats:%s", playlistID.String())
+
+	return r.client.Client.Del(ctx, key).Err()
+}
+
 // ============ Popular Content Caching ============
 
 // SetPopularAlbums caches popular albums for faster recommendations
@@ -279,6 +459,99 @@ func (r *MusicCacheRepository) GetPopularTracks(ctx context.Context) ([]*models.
 	return tracks, nil
 }
 
+// ============ Spotify Item Caching ============
+
+func spotifyItemKey(itemType, id string) string {
+	return fmt.Sprintf("spotify_item:%s:%s", itemType, id)
+}
+
+// SetSpotifyItems caches Spotify item metadata of one type, keyed by Spotify ID
+func (r *MusicCacheRepository) SetSpotifyItems(ctx context.Context, itemType string, items map[string]interface{}) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	pipe := r.client.Client.Pipeline()
+	for id, item := range items {
+		jsonData, err := json.Marshal(item)
+		if err != nil {
+			return fmt.Errorf("failed to marshal spotify item: %w", err)
+		}
+		pipe.Set(ctx, spotifyItemKey(itemType, id), jsonData, SpotifyItemCacheTTL)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to cache spotify items: %w", err)
+	}
+
+	return nil
+}
+
+// SetSpotifyItemsNotFound caches tombstones for Spotify IDs that Spotify
+// does not know, so repeated lookups skip the API until they expire
+func (r *MusicCacheRepository) SetSpotifyItemsNotFound(ctx context.Context, itemType string, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	pipe := r.client.Client.Pipeline()
+	for _, id := range ids {
+		pipe.Set(ctx, spotifyItemKey(itemType, id), "", SpotifyNotFoundTTL)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to cache spotify not-found items: %w", err)
+	}
+
+	return nil
+}
+
+// GetSpotifyItem fetches one cached Spotify item. A miss returns nil data and
+// no error; a tombstone returns repository.ErrCachedNotFound.
+func (r *MusicCacheRepository) GetSpotifyItem(ctx context.Context, itemType, id string) ([]byte, error) {
+	data, err := r.client.Client.Get(ctx, spotifyItemKey(itemType, id)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil // Cache miss
+		}
+		return nil, fmt.Errorf("failed to get spotify item: %w", err)
+	}
+
+	if data == "" {
+		return nil, repository.ErrCachedNotFound
+	}
+
+	return []byte(data), nil
+}
+
+// GetSpotifyItems fetches cached Spotify items of one type in a single round
+// trip. The result maps each cached ID to its JSON, or to an empty value for
+// IDs cached as not found; IDs not in the cache are absent.
+func (r *MusicCacheRepository) GetSpotifyItems(ctx context.Context, itemType string, ids []string) (map[string][]byte, error) {
+	items := make(map[string][]byte)
+	if len(ids) == 0 {
+		return items, nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = spotifyItemKey(itemType, id)
+	}
+
+	values, err := r.client.Client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spotify items: %w", err)
+	}
+
+	for i, value := range values {
+		if data, ok := value.(string); ok {
+			items[ids[i]] = []byte(data)
+		}
+	}
+
+	return items, nil
+}
+
 // ============ Cache Management ============
 
 // InvalidateUserCache removes all cached data for a user
@@ -286,26 +559,97 @@ func (r *MusicCacheRepository) InvalidateUserCache(ctx context.Context, userID u
 	keys := []string{
 		fmt.Sprintf("user_music:%s", userID.String()),
 		fmt.Sprintf("history:%s", userID.String()),
+		fmt.Sprintf("recommendations:%s", userID.String()),
+		recentlyPlayedKey(userID),
 	}
 
 	return r.client.Client.Del(ctx, keys...).Err()
 }
 
-// InvalidateSearchCache removes cached search results for a query
+// InvalidateSearchCache removes cached search results for every page of a query
 func (r *MusicCacheRepository) InvalidateSearchCache(ctx context.Context, query string) error {
-	pattern := fmt.Sprintf("search:*:%s", query)
+	pattern := fmt.Sprintf("search:*:%s:*:*", escapeGlob(query))
 
-	// Get all matching keys
-	keys, err := r.client.Client.Keys(ctx, pattern).Result()
+	if _, err := r.deleteMatching(ctx, pattern); err != nil {
+		return fmt.Errorf("failed to invalidate search cache: %w", err)
+	}
+
+	return nil
+}
+
+// ClearSpotifyCache deletes every cached Spotify item and returns how many
+// keys were removed. Sessions and other cached data are left alone.
+func (r *MusicCacheRepository) ClearSpotifyCache(ctx context.Context) (int, error) {
+	removed, err := r.deleteMatching(ctx, "spotify_item:*")
 	if err != nil {
-		return err
+		return removed, fmt.Errorf("failed to clear spotify cache: %w", err)
 	}
 
-	if len(keys) > 0 {
-		return r.client.Client.Del(ctx, keys...).Err()
+	return removed, nil
+}
+
+// scanBatchSize is the SCAN COUNT hint and the number of keys per DEL
+const scanBatchSize = 500
+
+// globEscaper backslash-escapes the characters Redis treats specially in
+// MATCH patterns
+var globEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
+
+// escapeGlob makes s match only itself when used in a MATCH pattern
+func escapeGlob(s string) string {
+	return globEscaper.Replace(s)
+}
+
+// deleteMatching removes keys matching pattern in SCAN-sized batches so large
+// keyspaces never block Redis the way KEYS does
+func (r *MusicCacheRepository) deleteMatching(ctx context.Context, pattern string) (int, error) {
+	removed := 0
+	keys := make([]string, 0, scanBatchSize)
+
+	flush := func() error {
+		if len(keys) == 0 {
+			return nil
+		}
+		deleted, err := r.client.Client.Del(ctx, keys...).Result()
+		if err != nil {
+			return err
+		}
+		removed += int(deleted)
+		keys = keys[:0]
+		return nil
 	}
 
-	return nil
+	iter := r.client.Client.Scan(ctx, 0, pattern, scanBatchSize).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+		if len(keys) == scanBatchSize {
+			if err := flush(); err != nil {
+				return removed, err
+			}
+		}
+	}
+
+	if err := iter.Err(); err != nil {
+		return removed, err
+	}
+
+	if err := flush(); err != nil {
+		return removed, err
+	}
+
+	return removed, nil
+}
+
+// countMatching counts keys matching pattern using SCAN
+func (r *MusicCacheRepository) countMatching(ctx context.Context, pattern string) (int, error) {
+	count := 0
+
+	iter := r.client.Client.Scan(ctx, 0, pattern, scanBatchSize).Iterator()
+	for iter.Next(ctx) {
+		count++
+	}
+
+	return count, iter.Err()
 }
 
 // GetCacheStats returns cache statistics
@@ -314,19 +658,22 @@ func (r *MusicCacheRepository) GetCacheStats(ctx context.Context) (map[string]in
 
 	// Count different types of cached data
 	patterns := map[string]string{
-		"user_music": "user_music:*",
-		"searches":   "search:*",
-		"history":    "history:*",
-		"popular":    "popular:*",
-		"sessions":   "session:*",
+		"user_music":      "user_music:*",
+		"searches":        "search:*",
+		"history":         "history:*",
+		"popular":         "popular:*",
+		"sessions":        "session:*",
+		"spotify":         "spotify_item:*",
+		"recommendations": "recommendations:*",
+		"playlist_stats":  "playlist_stats:*",
 	}
 
 	for name, pattern := range patterns {
-		keys, err := r.client.Client.Keys(ctx, pattern).Result()
+		count, err := r.countMatching(ctx, pattern)
 		if err != nil {
 			continue // Skip on error
 		}
-		stats[name] = len(keys)
+		stats[name] = count
 	}
 
 	return stats, nil
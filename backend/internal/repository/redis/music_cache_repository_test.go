@@ -0,0 +1,471 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/daedal00/muse/backend/internal/models"
+	"github.com/daedal00/muse/backend/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMusicCacheRepository_ClearSpotifyCache(t *testing.T) {
+	if testRedis == nil {
+		t.Skip("Redis not available")
+	}
+
+	repo := NewMusicCacheRepository(testRedis)
+	ctx := context.Background()
+
+	// Clean up before test
+	testRedis.Client.FlushDB(ctx)
+
+	err := repo.SetSpotifyItems(ctx, "track", map[string]interface{}{
+		"track-1": map[string]string{"name": "One"},
+		"track-2": map[string]string{"name": "Two"},
+	})
+	require.NoError(t, err)
+	err = repo.SetSpotifyItems(ctx, "album", map[string]interface{}{
+		"album-1": map[string]string{"name": "Album"},
+	})
+	require.NoError(t, err)
+
+	others := []string{"session:test-session", "user_sessions:test-user", "search:albums:query", "metrics:hits"}
+	for _, key := range others {
+		require.NoError(t, testRedis.Client.Set(ctx, key, "value", time.Hour).Err())
+	}
+
+	removed, err := repo.ClearSpotifyCache(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, removed)
+
+	items, err := repo.GetSpotifyItems(ctx, "track", []string{"track-1", "track-2"})
+	require.NoError(t, err)
+	assert.Empty(t, items)
+
+	for _, key := range others {
+		exists, err := testRedis.Client.Exists(ctx, key).Result()
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), exists, "expected %s to survive", key)
+	}
+}
+
+func TestMusicCacheRepository_InvalidateSearchCache_ManyKeys(t *testing.T) {
+	if testRedis == nil {
+		t.Skip("Redis not available")
+	}
+
+	repo := NewMusicCacheRepository(testRedis)
+	ctx := context.Background()
+
+	// Clean up before test
+	testRedis.Client.FlushDB(ctx)
+
+	// Enough pages of one query to need several SCAN and DEL batches, plus
+	// other queries that must survive
+	pipe := testRedis.Client.Pipeline()
+	for i := 0; i < 3000; i++ {
+		pipe.Set(ctx, searchKey("albums", "jazz", 20, i*20), "results", time.Hour)
+	}
+	for i := 0; i < 200; i++ {
+		pipe.Set(ctx, searchKey("albums", "rock", 20, i*20), "results", time.Hour)
+		pipe.Set(ctx, fmt.Sprintf("user_music:%d", i), "data", time.Hour)
+	}
+	_, err := pipe.Exec(ctx)
+	require.NoError(t, err)
+
+	stats, err := repo.GetCacheStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3200, stats["searches"])
+	assert.Equal(t, 200, stats["user_music"])
+
+	require.NoError(t, repo.InvalidateSearchCache(ctx, "jazz"))
+
+	stats, err = repo.GetCacheStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 200, stats["searches"])
+	assert.Equal(t, 200, stats["user_music"])
+}
+
+func TestMusicCacheRepository_InvalidateSearchCache_GlobCharacters(t *testing.T) {
+	if testRedis == nil {
+		t.Skip("Redis not available")
+	}
+
+	repo := NewMusicCacheRepository(testRedis)
+	ctx := context.Background()
+
+	// Clean up before test
+	testRedis.Client.FlushDB(ctx)
+
+	queries := []string{"*", "a?c", "abc", "[ab]c", "bc", `back\slash`, `backslash`}
+	for _, query := range queries {
+		require.NoError(t, testRedis.Client.Set(ctx, searchKey("albums", query, 20, 0), "results", time.Hour).Err())
+	}
+
+	// Each query only removes its own results, never ones its characters
+	// would match as a pattern
+	for i, query := range queries {
+		require.NoError(t, repo.InvalidateSearchCache(ctx, query))
+
+		for j, other := range queries {
+			exists, err := testRedis.Client.Exists(ctx, searchKey("albums", other, 20, 0)).Result()
+			require.NoError(t, err)
+			assert.Equal(t, j > i, exists == 1, "after invalidating %q, results for %q", query, other)
+		}
+	}
+}
+
+func TestMusicCacheRepository_SpotifyNotFoundTombstone(t *testing.T) {
+	if testRedis == nil {
+		t.Skip("Redis not available")
+	}
+
+	repo := NewMusicCacheRepository(testRedis)
+	ctx := context.Background()
+
+	// Clean up before test
+	testRedis.Client.FlushDB(ctx)
+
+	// Unknown IDs start as plain misses
+	data, err := repo.GetSpotifyItem(ctx, "track", "missing")
+	require.NoError(t, err)
+	assert.Nil(t, data)
+
+	require.NoError(t, repo.SetSpotifyItemsNotFound(ctx, "track", []string{"missing"}))
+
+	_, err = repo.GetSpotifyItem(ctx, "track", "missing")
+	assert.ErrorIs(t, err, repository.ErrCachedNotFound)
+
+	items, err := repo.GetSpotifyItems(ctx, "track", []string{"missing", "other"})
+	require.NoError(t, err)
+	require.Contains(t, items, "missing")
+	assert.Empty(t, items["missing"])
+	assert.NotContains(t, items, "other")
+
+	// Tombstones use the short TTL rather than the item TTL
+	ttl, err := testRedis.Client.TTL(ctx, spotifyItemKey("track", "missing")).Result()
+	require.NoError(t, err)
+	assert.True(t, ttl > 0 && ttl <= SpotifyNotFoundTTL, "unexpected tombstone TTL %s", ttl)
+
+	// Caching the real item later replaces the tombstone
+	require.NoError(t, repo.SetSpotifyItems(ctx, "track", map[string]interface{}{"missing": map[string]string{"name": "Found"}}))
+	data, err = repo.GetSpotifyItem(ctx, "track", "missing")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"Found"}`, string(data))
+
+	// Once a tombstone is gone, as after it expires, the ID is a plain miss again
+	require.NoError(t, repo.SetSpotifyItemsNotFound(ctx, "album", []string{"gone"}))
+	require.NoError(t, testRedis.Client.Del(ctx, spotifyItemKey("album", "gone")).Err())
+	data, err = repo.GetSpotifyItem(ctx, "album", "gone")
+	require.NoError(t, err)
+	assert.Nil(t, data)
+}
+
+func TestMusicCacheRepository_TypedSearchResults(t *testing.T) {
+	if testRedis == nil {
+		t.Skip("Redis not available")
+	}
+
+	repo := NewMusicCacheRepository(testRedis)
+	ctx := context.Background()
+
+	// Clean up before test
+	testRedis.Client.FlushDB(ctx)
+
+	// Misses are nil rather than an error
+	cached, err := repo.GetAlbumSearchResults(ctx, "ok computer", 20, 0)
+	require.NoError(t, err)
+	assert.Nil(t, cached)
+
+	albums := []models.SpotifyAlbum{
+		{
+			ID:          "album-1",
+			Name:        "OK Computer",
+			Artists:     []models.SpotifyArtist{{ID: "artist-1", Name: "Radiohead"}},
+			ReleaseDate: "1997-05-21",
+			ImageURL:    "https://i.scdn.co/image/ok-computer",
+		},
+		{
+			ID:      "album-2",
+			Name:    "Kid A",
+			Artists: []models.SpotifyArtist{{ID: "artist-1", Name: "Radiohead"}, {ID: "artist-2", Name: "Guest"}},
+		},
+	}
+	require.NoError(t, repo.SetAlbumSearchResults(ctx, "ok computer", 20, 0, albums))
+
+	cached, err = repo.GetAlbumSearchResults(ctx, "ok computer", 20, 0)
+	require.NoError(t, err)
+	assert.Equal(t, albums, cached)
+
+	tracks := []models.SpotifyTrack{
+		{ID: "track-1", Name: "Airbag", Artists: albums[0].Artists, Album: &albums[0], DurationMs: 284000, TrackNumber: 1},
+	}
+	require.NoError(t, repo.SetTrackSearchResults(ctx, "airbag", 20, 0, tracks))
+
+	cachedTracks, err := repo.GetTrackSearchResults(ctx, "airbag", 20, 0)
+	require.NoError(t, err)
+	assert.Equal(t, tracks, cachedTracks)
+
+	// Result types are cached under separate keys
+	cachedArtists, err := repo.GetArtistSearchResults(ctx, "ok computer", 20, 0)
+	require.NoError(t, err)
+	assert.Nil(t, cachedArtists)
+}
+
+func TestMusicCacheRepository_SearchResultsPerPage(t *testing.T) {
+	if testRedis == nil {
+		t.Skip("Redis not available")
+	}
+
+	repo := NewMusicCacheRepository(testRedis)
+	ctx := context.Background()
+
+	// Clean up before test
+	testRedis.Client.FlushDB(ctx)
+
+	page1 := []models.SpotifyArtist{{ID: "artist-1", Name: "First"}, {ID: "artist-2", Name: "Second"}}
+	page2 := []models.SpotifyArtist{{ID: "artist-3", Name: "Third"}, {ID: "artist-4", Name: "Fourth"}}
+	require.NoError(t, repo.SetArtistSearchResults(ctx, "jazz", 2, 0, page1))
+	require.NoError(t, repo.SetArtistSearchResults(ctx, "jazz", 2, 2, page2))
+
+	cached, err := repo.GetArtistSearchResults(ctx, "jazz", 2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, page1, cached)
+
+	cached, err = repo.GetArtistSearchResults(ctx, "jazz", 2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, page2, cached)
+
+	// A different page size is a different cache entry
+	cached, err = repo.GetArtistSearchResults(ctx, "jazz", 4, 0)
+	require.NoError(t, err)
+	assert.Nil(t, cached)
+
+	// Invalidation clears every page of the query
+	require.NoError(t, repo.SetArtistSearchResults(ctx, "rock", 2, 0, page1))
+	require.NoError(t, repo.InvalidateSearchCache(ctx, "jazz"))
+
+	for _, offset := range []int{0, 2} {
+		cached, err = repo.GetArtistSearchResults(ctx, "jazz", 2, offset)
+		require.NoError(t, err)
+		assert.Nil(t, cached)
+	}
+	cached, err = repo.GetArtistSearchResults(ctx, "rock", 2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, page1, cached)
+}
+
+func TestMusicCacheRepository_Recommendations(t *testing.T) {
+	if testRedis == nil {
+		t.Skip("Redis not available")
+	}
+
+	repo := NewMusicCacheRepository(testRedis)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	// Nothing cached yet is a miss, not an error
+	cached, err := repo.GetRecommendations(ctx, userID)
+	require.NoError(t, err)
+	assert.Nil(t, cached)
+
+	recommendations := &models.CachedRecommendations{
+		UserID:      userID,
+		Tracks:      []models.SpotifyTrack{{ID: "track-1", Name: "Track One"}},
+		SeedArtists: []string{"artist-1"},
+		SeedGenres:  []string{"jazz"},
+		GeneratedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	require.NoError(t, repo.SetRecommendations(ctx, recommendations))
+
+	cached, err = repo.GetRecommendations(ctx, userID)
+	require.NoError(t, err)
+	require.NotNil(t, cached)
+	assert.Equal(t, recommendations, cached)
+
+	ttl, err := testRedis.Client.TTL(ctx, "recommendations:"+userID.String()).Result()
+	require.NoError(t, err)
+	assert.LessOrEqual(t, ttl, RecommendationsTTL)
+
+	// Invalidating the user's cache drops their recommendations too
+	require.NoError(t, repo.InvalidateUserCache(ctx, userID))
+	cached, err = repo.GetRecommendations(ctx, userID)
+	require.NoError(t, err)
+	assert.Nil(t, cached)
+}
+
+func TestMusicCacheRepository_PlaylistAudioStats(t *testing.T) {
+	if testRedis == nil {
+		t.Skip("Redis not available")
+	}
+
+	repo := NewMusicCacheRepository(testRedis)
+	ctx := context.Background()
+	playlistID := uuid.New()
+
+	cached, err := repo.GetPlaylistAudioStats(ctx, playlistID)
+	require.NoError(t, err)
+	assert.Nil(t, cached)
+
+	stats := &models.PlaylistAudioStats{
+		PlaylistID:   playlistID,
+		TrackCount:   4,
+		Energy:       0.75,
+		Danceability: 0.5,
+		Valence:      0.25,
+		Tempo:        118.5,
+		ComputedAt:   time.Now().UTC().Truncate(time.Second),
+	}
+	require.NoError(t, repo.SetPlaylistAudioStats(ctx, stats))
+
+	cached, err = repo.GetPlaylistAudioStats(ctx, playlistID)
+	require.NoError(t, err)
+	assert.Equal(t, stats, cached)
+
+	ttl, err := testRedis.Client.TTL(ctx, "playlist_stats:"+playlistID.String()).Result()
+	require.NoError(t, err)
+	assert.Greater(t, ttl, time.Duration(0))
+	assert.LessOrEqual(t, ttl, PlaylistStatsTTL)
+
+	require.NoError(t, repo.DeletePlaylistAudioStats(ctx, playlistID))
+	cached, err = repo.GetPlaylistAudioStats(ctx, playlistID)
+	require.NoError(t, err)
+	assert.Nil(t, cached)
+}
+
+func TestMusicCacheRepository_RecentlyPlayed(t *testing.T) {
+	if testRedis == nil {
+		t.Skip("Redis not available")
+	}
+
+	repo := NewMusicCacheRepository(testRedis)
+	ctx := context.Background()
+	userID := uuid.New()
+	defer func() { _ = repo.InvalidateUserCache(ctx, userID) }()
+
+	empty, err := repo.GetRecentlyPlayed(ctx, userID, 10)
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+
+	addConcurrently := func(from, to int) {
+		var wg sync.WaitGroup
+		for i := from; i < to; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				track := &models.Track{ID: uuid.New(), Title: fmt.Sprintf("Track %d", i)}
+				assert.NoError(t, repo.AddToRecentlyPlayed(ctx, userID, track))
+			}()
+		}
+		wg.Wait()
+	}
+
+	// Below the cap every concurrent play is kept
+	addConcurrently(0, 30)
+	tracks, err := repo.GetRecentlyPlayed(ctx, userID, 0)
+	require.NoError(t, err)
+	titles := make(map[string]bool)
+	for _, track := range tracks {
+		titles[track.Title] = true
+	}
+	assert.Len(t, titles, 30)
+
+	// Past the cap the list stays bounded
+	addConcurrently(30, 100)
+	tracks, err = repo.GetRecentlyPlayed(ctx, userID, 0)
+	require.NoError(t, err)
+	assert.Len(t, tracks, MaxRecentlyPlayed)
+
+	length, err := testRedis.Client.LLen(ctx, recentlyPlayedKey(userID)).Result()
+	require.NoError(t, err)
+	assert.Equal(t, int64(MaxRecentlyPlayed), length)
+
+	// Newest first, and the limit is applied
+	latest := &models.Track{ID: uuid.New(), Title: "Latest"}
+	require.NoError(t, repo.AddToRecentlyPlayed(ctx, userID, latest))
+	tracks, err = repo.GetRecentlyPlayed(ctx, userID, 3)
+	require.NoError(t, err)
+	require.Len(t, tracks, 3)
+	assert.Equal(t, latest.ID, tracks[0].ID)
+
+	ttl, err := testRedis.Client.TTL(ctx, recentlyPlayedKey(userID)).Result()
+	require.NoError(t, err)
+	assert.Greater(t, ttl, time.Duration(0))
+}
+
+func TestMusicCacheRepository_UserMusicData(t *testing.T) {
+	if testRedis == nil {
+		t.Skip("Redis not available")
+	}
+
+	var repo repository.MusicCacheRepository = NewMusicCacheRepository(testRedis)
+	ctx := context.Background()
+	userID := uuid.New()
+	defer func() { _ = repo.InvalidateUserCache(ctx, userID) }()
+
+	missing, err := repo.GetUserMusicData(ctx, userID)
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	album := &models.Album{ID: uuid.New(), Title: "Favorite"}
+	require.NoError(t, repo.SetUserMusicData(ctx, userID, &MusicData{FavoriteAlbums: []*models.Album{album}}))
+
+	cached, err := repo.GetUserMusicData(ctx, userID)
+	require.NoError(t, err)
+	data, ok := cached.(*MusicData)
+	require.True(t, ok, "expected *MusicData, got %T", cached)
+	require.Len(t, data.FavoriteAlbums, 1)
+	assert.Equal(t, album.ID, data.FavoriteAlbums[0].ID)
+	assert.False(t, data.LastUpdated.IsZero())
+
+	assert.Error(t, repo.SetUserMusicData(ctx, userID, map[string]string{"not": "music data"}))
+}
+
+func TestMusicCacheRepository_CompressedRecommendations(t *testing.T) {
+	if testRedis == nil {
+		t.Skip("Redis not available")
+	}
+
+	repo := NewMusicCacheRepository(testRedis)
+	ctx := context.Background()
+	userID := uuid.New()
+	key := "recommendations:" + userID.String()
+	defer func() { _ = repo.InvalidateUserCache(ctx, userID) }()
+
+	recommendations := &models.CachedRecommendations{
+		UserID:      userID,
+		SeedArtists: []string{"artist-1"},
+		GeneratedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	for i := 0; i < 100; i++ {
+		recommendations.Tracks = append(recommendations.Tracks, models.SpotifyTrack{
+			ID:      fmt.Sprintf("track-%d", i),
+			Name:    fmt.Sprintf("Track %d", i),
+			Artists: []models.SpotifyArtist{{ID: "artist-1", Name: "Artist One"}},
+		})
+	}
+	require.NoError(t, repo.SetRecommendations(ctx, recommendations))
+
+	raw, err := json.Marshal(recommendations)
+	require.NoError(t, err)
+	stored, err := testRedis.Client.Get(ctx, key).Bytes()
+	require.NoError(t, err)
+	assert.Less(t, len(stored), len(raw))
+
+	cached, err := repo.GetRecommendations(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, recommendations, cached)
+
+	// Values cached as plain JSON before compression still decode
+	require.NoError(t, testRedis.Client.Set(ctx, key, raw, time.Minute).Err())
+	cached, err = repo.GetRecommendations(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, recommendations, cached)
+}
@@ -26,16 +26,9 @@ func (r *sessionRepository) Create(ctx context.Context, session *models.Session)
 	userSessionsKey := fmt.Sprintf("user_sessions:%s", session.UserID.String())
 
 	// Serialize session data
-	sessionData := map[string]interface{}{
-		"id":         session.ID,
-		"user_id":    session.UserID.String(),
-		"expires_at": session.ExpiresAt.Unix(),
-		"created_at": session.CreatedAt.Unix(),
-	}
-
-	serializedData, err := json.Marshal(sessionData)
+	serializedData, err := encodeSession(session)
 	if err != nil {
-		return fmt.Errorf("failed to serialize session: %w", err)
+		return err
 	}
 
 	// Calculate TTL
@@ -68,11 +61,42 @@ func (r *sessionRepository) GetByID(ctx context.Context, id string) (*models.Ses
 	data, err := r.client.Client.Get(ctx, sessionKey).Result()
 	if err != nil {
 		if err == redis.Nil {
-			return nil, fmt.Errorf("session not found or expired")
+			return nil, fmt.Errorf("session %w or expired", repository.ErrNotFound)
 		}
 		return nil, fmt.Errorf("failed to get session: %w", err)
 	}
 
+	return decodeSession(data)
+}
+
+// encodeSession serializes a session for storage under its session key
+func encodeSession(session *models.Session) ([]byte, error) {
+	lastSeenAt := session.LastSeenAt
+	if lastSeenAt.IsZero() {
+		lastSeenAt = session.CreatedAt
+	}
+
+	sessionData := map[string]interface{}{
+		"id":           session.ID,
+		"user_id":      session.UserID.String(),
+		"expires_at":   session.ExpiresAt.Unix(),
+		"created_at":   session.CreatedAt.Unix(),
+		"user_agent":   session.UserAgent,
+		"ip_address":   session.IPAddress,
+		"last_seen_at": lastSeenAt.Unix(),
+	}
+
+	serializedData, err := json.Marshal(sessionData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize session: %w", err)
+	}
+	return serializedData, nil
+}
+
+// decodeSession parses a session stored by encodeSession. Sessions stored
+// before client metadata was recorded decode with it empty and LastSeenAt
+// set to CreatedAt.
+func decodeSession(data string) (*models.Session, error) {
 	var sessionData map[string]interface{}
 	if err := json.Unmarshal([]byte(data), &sessionData); err != nil {
 		return nil, fmt.Errorf("failed to deserialize session: %w", err)
@@ -86,12 +110,25 @@ func (r *sessionRepository) GetByID(ctx context.Context, id string) (*models.Ses
 	expiresAt := time.Unix(int64(sessionData["expires_at"].(float64)), 0)
 	createdAt := time.Unix(int64(sessionData["created_at"].(float64)), 0)
 
-	return &models.Session{
-		ID:        sessionData["id"].(string),
-		UserID:    userID,
-		ExpiresAt: expiresAt,
-		CreatedAt: createdAt,
-	}, nil
+	session := &models.Session{
+		ID:         sessionData["id"].(string),
+		UserID:     userID,
+		ExpiresAt:  expiresAt,
+		CreatedAt:  createdAt,
+		LastSeenAt: createdAt,
+	}
+
+	if userAgent, ok := sessionData["user_agent"].(string); ok {
+		session.UserAgent = userAgent
+	}
+	if ipAddress, ok := sessionData["ip_address"].(string); ok {
+		session.IPAddress = ipAddress
+	}
+	if lastSeenAt, ok := sessionData["last_seen_at"].(float64); ok && lastSeenAt > 0 {
+		session.LastSeenAt = time.Unix(int64(lastSeenAt), 0)
+	}
+
+	return session, nil
 }
 
 func (r *sessionRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*models.Session, error) {
@@ -130,28 +167,98 @@ func (r *sessionRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (
 			continue
 		}
 
-		var sessionData map[string]interface{}
-		if err := json.Unmarshal([]byte(result.(string)), &sessionData); err != nil {
+		session, err := decodeSession(result.(string))
+		if err != nil {
 			continue // Skip invalid sessions
 		}
 
-		userIDParsed, err := uuid.Parse(sessionData["user_id"].(string))
-		if err != nil {
-			continue // Skip invalid sessions
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+// refreshSessionScript sets a stored session's expires_at and TTL and extends
+// its user's session set in one step, so a concurrent UpdateLastSeen cannot
+// write back the old expiry and a concurrent delete cannot be undone.
+// KEYS[2] is the session set of the user Refresh read the session for; a
+// session that now belongs to someone else is left alone. PEXPIRE GT never
+// shortens the set below another, longer-lived session.
+var refreshSessionScript = redis.NewScript(`
+local data = redis.call("GET", KEYS[1])
+if not data then
+	return false
+end
+local session = cjson.decode(data)
+if session.user_id ~= ARGV[3] then
+	return false
+end
+session.expires_at = tonumber(ARGV[1])
+redis.call("SET", KEYS[1], cjson.encode(session), "PX", ARGV[2])
+redis.call("PEXPIRE", KEYS[2], ARGV[2], "GT")
+return 1
+`)
+
+// lastSeenInterval is how stale a session's last_seen_at must be before
+// UpdateLastSeen rewrites it, so busy clients don't write on every request
+const lastSeenInterval = time.Minute
+
+// updateLastSeenScript sets a stored session's last_seen_at in one step,
+// keeping its TTL, so a concurrent Refresh cannot be undone. Sessions seen
+// within the last ARGV[2] seconds are left as they are.
+var updateLastSeenScript = redis.NewScript(`
+local data = redis.call("GET", KEYS[1])
+if not data then
+	return false
+end
+local session = cjson.decode(data)
+local now = tonumber(ARGV[1])
+if session.last_seen_at and now - session.last_seen_at < tonumber(ARGV[2]) then
+	return 1
+end
+session.last_seen_at = now
+redis.call("SET", KEYS[1], cjson.encode(session), "KEEPTTL")
+return 1
+`)
+
+func (r *sessionRepository) Refresh(ctx context.Context, id string, newExpiry time.Time) error {
+	ttl := time.Until(newExpiry)
+	if ttl <= 0 {
+		return fmt.Errorf("session %w: new expiry has passed", repository.ErrNotFound)
+	}
+
+	// The user's session set key is named after the user, so read the
+	// session first to pass it to the script
+	session, err := r.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	userID := session.UserID.String()
+
+	keys := []string{fmt.Sprintf("session:%s", id), fmt.Sprintf("user_sessions:%s", userID)}
+	err = refreshSessionScript.Run(ctx, r.client.Client, keys, newExpiry.Unix(), ttl.Milliseconds(), userID).Err()
+	if err != nil {
+		if err == redis.Nil {
+			return fmt.Errorf("session %w", repository.ErrNotFound)
 		}
+		return fmt.Errorf("failed to refresh session: %w", err)
+	}
+
+	return nil
+}
 
-		expiresAt := time.Unix(int64(sessionData["expires_at"].(float64)), 0)
-		createdAt := time.Unix(int64(sessionData["created_at"].(float64)), 0)
+func (r *sessionRepository) UpdateLastSeen(ctx context.Context, id string) error {
+	sessionKey := fmt.Sprintf("session:%s", id)
 
-		sessions = append(sessions, &models.Session{
-			ID:        sessionData["id"].(string),
-			UserID:    userIDParsed,
-			ExpiresAt: expiresAt,
-			CreatedAt: createdAt,
-		})
+	err := updateLastSeenScript.Run(ctx, r.client.Client, []string{sessionKey}, time.Now().Unix(), int64(lastSeenInterval.Seconds())).Err()
+	if err != nil {
+		if err == redis.Nil {
+			return fmt.Errorf("session %w", repository.ErrNotFound)
+		}
+		return fmt.Errorf("failed to update session last seen: %w", err)
 	}
 
-	return sessions, nil
+	return nil
 }
 
 func (r *sessionRepository) Delete(ctx context.Context, id string) error {
@@ -269,3 +376,105 @@ func (r *sessionRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID
 
 	return nil
 }
+
+func (r *sessionRepository) DeleteByUserIDExcept(ctx context.Context, userID uuid.UUID, keepSessionID string) error {
+	userSessionsKey := fmt.Sprintf("user_sessions:%s", userID.String())
+
+	// Get all session IDs for the user
+	sessionIDs, err := r.client.Client.SMembers(ctx, userSessionsKey).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil // No sessions to delete
+		}
+		return fmt.Errorf("failed to get user sessions: %w", err)
+	}
+
+	var sessionKeys []string
+	var removedIDs []interface{}
+	for _, sessionID := range sessionIDs {
+		if sessionID == keepSessionID {
+			continue
+		}
+		sessionKeys = append(sessionKeys, fmt.Sprintf("session:%s", sessionID))
+		removedIDs = append(removedIDs, sessionID)
+	}
+
+	if len(sessionKeys) == 0 {
+		return nil
+	}
+
+	// Delete the other sessions and drop them from the user's session set
+	pipe := r.client.Client.TxPipeline()
+	pipe.Del(ctx, sessionKeys...)
+	pipe.SRem(ctx, userSessionsKey, removedIDs...)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to delete other sessions by user: %w", err)
+	}
+
+	return nil
+}
+
+// rotateRefreshTokenScript moves a refresh token's session ID to the new
+// token's key, so the old token is spent by the same command that issues
+// its replacement. KEYS[3] is the session RotateRefreshToken read the token
+// for; if the token now names another session nothing changes. A token whose
+// session has ended is spent without a replacement, so logging out or
+// deleting the account leaves nothing behind to renew.
+var rotateRefreshTokenScript = redis.NewScript(`
+local sessionID = redis.call("GET", KEYS[1])
+if sessionID ~= ARGV[2] then
+	return false
+end
+redis.call("DEL", KEYS[1])
+if redis.call("EXISTS", KEYS[3]) == 0 then
+	return false
+end
+redis.call("SET", KEYS[2], sessionID, "PX", ARGV[1])
+return sessionID
+`)
+
+func refreshTokenKey(tokenHash string) string {
+	return fmt.Sprintf("refresh_token:%s", tokenHash)
+}
+
+func (r *sessionRepository) StoreRefreshToken(ctx context.Context, tokenHash, sessionID string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return fmt.Errorf("refresh token already expired")
+	}
+
+	if err := r.client.Client.Set(ctx, refreshTokenKey(tokenHash), sessionID, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to store refresh token: %w", err)
+	}
+
+	return nil
+}
+
+func (r *sessionRepository) RotateRefreshToken(ctx context.Context, oldHash, newHash string, expiresAt time.Time) (string, error) {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return "", fmt.Errorf("refresh token already expired")
+	}
+
+	// The session key is named after the session, so look the token up first
+	// to pass it to the script
+	sessionID, err := r.client.Client.Get(ctx, refreshTokenKey(oldHash)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return "", fmt.Errorf("refresh token %w", repository.ErrNotFound)
+		}
+		return "", fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	keys := []string{refreshTokenKey(oldHash), refreshTokenKey(newHash), fmt.Sprintf("session:%s", sessionID)}
+	sessionID, err = rotateRefreshTokenScript.Run(ctx, r.client.Client, keys, ttl.Milliseconds(), sessionID).Text()
+	if err != nil {
+		if err == redis.Nil {
+			return "", fmt.Errorf("refresh token %w", repository.ErrNotFound)
+		}
+		return "", fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+
+	return sessionID, nil
+}
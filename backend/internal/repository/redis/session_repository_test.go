@@ -4,11 +4,13 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/daedal00/muse/backend/internal/database"
 	"github.com/daedal00/muse/backend/internal/models"
+	"github.com/daedal00/muse/backend/internal/repository"
 	"github.com/google/uuid"
 	"github.com/joho/godotenv"
 	"github.com/stretchr/testify/assert"
@@ -144,6 +146,80 @@ func TestSessionRepository_GetByID(t *testing.T) {
 	assert.Contains(t, err.Error(), "not found")
 }
 
+func TestSessionRepository_Metadata(t *testing.T) {
+	if testRedis == nil {
+		t.Skip("Redis not available")
+	}
+
+	repo := NewSessionRepository(testRedis)
+	ctx := context.Background()
+
+	// Clean up before test
+	testRedis.Client.FlushDB(ctx)
+
+	userID := uuid.New()
+	createdAt := time.Now().Add(-time.Hour)
+	session := &models.Session{
+		ID:         "metadata-test-session",
+		UserID:     userID,
+		ExpiresAt:  time.Now().Add(1 * time.Hour),
+		CreatedAt:  createdAt,
+		UserAgent:  "Mozilla/5.0 (Macintosh)",
+		IPAddress:  "203.0.113.7",
+		LastSeenAt: createdAt,
+	}
+
+	err := repo.Create(ctx, session)
+	require.NoError(t, err)
+
+	// Client metadata survives the round trip through Redis
+	retrievedSession, err := repo.GetByID(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, session.UserAgent, retrievedSession.UserAgent)
+	assert.Equal(t, session.IPAddress, retrievedSession.IPAddress)
+	assert.WithinDuration(t, createdAt, retrievedSession.LastSeenAt, time.Second)
+
+	sessions, err := repo.GetByUserID(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, session.UserAgent, sessions[0].UserAgent)
+	assert.Equal(t, session.IPAddress, sessions[0].IPAddress)
+
+	// Marking the session as seen moves LastSeenAt without touching its expiry
+	ttlBefore, err := testRedis.Client.TTL(ctx, "session:"+session.ID).Result()
+	require.NoError(t, err)
+
+	err = repo.UpdateLastSeen(ctx, session.ID)
+	require.NoError(t, err)
+
+	retrievedSession, err = repo.GetByID(ctx, session.ID)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), retrievedSession.LastSeenAt, 2*time.Second)
+	assert.Equal(t, session.UserAgent, retrievedSession.UserAgent)
+	assert.WithinDuration(t, session.ExpiresAt, retrievedSession.ExpiresAt, time.Second)
+
+	ttlAfter, err := testRedis.Client.TTL(ctx, "session:"+session.ID).Result()
+	require.NoError(t, err)
+	assert.InDelta(t, ttlBefore.Seconds(), ttlAfter.Seconds(), 2)
+
+	// A session seen within the last minute is not rewritten
+	recentlySeen := &models.Session{
+		ID:         "metadata-recent-session",
+		UserID:     userID,
+		ExpiresAt:  time.Now().Add(1 * time.Hour),
+		CreatedAt:  createdAt,
+		LastSeenAt: time.Now().Add(-10 * time.Second),
+	}
+	require.NoError(t, repo.Create(ctx, recentlySeen))
+	require.NoError(t, repo.UpdateLastSeen(ctx, recentlySeen.ID))
+	retrievedSession, err = repo.GetByID(ctx, recentlySeen.ID)
+	require.NoError(t, err)
+	assert.WithinDuration(t, recentlySeen.LastSeenAt, retrievedSession.LastSeenAt, time.Second)
+
+	err = repo.UpdateLastSeen(ctx, "non-existent-session")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
 func TestSessionRepository_GetByUserID(t *testing.T) {
 	if testRedis == nil {
 		t.Skip("Redis not available")
@@ -291,6 +367,208 @@ func TestSessionRepository_DeleteByUserID(t *testing.T) {
 	assert.Empty(t, retrievedSessions)
 }
 
+func TestSessionRepository_Refresh(t *testing.T) {
+	if testRedis == nil {
+		t.Skip("Redis not available")
+	}
+
+	repo := NewSessionRepository(testRedis)
+	ctx := context.Background()
+
+	// Clean up before test
+	testRedis.Client.FlushDB(ctx)
+
+	userID := uuid.New()
+	session := &models.Session{
+		ID:        "refresh-test-session",
+		UserID:    userID,
+		ExpiresAt: time.Now().Add(2 * time.Second),
+		CreatedAt: time.Now(),
+	}
+
+	err := repo.Create(ctx, session)
+	require.NoError(t, err)
+
+	newExpiry := time.Now().Add(1 * time.Hour)
+	err = repo.Refresh(ctx, session.ID, newExpiry)
+	require.NoError(t, err)
+
+	// Both the session and the user's session set outlive the original TTL
+	sessionTTL, err := testRedis.Client.TTL(ctx, "session:"+session.ID).Result()
+	require.NoError(t, err)
+	assert.Greater(t, sessionTTL, time.Minute)
+
+	setTTL, err := testRedis.Client.TTL(ctx, "user_sessions:"+userID.String()).Result()
+	require.NoError(t, err)
+	assert.Greater(t, setTTL, time.Minute)
+
+	refreshed, err := repo.GetByID(ctx, session.ID)
+	require.NoError(t, err)
+	assert.WithinDuration(t, newExpiry, refreshed.ExpiresAt, time.Second)
+	assert.WithinDuration(t, session.CreatedAt, refreshed.CreatedAt, time.Second)
+
+	// Refreshing a session that no longer exists fails
+	err = repo.Refresh(ctx, "non-existent-session", newExpiry)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	// So does refreshing to an expiry that has already passed
+	err = repo.Refresh(ctx, session.ID, time.Now().Add(-time.Minute))
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func TestSessionRepository_RefreshAndUpdateLastSeenConcurrently(t *testing.T) {
+	if testRedis == nil {
+		t.Skip("Redis not available")
+	}
+
+	repo := NewSessionRepository(testRedis)
+	ctx := context.Background()
+
+	// Clean up before test
+	testRedis.Client.FlushDB(ctx)
+
+	session := &models.Session{
+		ID:         "concurrent-refresh-session",
+		UserID:     uuid.New(),
+		ExpiresAt:  time.Now().Add(time.Minute),
+		CreatedAt:  time.Now().Add(-time.Hour),
+		LastSeenAt: time.Now().Add(-time.Hour),
+	}
+	require.NoError(t, repo.Create(ctx, session))
+
+	newExpiry := time.Now().Add(time.Hour)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, repo.Refresh(ctx, session.ID, newExpiry))
+		}()
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, repo.UpdateLastSeen(ctx, session.ID))
+		}()
+	}
+	wg.Wait()
+
+	// Neither update overwrote the other's field
+	stored, err := repo.GetByID(ctx, session.ID)
+	require.NoError(t, err)
+	assert.WithinDuration(t, newExpiry, stored.ExpiresAt, time.Second)
+	assert.WithinDuration(t, time.Now(), stored.LastSeenAt, 5*time.Second)
+	assert.Equal(t, session.UserAgent, stored.UserAgent)
+	assert.WithinDuration(t, session.CreatedAt, stored.CreatedAt, time.Second)
+}
+
+func TestSessionRepository_RotateRefreshToken(t *testing.T) {
+	if testRedis == nil {
+		t.Skip("Redis not available")
+	}
+
+	repo := NewSessionRepository(testRedis)
+	ctx := context.Background()
+
+	// Clean up before test
+	testRedis.Client.FlushDB(ctx)
+
+	expiresAt := time.Now().Add(time.Hour)
+	userID := uuid.New()
+	err := repo.Create(ctx, &models.Session{ID: "rotate-test-session", UserID: userID, ExpiresAt: expiresAt, CreatedAt: time.Now()})
+	require.NoError(t, err)
+	err = repo.StoreRefreshToken(ctx, "first-hash", "rotate-test-session", expiresAt)
+	require.NoError(t, err)
+
+	sessionID, err := repo.RotateRefreshToken(ctx, "first-hash", "second-hash", expiresAt)
+	require.NoError(t, err)
+	assert.Equal(t, "rotate-test-session", sessionID)
+
+	ttl, err := testRedis.Client.TTL(ctx, "refresh_token:second-hash").Result()
+	require.NoError(t, err)
+	assert.Greater(t, ttl, time.Minute)
+
+	// A spent token cannot be used again
+	_, err = repo.RotateRefreshToken(ctx, "first-hash", "third-hash", expiresAt)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	exists, err := testRedis.Client.Exists(ctx, "refresh_token:third-hash").Result()
+	require.NoError(t, err)
+	assert.Zero(t, exists)
+
+	// The rotated token is still valid
+	sessionID, err = repo.RotateRefreshToken(ctx, "second-hash", "third-hash", expiresAt)
+	require.NoError(t, err)
+	assert.Equal(t, "rotate-test-session", sessionID)
+
+	_, err = repo.RotateRefreshToken(ctx, "unknown-hash", "fourth-hash", expiresAt)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	err = repo.StoreRefreshToken(ctx, "expired-hash", "rotate-test-session", time.Now().Add(-time.Minute))
+	assert.Error(t, err)
+
+	// Once the session ends its token is spent without a replacement
+	require.NoError(t, repo.DeleteByUserID(ctx, userID))
+	_, err = repo.RotateRefreshToken(ctx, "third-hash", "fourth-hash", expiresAt)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	exists, err = testRedis.Client.Exists(ctx, "refresh_token:third-hash", "refresh_token:fourth-hash").Result()
+	require.NoError(t, err)
+	assert.Zero(t, exists)
+}
+
+func TestSessionRepository_DeleteByUserIDExcept(t *testing.T) {
+	if testRedis == nil {
+		t.Skip("Redis not available")
+	}
+
+	repo := NewSessionRepository(testRedis)
+	ctx := context.Background()
+
+	// Clean up before test
+	testRedis.Client.FlushDB(ctx)
+
+	userID := uuid.New()
+	otherUserID := uuid.New()
+
+	// Create three sessions for the user and one for someone else
+	for i := 1; i <= 3; i++ {
+		err := repo.Create(ctx, &models.Session{
+			ID:        fmt.Sprintf("except-session-%d", i),
+			UserID:    userID,
+			ExpiresAt: time.Now().Add(1 * time.Hour),
+			CreatedAt: time.Now(),
+		})
+		require.NoError(t, err)
+	}
+	err := repo.Create(ctx, &models.Session{
+		ID:        "except-other-user-session",
+		UserID:    otherUserID,
+		ExpiresAt: time.Now().Add(1 * time.Hour),
+		CreatedAt: time.Now(),
+	})
+	require.NoError(t, err)
+
+	// Sign out everywhere except the second session
+	err = repo.DeleteByUserIDExcept(ctx, userID, "except-session-2")
+	require.NoError(t, err)
+
+	// Verify only the kept session survives
+	retrievedSessions, err := repo.GetByUserID(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, retrievedSessions, 1)
+	assert.Equal(t, "except-session-2", retrievedSessions[0].ID)
+
+	members, err := testRedis.Client.SMembers(ctx, "user_sessions:"+userID.String()).Result()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"except-session-2"}, members)
+
+	_, err = repo.GetByID(ctx, "except-session-1")
+	assert.Error(t, err)
+
+	// Other users' sessions are untouched
+	_, err = repo.GetByID(ctx, "except-other-user-session")
+	require.NoError(t, err)
+}
+
 func TestSessionRepository_DeleteExpired(t *testing.T) {
 	if testRedis == nil {
 		t.Skip("Redis not available")
@@ -2,9 +2,12 @@ package spotify
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"time"
 
 	"github.com/zmb3/spotify/v2"
 	spotifyauth "github.com/zmb3/spotify/v2/auth"
@@ -17,6 +20,8 @@ type Client struct {
 	auth         *spotifyauth.Authenticator
 	clientID     string
 	clientSecret string
+	httpClient   *http.Client
+	maxRetries   int
 }
 
 // Config holds the configuration for the Spotify client
@@ -25,6 +30,40 @@ type Config struct {
 	ClientSecret string
 	RedirectURL  string
 	Scopes       []string
+
+	// HTTPClient carries token and API requests; nil uses http.DefaultClient
+	HTTPClient *http.Client
+
+	// MaxRateLimitRetries is how many times an API request rejected with 429
+	// is retried after its Retry-After; zero disables retrying
+	MaxRateLimitRetries int
+}
+
+// TransportConfig tunes the connection pool shared by Spotify clients; zero
+// fields keep the net/http defaults
+type TransportConfig struct {
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+}
+
+// NewTransport returns an HTTP transport meant to be shared by every Spotify
+// client so connections are reused across users and requests
+func NewTransport(config TransportConfig) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if config.MaxIdleConns > 0 {
+		transport.MaxIdleConns = config.MaxIdleConns
+	}
+	if config.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = config.MaxIdleConnsPerHost
+	}
+	if config.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = config.IdleConnTimeout
+	}
+
+	return transport
 }
 
 // NewClient creates a new Spotify client instance
@@ -38,6 +77,8 @@ func NewClient(config Config) *Client {
 		auth:         auth,
 		clientID:     config.ClientID,
 		clientSecret: config.ClientSecret,
+		httpClient:   config.HTTPClient,
+		maxRetries:   config.MaxRateLimitRetries,
 	}
 }
 
@@ -67,11 +108,22 @@ func NewClientFromEnv() *Client {
 			spotifyauth.ScopeUserLibraryRead,
 			spotifyauth.ScopeUserTopRead,
 		},
+		MaxRateLimitRetries: DefaultMaxRateLimitRetries,
 	})
 }
 
+// oauthContext makes oauth2 send token requests through the configured HTTP
+// client and build authorized clients on top of its transport
+func (c *Client) oauthContext(ctx context.Context) context.Context {
+	if c.httpClient == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, oauth2.HTTPClient, c.httpClient)
+}
+
 // GetClientCredentialsClient returns a client using client credentials flow (for public data)
 func (c *Client) GetClientCredentialsClient(ctx context.Context) (*spotify.Client, error) {
+	ctx = c.oauthContext(ctx)
 	config := &clientcredentials.Config{
 		ClientID:     c.clientID,
 		ClientSecret: c.clientSecret,
@@ -83,13 +135,13 @@ func (c *Client) GetClientCredentialsClient(ctx context.Context) (*spotify.Clien
 		return nil, fmt.Errorf("couldn't get token: %w", err)
 	}
 
-	httpClient := spotifyauth.New().Client(ctx, token)
+	httpClient := withRateLimitRetry(spotifyauth.New().Client(ctx, token), c.maxRetries)
 	return spotify.New(httpClient), nil
 }
 
 // GetAuthorizedClient returns a client from an authorization code (for user-specific data)
 func (c *Client) GetAuthorizedClient(ctx context.Context, token *oauth2.Token) *spotify.Client {
-	httpClient := c.auth.Client(ctx, token)
+	httpClient := withRateLimitRetry(c.auth.Client(c.oauthContext(ctx), token), c.maxRetries)
 	return spotify.New(httpClient)
 }
 
@@ -109,12 +161,12 @@ func (c *Client) GetAuthURLWithPKCE(state, codeChallenge string) string {
 
 // ExchangeCode exchanges an authorization code for a token
 func (c *Client) ExchangeCode(ctx context.Context, code string) (*oauth2.Token, error) {
-	return c.auth.Exchange(ctx, code)
+	return c.auth.Exchange(c.oauthContext(ctx), code)
 }
 
 // ExchangeCodeWithPKCE exchanges an authorization code for a token using PKCE
 func (c *Client) ExchangeCodeWithPKCE(ctx context.Context, code, codeVerifier string) (*oauth2.Token, error) {
-	return c.auth.Exchange(ctx, code, oauth2.SetAuthURLParam("code_verifier", codeVerifier))
+	return c.auth.Exchange(c.oauthContext(ctx), code, oauth2.SetAuthURLParam("code_verifier", codeVerifier))
 }
 
 // SearchService provides search functionality
@@ -202,6 +254,11 @@ func (t *TrackService) GetTrack(ctx context.Context, trackID spotify.ID, options
 	return t.client.GetTrack(ctx, trackID, options...)
 }
 
+// GetTracks gets up to 50 tracks by ID; entries for unknown IDs are nil
+func (t *TrackService) GetTracks(ctx context.Context, trackIDs []spotify.ID, options ...spotify.RequestOption) ([]*spotify.FullTrack, error) {
+	return t.client.GetTracks(ctx, trackIDs, options...)
+}
+
 // GetAudioFeatures gets audio features for tracks
 func (t *TrackService) GetAudioFeatures(ctx context.Context, trackIDs ...spotify.ID) ([]*spotify.AudioFeatures, error) {
 	return t.client.GetAudioFeatures(ctx, trackIDs...)
@@ -222,6 +279,11 @@ func (a *AlbumService) GetAlbum(ctx context.Context, albumID spotify.ID, options
 	return a.client.GetAlbum(ctx, albumID, options...)
 }
 
+// GetAlbums gets up to 20 albums by ID; entries for unknown IDs are nil
+func (a *AlbumService) GetAlbums(ctx context.Context, albumIDs []spotify.ID, options ...spotify.RequestOption) ([]*spotify.FullAlbum, error) {
+	return a.client.GetAlbums(ctx, albumIDs, options...)
+}
+
 // GetAlbumTracks gets tracks from an album
 func (a *AlbumService) GetAlbumTracks(ctx context.Context, albumID spotify.ID, options ...spotify.RequestOption) (*spotify.SimpleTrackPage, error) {
 	return a.client.GetAlbumTracks(ctx, albumID, options...)
@@ -237,6 +299,11 @@ func (a *ArtistService) GetArtist(ctx context.Context, artistID spotify.ID) (*sp
 	return a.client.GetArtist(ctx, artistID)
 }
 
+// GetArtists gets up to 50 artists by ID; entries for unknown IDs are nil
+func (a *ArtistService) GetArtists(ctx context.Context, artistIDs ...spotify.ID) ([]*spotify.FullArtist, error) {
+	return a.client.GetArtists(ctx, artistIDs...)
+}
+
 // GetArtistTopTracks gets an artist's top tracks
 func (a *ArtistService) GetArtistTopTracks(ctx context.Context, artistID spotify.ID, country string) ([]spotify.FullTrack, error) {
 	return a.client.GetArtistsTopTracks(ctx, artistID, country)
@@ -0,0 +1,78 @@
+package spotify
+
+import (
+	"github.com/daedal00/muse/backend/internal/models"
+	"github.com/zmb3/spotify/v2"
+)
+
+// spotifyURLKey is the ExternalURLs entry holding an item's open.spotify.com link
+const spotifyURLKey = "spotify"
+
+// ToModelArtist converts a Spotify API artist to the cached model
+func ToModelArtist(artist spotify.SimpleArtist) models.SpotifyArtist {
+	return models.SpotifyArtist{
+		ID:          string(artist.ID),
+		Name:        artist.Name,
+		ExternalURL: artist.ExternalURLs[spotifyURLKey],
+	}
+}
+
+// ToModelAlbum converts a Spotify API album to the cached model. ImageURLs
+// keeps every size Spotify returns, widest first; ImageURL is the widest.
+func ToModelAlbum(album spotify.SimpleAlbum) models.SpotifyAlbum {
+	var imageURLs []string
+	for _, image := range album.Images {
+		if image.URL != "" {
+			imageURLs = append(imageURLs, image.URL)
+		}
+	}
+
+	var imageURL string
+	if len(imageURLs) > 0 {
+		imageURL = imageURLs[0]
+	}
+
+	return models.SpotifyAlbum{
+		ID:          string(album.ID),
+		Name:        album.Name,
+		Artists:     toModelArtists(album.Artists),
+		ReleaseDate: album.ReleaseDate,
+		ImageURL:    imageURL,
+		ImageURLs:   imageURLs,
+		ExternalURL: album.ExternalURLs[spotifyURLKey],
+	}
+}
+
+// ToModelTrack converts a Spotify API track to the cached model; a nil track
+// converts to nil
+func ToModelTrack(track *spotify.FullTrack) *models.SpotifyTrack {
+	if track == nil {
+		return nil
+	}
+
+	var album *models.SpotifyAlbum
+	if track.Album.ID != "" {
+		converted := ToModelAlbum(track.Album)
+		album = &converted
+	}
+
+	return &models.SpotifyTrack{
+		ID:          string(track.ID),
+		Name:        track.Name,
+		Artists:     toModelArtists(track.Artists),
+		Album:       album,
+		DurationMs:  int(track.Duration),
+		TrackNumber: int(track.TrackNumber),
+		PreviewURL:  track.PreviewURL,
+		ExternalURL: track.ExternalURLs[spotifyURLKey],
+	}
+}
+
+// toModelArtists converts every artist, returning an empty slice rather than nil
+func toModelArtists(artists []spotify.SimpleArtist) []models.SpotifyArtist {
+	converted := make([]models.SpotifyArtist, 0, len(artists))
+	for _, artist := range artists {
+		converted = append(converted, ToModelArtist(artist))
+	}
+	return converted
+}
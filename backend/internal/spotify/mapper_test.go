@@ -0,0 +1,102 @@
+package spotify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zmb3/spotify/v2"
+)
+
+func fixtureArtist(id, name string) spotify.SimpleArtist {
+	return spotify.SimpleArtist{
+		ID:           spotify.ID(id),
+		Name:         name,
+		ExternalURLs: map[string]string{"spotify": "https://open.spotify.com/artist/" + id},
+	}
+}
+
+func TestToModelTrack_MultiArtist(t *testing.T) {
+	track := &spotify.FullTrack{
+		SimpleTrack: spotify.SimpleTrack{
+			ID:           "track-1",
+			Name:         "Under Pressure",
+			Artists:      []spotify.SimpleArtist{fixtureArtist("queen", "Queen"), fixtureArtist("bowie", "David Bowie")},
+			Duration:     248000,
+			TrackNumber:  11,
+			PreviewURL:   "https://p.scdn.co/mp3-preview/track-1",
+			ExternalURLs: map[string]string{"spotify": "https://open.spotify.com/track/track-1"},
+		},
+		Album: spotify.SimpleAlbum{
+			ID:          "album-1",
+			Name:        "Hot Space",
+			Artists:     []spotify.SimpleArtist{fixtureArtist("queen", "Queen")},
+			ReleaseDate: "1982-05-21",
+			Images: []spotify.Image{
+				{URL: "https://i.scdn.co/image/640", Width: 640, Height: 640},
+				{URL: "https://i.scdn.co/image/300", Width: 300, Height: 300},
+				{URL: "https://i.scdn.co/image/64", Width: 64, Height: 64},
+			},
+			ExternalURLs: map[string]string{"spotify": "https://open.spotify.com/album/album-1"},
+		},
+	}
+
+	got := ToModelTrack(track)
+	require.NotNil(t, got)
+
+	assert.Equal(t, "track-1", got.ID)
+	assert.Equal(t, "Under Pressure", got.Name)
+	assert.Equal(t, 248000, got.DurationMs)
+	assert.Equal(t, 11, got.TrackNumber)
+	assert.Equal(t, "https://p.scdn.co/mp3-preview/track-1", got.PreviewURL)
+	assert.Equal(t, "https://open.spotify.com/track/track-1", got.ExternalURL)
+
+	require.Len(t, got.Artists, 2)
+	assert.Equal(t, "Queen", got.Artists[0].Name)
+	assert.Equal(t, "bowie", got.Artists[1].ID)
+	assert.Equal(t, "https://open.spotify.com/artist/bowie", got.Artists[1].ExternalURL)
+
+	require.NotNil(t, got.Album)
+	assert.Equal(t, "Hot Space", got.Album.Name)
+	assert.Equal(t, "1982-05-21", got.Album.ReleaseDate)
+	assert.Equal(t, "https://i.scdn.co/image/640", got.Album.ImageURL)
+	assert.Equal(t, []string{
+		"https://i.scdn.co/image/640",
+		"https://i.scdn.co/image/300",
+		"https://i.scdn.co/image/64",
+	}, got.Album.ImageURLs)
+	assert.Equal(t, "https://open.spotify.com/album/album-1", got.Album.ExternalURL)
+}
+
+func TestToModelTrack_MissingData(t *testing.T) {
+	assert.Nil(t, ToModelTrack(nil))
+
+	got := ToModelTrack(&spotify.FullTrack{SimpleTrack: spotify.SimpleTrack{ID: "bare", Name: "Bare"}})
+	require.NotNil(t, got)
+	assert.NotNil(t, got.Artists)
+	assert.Empty(t, got.Artists)
+	assert.Nil(t, got.Album)
+	assert.Empty(t, got.PreviewURL)
+	assert.Empty(t, got.ExternalURL)
+}
+
+func TestToModelAlbum_MissingImages(t *testing.T) {
+	got := ToModelAlbum(spotify.SimpleAlbum{ID: "album-2", Name: "No Art"})
+
+	assert.Equal(t, "album-2", got.ID)
+	assert.Empty(t, got.ImageURL)
+	assert.Nil(t, got.ImageURLs)
+	assert.NotNil(t, got.Artists)
+	assert.Empty(t, got.Artists)
+	assert.Empty(t, got.ExternalURL)
+}
+
+func TestToModelArtist(t *testing.T) {
+	got := ToModelArtist(fixtureArtist("bowie", "David Bowie"))
+	assert.Equal(t, "bowie", got.ID)
+	assert.Equal(t, "David Bowie", got.Name)
+	assert.Equal(t, "https://open.spotify.com/artist/bowie", got.ExternalURL)
+
+	// Artists without external URLs map cleanly
+	assert.Empty(t, ToModelArtist(spotify.SimpleArtist{ID: "x", Name: "X"}).ExternalURL)
+}
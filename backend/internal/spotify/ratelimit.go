@@ -0,0 +1,106 @@
+package spotify
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultMaxRateLimitRetries is how many times NewClientFromEnv clients
+	// retry a request Spotify rejects with 429
+	DefaultMaxRateLimitRetries = 3
+
+	// maxRateLimitWait is the longest Retry-After worth waiting out; longer
+	// waits return the 429 to the caller instead of holding the request open
+	maxRateLimitWait = 30 * time.Second
+
+	// defaultRateLimitWait applies when a 429 carries no usable Retry-After
+	defaultRateLimitWait = time.Second
+)
+
+// rateLimitTransport retries requests Spotify answers with 429 Too Many
+// Requests, sleeping for the Retry-After it sends between attempts
+type rateLimitTransport struct {
+	base       http.RoundTripper
+	maxRetries int
+}
+
+// withRateLimitRetry wraps client's transport so rate-limited requests are
+// retried up to maxRetries times; zero or fewer leaves the client unchanged
+func withRateLimitRetry(client *http.Client, maxRetries int) *http.Client {
+	if maxRetries <= 0 {
+		return client
+	}
+
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	client.Transport = &rateLimitTransport{base: base, maxRetries: maxRetries}
+	return client
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests || attempt > t.maxRetries {
+			return resp, err
+		}
+
+		// A body that cannot be replayed means the request cannot be retried
+		if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+			return resp, nil
+		}
+
+		wait, ok := retryAfter(resp.Header.Get("Retry-After"))
+		if !ok {
+			wait = defaultRateLimitWait
+		}
+		if wait > maxRateLimitWait {
+			return resp, nil
+		}
+
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+
+		log.Printf("[SPOTIFY] Rate limited on %s, retrying in %v (attempt %d of %d)",
+			req.URL.Path, wait, attempt, t.maxRetries)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date
+func retryAfter(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return max(time.Until(at), 0), true
+	}
+	return 0, false
+}
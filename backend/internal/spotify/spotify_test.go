@@ -2,11 +2,19 @@ package spotify
 
 import (
 	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/zmb3/spotify/v2"
 	spotifyauth "github.com/zmb3/spotify/v2/auth"
 	"golang.org/x/oauth2"
 )
@@ -134,6 +142,179 @@ func TestClient_GetAuthorizedClient(t *testing.T) {
 	assert.NotNil(t, spotifyClient)
 }
 
+// recordingTransport answers every request with an empty JSON object and
+// remembers the URLs it was asked for
+type recordingTransport struct {
+	urls []string
+}
+
+func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.urls = append(rt.urls, req.URL.String())
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader("{}")),
+		Request:    req,
+	}, nil
+}
+
+func TestClient_GetAuthorizedClient_UsesConfiguredTransport(t *testing.T) {
+	transport := &recordingTransport{}
+	client := NewClient(Config{
+		ClientID:     "test-client-id",
+		ClientSecret: "test-client-secret",
+		RedirectURL:  "http://localhost:8080/callback",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	ctx := context.Background()
+
+	token := &oauth2.Token{
+		AccessToken: "test-access-token",
+		TokenType:   "Bearer",
+		Expiry:      time.Now().Add(time.Hour),
+	}
+
+	spotifyClient := client.GetAuthorizedClient(ctx, token)
+	_, err := spotifyClient.CurrentUser(ctx)
+	require.NoError(t, err)
+
+	require.Len(t, transport.urls, 1)
+	assert.Contains(t, transport.urls[0], "api.spotify.com")
+}
+
+// stubServerTransport sends every request to a local test server instead of
+// the Spotify API
+type stubServerTransport struct {
+	target *url.URL
+}
+
+func (st *stubServerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = st.target.Scheme
+	req.URL.Host = st.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newRateLimitedClient returns a Spotify client whose API calls hit a stub
+// server that answers the first limited requests with 429 and Retry-After
+func newRateLimitedClient(t *testing.T, limited int, retryAfter string, maxRetries int) (*Client, *atomic.Int32) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if int(calls.Add(1)) <= limited {
+			w.Header().Set("Retry-After", retryAfter)
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":{"status":429,"message":"API rate limit exceeded"}}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"stub-user"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	target, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	client := NewClient(Config{
+		ClientID:            "test-client-id",
+		ClientSecret:        "test-client-secret",
+		RedirectURL:         "http://localhost:8080/callback",
+		HTTPClient:          &http.Client{Transport: &stubServerTransport{target: target}},
+		MaxRateLimitRetries: maxRetries,
+	})
+	return client, &calls
+}
+
+func validToken() *oauth2.Token {
+	return &oauth2.Token{
+		AccessToken: "test-access-token",
+		TokenType:   "Bearer",
+		Expiry:      time.Now().Add(time.Hour),
+	}
+}
+
+func TestClient_RetriesRateLimitedRequests(t *testing.T) {
+	client, calls := newRateLimitedClient(t, 1, "1", 3)
+	ctx := context.Background()
+
+	start := time.Now()
+	user, err := client.GetAuthorizedClient(ctx, validToken()).CurrentUser(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, "stub-user", user.ID)
+	assert.Equal(t, int32(2), calls.Load())
+	assert.GreaterOrEqual(t, time.Since(start), time.Second, "should wait out Retry-After")
+}
+
+func TestClient_RateLimitRetriesExhausted(t *testing.T) {
+	client, calls := newRateLimitedClient(t, 10, "0", 2)
+	ctx := context.Background()
+
+	_, err := client.GetAuthorizedClient(ctx, validToken()).CurrentUser(ctx)
+	require.Error(t, err)
+
+	var spotifyErr spotify.Error
+	require.ErrorAs(t, err, &spotifyErr)
+	assert.Equal(t, http.StatusTooManyRequests, spotifyErr.Status)
+	assert.Equal(t, int32(3), calls.Load(), "one attempt plus two retries")
+}
+
+func TestClient_RateLimitWaitRespectsContext(t *testing.T) {
+	client, calls := newRateLimitedClient(t, 1, "20", 3)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.GetAuthorizedClient(ctx, validToken()).CurrentUser(ctx)
+	require.Error(t, err)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestClient_RateLimitRetryDisabled(t *testing.T) {
+	client, calls := newRateLimitedClient(t, 1, "0", 0)
+	ctx := context.Background()
+
+	_, err := client.GetAuthorizedClient(ctx, validToken()).CurrentUser(ctx)
+	require.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestRetryAfter(t *testing.T) {
+	wait, ok := retryAfter("7")
+	assert.True(t, ok)
+	assert.Equal(t, 7*time.Second, wait)
+
+	wait, ok = retryAfter(time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), wait)
+
+	_, ok = retryAfter("")
+	assert.False(t, ok)
+	_, ok = retryAfter("soon")
+	assert.False(t, ok)
+	_, ok = retryAfter("-3")
+	assert.False(t, ok)
+}
+
+func TestNewTransport(t *testing.T) {
+	transport := NewTransport(TransportConfig{
+		MaxIdleConns:        50,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     30 * time.Second,
+	})
+
+	assert.Equal(t, 50, transport.MaxIdleConns)
+	assert.Equal(t, 10, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, 30*time.Second, transport.IdleConnTimeout)
+	require.NotNil(t, transport.TLSClientConfig)
+
+	// Zero values keep the net/http defaults
+	defaults := NewTransport(TransportConfig{})
+	assert.Equal(t, http.DefaultTransport.(*http.Transport).MaxIdleConns, defaults.MaxIdleConns)
+}
+
 func TestNewServices(t *testing.T) {
 	// Create a mock spotify client
 	config := Config{
@@ -0,0 +1,214 @@
+package spotify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/daedal00/muse/backend/internal/models"
+	"github.com/daedal00/muse/backend/internal/repository"
+	"github.com/zmb3/spotify/v2"
+)
+
+// ErrTrackNotFound is returned for Spotify track IDs that Spotify does not know
+var ErrTrackNotFound = errors.New("spotify track not found")
+
+// trackCacheType namespaces TrackResolver entries in the Spotify item cache,
+// apart from the GraphQL search results the item service caches as "track"
+const trackCacheType = "spotify_track"
+
+// tracksBatchSize is the most IDs Spotify's several-tracks endpoint accepts
+const tracksBatchSize = 50
+
+// TrackCache is the part of the music cache TrackResolver reads and fills
+type TrackCache interface {
+	GetSpotifyItem(ctx context.Context, itemType, id string) ([]byte, error)
+	GetSpotifyItems(ctx context.Context, itemType string, ids []string) (map[string][]byte, error)
+	SetSpotifyItems(ctx context.Context, itemType string, items map[string]interface{}) error
+	SetSpotifyItemsNotFound(ctx context.Context, itemType string, ids []string) error
+}
+
+// TrackFetcher loads tracks from the Spotify API; TrackService implements it
+type TrackFetcher interface {
+	GetTrack(ctx context.Context, trackID spotify.ID, options ...spotify.RequestOption) (*spotify.FullTrack, error)
+	GetTracks(ctx context.Context, trackIDs []spotify.ID, options ...spotify.RequestOption) ([]*spotify.FullTrack, error)
+}
+
+// TrackResolverStats counts how TrackResolver lookups were served
+type TrackResolverStats struct {
+	CacheHits   int64 `json:"cache_hits"`
+	CacheMisses int64 `json:"cache_misses"`
+	APICalls    int64 `json:"api_calls"`
+	CacheErrors int64 `json:"cache_errors"`
+}
+
+// TrackResolver reads tracks through the cache, fetching misses from Spotify
+// and writing them back. Cache failures are logged and treated as misses so
+// a Redis outage only costs API calls.
+type TrackResolver struct {
+	cache   TrackCache
+	fetcher TrackFetcher
+
+	cacheHits   atomic.Int64
+	cacheMisses atomic.Int64
+	apiCalls    atomic.Int64
+	cacheErrors atomic.Int64
+}
+
+// NewTrackResolver creates a track resolver over cache and fetcher
+func NewTrackResolver(cache TrackCache, fetcher TrackFetcher) *TrackResolver {
+	return &TrackResolver{cache: cache, fetcher: fetcher}
+}
+
+// Stats returns the lookup counters accumulated since the resolver was created
+func (r *TrackResolver) Stats() TrackResolverStats {
+	return TrackResolverStats{
+		CacheHits:   r.cacheHits.Load(),
+		CacheMisses: r.cacheMisses.Load(),
+		APICalls:    r.apiCalls.Load(),
+		CacheErrors: r.cacheErrors.Load(),
+	}
+}
+
+// GetTrack returns a track by Spotify ID, or ErrTrackNotFound
+func (r *TrackResolver) GetTrack(ctx context.Context, id string) (*models.SpotifyTrack, error) {
+	data, err := r.cache.GetSpotifyItem(ctx, trackCacheType, id)
+	switch {
+	case errors.Is(err, repository.ErrCachedNotFound):
+		r.cacheHits.Add(1)
+		return nil, ErrTrackNotFound
+	case err != nil:
+		r.cacheErrors.Add(1)
+		log.Printf("[CACHE] Warning: Failed to read cached track %s: %v", id, err)
+	case data != nil:
+		if track, ok := r.decode(id, data); ok {
+			r.cacheHits.Add(1)
+			return track, nil
+		}
+	}
+	r.cacheMisses.Add(1)
+
+	r.apiCalls.Add(1)
+	fullTrack, err := r.fetcher.GetTrack(ctx, spotify.ID(id))
+	if err != nil {
+		var apiErr spotify.Error
+		if errors.As(err, &apiErr) && (apiErr.Status == http.StatusNotFound || apiErr.Status == http.StatusBadRequest) {
+			r.rememberNotFound(ctx, []string{id})
+			return nil, ErrTrackNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch track: %w", err)
+	}
+
+	track := ToModelTrack(fullTrack)
+	r.store(ctx, map[string]interface{}{id: track})
+	return track, nil
+}
+
+// GetTracks returns tracks in the order of ids, with nil entries for IDs
+// Spotify does not know. Cached tracks are read in one batch and the misses
+// fetched in as few API calls as the batch endpoint allows.
+func (r *TrackResolver) GetTracks(ctx context.Context, ids []string) ([]*models.SpotifyTrack, error) {
+	tracks := make([]*models.SpotifyTrack, len(ids))
+	if len(ids) == 0 {
+		return tracks, nil
+	}
+
+	cached, err := r.cache.GetSpotifyItems(ctx, trackCacheType, ids)
+	if err != nil {
+		r.cacheErrors.Add(1)
+		log.Printf("[CACHE] Warning: Failed to read cached tracks: %v", err)
+	}
+
+	found := make(map[string]*models.SpotifyTrack, len(ids))
+	known := make(map[string]bool, len(ids))
+	var misses []string
+	for _, id := range ids {
+		if known[id] {
+			continue
+		}
+		known[id] = true
+
+		if data, ok := cached[id]; ok {
+			// An empty value is a tombstone for an ID Spotify does not know
+			if len(data) == 0 {
+				r.cacheHits.Add(1)
+				continue
+			}
+			if track, ok := r.decode(id, data); ok {
+				r.cacheHits.Add(1)
+				found[id] = track
+				continue
+			}
+		}
+		r.cacheMisses.Add(1)
+		misses = append(misses, id)
+	}
+
+	if len(misses) > 0 {
+		fetched := make(map[string]interface{}, len(misses))
+		for start := 0; start < len(misses); start += tracksBatchSize {
+			batch := misses[start:min(start+tracksBatchSize, len(misses))]
+			batchIDs := make([]spotify.ID, len(batch))
+			for i, id := range batch {
+				batchIDs[i] = spotify.ID(id)
+			}
+
+			r.apiCalls.Add(1)
+			fullTracks, err := r.fetcher.GetTracks(ctx, batchIDs)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch tracks: %w", err)
+			}
+			for _, fullTrack := range fullTracks {
+				if fullTrack == nil {
+					continue
+				}
+				track := ToModelTrack(fullTrack)
+				found[track.ID] = track
+				fetched[track.ID] = track
+			}
+		}
+
+		var notFound []string
+		for _, id := range misses {
+			if _, ok := found[id]; !ok {
+				notFound = append(notFound, id)
+			}
+		}
+		r.store(ctx, fetched)
+		r.rememberNotFound(ctx, notFound)
+	}
+
+	for i, id := range ids {
+		tracks[i] = found[id]
+	}
+	return tracks, nil
+}
+
+// decode unmarshals a cached track, treating corrupt entries as misses
+func (r *TrackResolver) decode(id string, data []byte) (*models.SpotifyTrack, bool) {
+	var track models.SpotifyTrack
+	if err := json.Unmarshal(data, &track); err != nil {
+		r.cacheErrors.Add(1)
+		log.Printf("[CACHE] Warning: Failed to decode cached track %s: %v", id, err)
+		return nil, false
+	}
+	return &track, true
+}
+
+func (r *TrackResolver) store(ctx context.Context, tracks map[string]interface{}) {
+	if err := r.cache.SetSpotifyItems(ctx, trackCacheType, tracks); err != nil {
+		r.cacheErrors.Add(1)
+		log.Printf("[CACHE] Warning: Failed to cache tracks: %v", err)
+	}
+}
+
+func (r *TrackResolver) rememberNotFound(ctx context.Context, ids []string) {
+	if err := r.cache.SetSpotifyItemsNotFound(ctx, trackCacheType, ids); err != nil {
+		r.cacheErrors.Add(1)
+		log.Printf("[CACHE] Warning: Failed to cache missing tracks: %v", err)
+	}
+}
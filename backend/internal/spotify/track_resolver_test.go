@@ -0,0 +1,226 @@
+package spotify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/daedal00/muse/backend/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zmb3/spotify/v2"
+)
+
+// The music cache and track service plug straight into the resolver
+var (
+	_ TrackCache   = repository.MusicCacheRepository(nil)
+	_ TrackFetcher = (*TrackService)(nil)
+)
+
+// memoryTrackCache keeps Spotify items in memory, keyed by type then ID
+type memoryTrackCache struct {
+	items   map[string]map[string][]byte
+	failGet bool
+}
+
+func newMemoryTrackCache() *memoryTrackCache {
+	return &memoryTrackCache{items: make(map[string]map[string][]byte)}
+}
+
+func (c *memoryTrackCache) set(itemType, id string, data []byte) {
+	if c.items[itemType] == nil {
+		c.items[itemType] = make(map[string][]byte)
+	}
+	c.items[itemType][id] = data
+}
+
+func (c *memoryTrackCache) GetSpotifyItem(ctx context.Context, itemType, id string) ([]byte, error) {
+	if c.failGet {
+		return nil, errors.New("redis unavailable")
+	}
+	data, ok := c.items[itemType][id]
+	if !ok {
+		return nil, nil
+	}
+	if len(data) == 0 {
+		return nil, repository.ErrCachedNotFound
+	}
+	return data, nil
+}
+
+func (c *memoryTrackCache) GetSpotifyItems(ctx context.Context, itemType string, ids []string) (map[string][]byte, error) {
+	if c.failGet {
+		return nil, errors.New("redis unavailable")
+	}
+	result := make(map[string][]byte)
+	for _, id := range ids {
+		if data, ok := c.items[itemType][id]; ok {
+			result[id] = data
+		}
+	}
+	return result, nil
+}
+
+func (c *memoryTrackCache) SetSpotifyItems(ctx context.Context, itemType string, items map[string]interface{}) error {
+	for id, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		c.set(itemType, id, data)
+	}
+	return nil
+}
+
+func (c *memoryTrackCache) SetSpotifyItemsNotFound(ctx context.Context, itemType string, ids []string) error {
+	for _, id := range ids {
+		c.set(itemType, id, []byte{})
+	}
+	return nil
+}
+
+// fakeTrackFetcher serves a fixed catalogue and records each API call
+type fakeTrackFetcher struct {
+	tracks     map[spotify.ID]*spotify.FullTrack
+	trackCalls []spotify.ID
+	batchCalls [][]spotify.ID
+}
+
+func newFakeTrackFetcher(ids ...string) *fakeTrackFetcher {
+	f := &fakeTrackFetcher{tracks: make(map[spotify.ID]*spotify.FullTrack)}
+	for _, id := range ids {
+		f.tracks[spotify.ID(id)] = &spotify.FullTrack{
+			SimpleTrack: spotify.SimpleTrack{
+				ID:          spotify.ID(id),
+				Name:        "Track " + id,
+				Artists:     []spotify.SimpleArtist{{ID: "artist-1", Name: "Artist One"}},
+				Duration:    215000,
+				TrackNumber: 3,
+			},
+			Album: spotify.SimpleAlbum{ID: "album-1", Name: "Album One"},
+		}
+	}
+	return f
+}
+
+func (f *fakeTrackFetcher) GetTrack(ctx context.Context, trackID spotify.ID, options ...spotify.RequestOption) (*spotify.FullTrack, error) {
+	f.trackCalls = append(f.trackCalls, trackID)
+	track, ok := f.tracks[trackID]
+	if !ok {
+		return nil, spotify.Error{Message: "non existing id", Status: http.StatusNotFound}
+	}
+	return track, nil
+}
+
+func (f *fakeTrackFetcher) GetTracks(ctx context.Context, trackIDs []spotify.ID, options ...spotify.RequestOption) ([]*spotify.FullTrack, error) {
+	f.batchCalls = append(f.batchCalls, trackIDs)
+	tracks := make([]*spotify.FullTrack, len(trackIDs))
+	for i, id := range trackIDs {
+		tracks[i] = f.tracks[id]
+	}
+	return tracks, nil
+}
+
+func TestTrackResolver_GetTrack_CachesAPIResult(t *testing.T) {
+	cache := newMemoryTrackCache()
+	fetcher := newFakeTrackFetcher("t1")
+	resolver := NewTrackResolver(cache, fetcher)
+	ctx := context.Background()
+
+	track, err := resolver.GetTrack(ctx, "t1")
+	require.NoError(t, err)
+	assert.Equal(t, "Track t1", track.Name)
+	assert.Equal(t, 215000, track.DurationMs)
+	require.Len(t, track.Artists, 1)
+	require.NotNil(t, track.Album)
+	assert.Equal(t, "album-1", track.Album.ID)
+
+	// The second lookup is served from the cache
+	again, err := resolver.GetTrack(ctx, "t1")
+	require.NoError(t, err)
+	assert.Equal(t, track, again)
+	assert.Len(t, fetcher.trackCalls, 1)
+
+	assert.Equal(t, TrackResolverStats{CacheHits: 1, CacheMisses: 1, APICalls: 1}, resolver.Stats())
+}
+
+func TestTrackResolver_GetTrack_RemembersNotFound(t *testing.T) {
+	cache := newMemoryTrackCache()
+	fetcher := newFakeTrackFetcher()
+	resolver := NewTrackResolver(cache, fetcher)
+	ctx := context.Background()
+
+	_, err := resolver.GetTrack(ctx, "missing")
+	assert.ErrorIs(t, err, ErrTrackNotFound)
+
+	_, err = resolver.GetTrack(ctx, "missing")
+	assert.ErrorIs(t, err, ErrTrackNotFound)
+	assert.Len(t, fetcher.trackCalls, 1, "the tombstone should skip the API")
+}
+
+func TestTrackResolver_GetTrack_CacheFailureFallsBackToAPI(t *testing.T) {
+	cache := newMemoryTrackCache()
+	cache.failGet = true
+	fetcher := newFakeTrackFetcher("t1")
+	resolver := NewTrackResolver(cache, fetcher)
+
+	track, err := resolver.GetTrack(context.Background(), "t1")
+	require.NoError(t, err)
+	assert.Equal(t, "t1", track.ID)
+	assert.Equal(t, int64(1), resolver.Stats().CacheErrors)
+}
+
+func TestTrackResolver_GetTracks_BatchesMisses(t *testing.T) {
+	cache := newMemoryTrackCache()
+	fetcher := newFakeTrackFetcher("t1", "t2", "t3")
+	resolver := NewTrackResolver(cache, fetcher)
+	ctx := context.Background()
+
+	// Warm the cache with one track
+	_, err := resolver.GetTrack(ctx, "t2")
+	require.NoError(t, err)
+
+	tracks, err := resolver.GetTracks(ctx, []string{"t1", "t2", "missing", "t3", "t1"})
+	require.NoError(t, err)
+	require.Len(t, tracks, 5)
+	assert.Equal(t, "t1", tracks[0].ID)
+	assert.Equal(t, "t2", tracks[1].ID)
+	assert.Nil(t, tracks[2])
+	assert.Equal(t, "t3", tracks[3].ID)
+	assert.Equal(t, "t1", tracks[4].ID)
+
+	// Only the misses went to Spotify, in one batch call
+	require.Len(t, fetcher.batchCalls, 1)
+	assert.ElementsMatch(t, []spotify.ID{"t1", "missing", "t3"}, fetcher.batchCalls[0])
+
+	// Everything, including the unknown ID, is now cached
+	tracks, err = resolver.GetTracks(ctx, []string{"t1", "missing", "t3"})
+	require.NoError(t, err)
+	assert.Nil(t, tracks[1])
+	assert.Len(t, fetcher.batchCalls, 1)
+}
+
+func TestTrackResolver_GetTracks_SplitsLargeBatches(t *testing.T) {
+	ids := make([]string, 0, tracksBatchSize+5)
+	for i := 0; i < tracksBatchSize+5; i++ {
+		ids = append(ids, fmt.Sprintf("track-%02d", i))
+	}
+	cache := newMemoryTrackCache()
+	fetcher := newFakeTrackFetcher(ids...)
+	resolver := NewTrackResolver(cache, fetcher)
+
+	tracks, err := resolver.GetTracks(context.Background(), ids)
+	require.NoError(t, err)
+	require.Len(t, tracks, len(ids))
+	for i, track := range tracks {
+		require.NotNil(t, track)
+		assert.Equal(t, ids[i], track.ID)
+	}
+
+	require.Len(t, fetcher.batchCalls, 2)
+	assert.Len(t, fetcher.batchCalls[0], tracksBatchSize)
+	assert.Len(t, fetcher.batchCalls[1], 5)
+}
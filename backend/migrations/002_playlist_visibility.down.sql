@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_playlists_is_public;
+
+ALTER TABLE playlists DROP COLUMN IF EXISTS is_public;
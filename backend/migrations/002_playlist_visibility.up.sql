@@ -0,0 +1,4 @@
+-- Playlist visibility; existing playlists stay public
+ALTER TABLE playlists ADD COLUMN is_public BOOLEAN NOT NULL DEFAULT TRUE;
+
+CREATE INDEX idx_playlists_is_public ON playlists(is_public);
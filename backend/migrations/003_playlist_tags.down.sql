@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_playlists_tags;
+
+ALTER TABLE playlists DROP COLUMN IF EXISTS tags;
@@ -0,0 +1,4 @@
+-- Free-form playlist tags used for discovery
+ALTER TABLE playlists ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX idx_playlists_tags ON playlists USING GIN (tags);
@@ -0,0 +1,10 @@
+-- Outbox of review changes for external search indexers
+CREATE TABLE review_search_events (
+    id BIGSERIAL PRIMARY KEY,
+    review_id UUID NOT NULL,
+    event_type VARCHAR(16) NOT NULL CHECK (event_type IN ('created', 'updated', 'deleted')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    processed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_review_search_events_pending ON review_search_events(id) WHERE processed_at IS NULL;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_users_created_at_id;
+DROP INDEX IF EXISTS idx_playlists_created_at_id;
+DROP INDEX IF EXISTS idx_reviews_created_at_id;
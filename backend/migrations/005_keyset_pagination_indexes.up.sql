@@ -0,0 +1,4 @@
+-- Indexes backing (created_at, id) keyset pagination
+CREATE INDEX idx_reviews_created_at_id ON reviews(created_at DESC, id DESC);
+CREATE INDEX idx_playlists_created_at_id ON playlists(created_at DESC, id DESC);
+CREATE INDEX idx_users_created_at_id ON users(created_at DESC, id DESC);
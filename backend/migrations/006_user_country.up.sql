@@ -0,0 +1,2 @@
+-- Reviewer country (ISO 3166-1 alpha-2), taken from the user's Spotify profile when available
+ALTER TABLE users ADD COLUMN country VARCHAR(2);
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_playlists_deleted_at;
+DROP INDEX IF EXISTS idx_reviews_deleted_at;
+
+-- Soft-deleted rows cannot survive the return of the full unique constraint
+DELETE FROM reviews WHERE deleted_at IS NOT NULL;
+DELETE FROM playlists WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_reviews_user_album_active;
+ALTER TABLE reviews ADD CONSTRAINT reviews_user_id_album_id_key UNIQUE (user_id, album_id);
+
+ALTER TABLE playlists DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE reviews DROP COLUMN IF EXISTS deleted_at;
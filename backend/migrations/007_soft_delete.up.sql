@@ -0,0 +1,10 @@
+-- Soft delete for reviews and playlists; rows are purged by a maintenance job
+ALTER TABLE reviews ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE playlists ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- A soft-deleted review must not stop the user from reviewing the album again
+ALTER TABLE reviews DROP CONSTRAINT reviews_user_id_album_id_key;
+CREATE UNIQUE INDEX idx_reviews_user_album_active ON reviews(user_id, album_id) WHERE deleted_at IS NULL;
+
+CREATE INDEX idx_reviews_deleted_at ON reviews(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_playlists_deleted_at ON playlists(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,2 @@
+-- Lets reviewers mark reviews that give away an album's story
+ALTER TABLE reviews ADD COLUMN has_spoiler BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,4 @@
+-- When the user last made an authenticated request; maintained by the auth middleware
+ALTER TABLE users ADD COLUMN last_active_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_users_last_active_at ON users(last_active_at);
@@ -0,0 +1,16 @@
+-- Renumber each playlist's tracks 1..n so existing duplicate or gapped
+-- positions do not block the constraint below
+WITH ranked AS (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY playlist_id ORDER BY position, added_at, id) AS new_position
+    FROM playlist_tracks
+)
+UPDATE playlist_tracks pt
+SET position = ranked.new_position
+FROM ranked
+WHERE pt.id = ranked.id AND pt.position <> ranked.new_position;
+
+-- Deferred so shifting a range of positions by one inside a transaction does
+-- not trip the constraint halfway through
+ALTER TABLE playlist_tracks
+    ADD CONSTRAINT playlist_tracks_playlist_id_position_key
+    UNIQUE (playlist_id, position) DEFERRABLE INITIALLY DEFERRED;
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS maintain_album_review_stats ON reviews;
+DROP FUNCTION IF EXISTS maintain_album_review_stats();
+DROP TABLE IF EXISTS album_review_stats;
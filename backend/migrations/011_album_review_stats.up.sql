@@ -0,0 +1,38 @@
+-- Running review count and rating sum per album so hot album pages read
+-- their stats from one row instead of aggregating reviews every view
+CREATE TABLE album_review_stats (
+    album_id UUID PRIMARY KEY REFERENCES albums(id) ON DELETE CASCADE,
+    review_count INTEGER NOT NULL DEFAULT 0,
+    rating_sum BIGINT NOT NULL DEFAULT 0
+);
+
+INSERT INTO album_review_stats (album_id, review_count, rating_sum)
+SELECT album_id, COUNT(*), SUM(rating)
+FROM reviews
+WHERE deleted_at IS NULL
+GROUP BY album_id;
+
+-- Only live reviews count; soft deletes and restores move a review out of
+-- and back into its album's stats
+CREATE OR REPLACE FUNCTION maintain_album_review_stats()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') AND OLD.deleted_at IS NULL THEN
+        UPDATE album_review_stats
+        SET review_count = review_count - 1, rating_sum = rating_sum - OLD.rating
+        WHERE album_id = OLD.album_id;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') AND NEW.deleted_at IS NULL THEN
+        INSERT INTO album_review_stats (album_id, review_count, rating_sum)
+        VALUES (NEW.album_id, 1, NEW.rating)
+        ON CONFLICT (album_id) DO UPDATE
+        SET review_count = album_review_stats.review_count + 1,
+            rating_sum = album_review_stats.rating_sum + EXCLUDED.rating_sum;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER maintain_album_review_stats AFTER INSERT OR UPDATE OR DELETE ON reviews FOR EACH ROW EXECUTE FUNCTION maintain_album_review_stats();
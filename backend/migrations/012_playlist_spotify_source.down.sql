@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_playlists_creator_spotify_playlist;
+ALTER TABLE playlists DROP COLUMN IF EXISTS spotify_playlist_id;
@@ -0,0 +1,7 @@
+-- Remember which Spotify playlist an imported playlist came from so it can
+-- be synced and is not imported twice by the same user
+ALTER TABLE playlists ADD COLUMN spotify_playlist_id VARCHAR(255);
+
+CREATE UNIQUE INDEX idx_playlists_creator_spotify_playlist
+    ON playlists(creator_id, spotify_playlist_id)
+    WHERE spotify_playlist_id IS NOT NULL AND deleted_at IS NULL;
@@ -0,0 +1,11 @@
+-- Per-user taste and settings; artist IDs are Spotify IDs so favourites work
+-- before an artist has been imported
+CREATE TABLE user_preferences (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    preferred_genres TEXT[] NOT NULL DEFAULT '{}',
+    favorite_artist_ids TEXT[] NOT NULL DEFAULT '{}',
+    notification_settings JSONB NOT NULL DEFAULT '{}',
+    privacy_settings JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
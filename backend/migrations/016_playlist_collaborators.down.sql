@@ -0,0 +1 @@
+DROP TABLE IF EXISTS playlist_collaborators;
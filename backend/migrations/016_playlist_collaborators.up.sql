@@ -0,0 +1,9 @@
+-- Users other than the creator who may edit a playlist's tracks
+CREATE TABLE playlist_collaborators (
+    playlist_id UUID NOT NULL REFERENCES playlists(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (playlist_id, user_id)
+);
+
+CREATE INDEX idx_playlist_collaborators_user ON playlist_collaborators(user_id);
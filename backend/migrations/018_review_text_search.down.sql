@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_reviews_text_search;
+ALTER TABLE reviews DROP COLUMN IF EXISTS review_text_search;
@@ -0,0 +1,5 @@
+-- Full-text search over review bodies
+ALTER TABLE reviews ADD COLUMN review_text_search tsvector
+    GENERATED ALWAYS AS (to_tsvector('english', COALESCE(review_text, ''))) STORED;
+
+CREATE INDEX idx_reviews_text_search ON reviews USING GIN (review_text_search);
@@ -0,0 +1,7 @@
+-- Emails are stored lowercased and unique regardless of case, so
+-- Test@Example.com and test@example.com cannot both register. This fails if
+-- existing accounts already differ only by case; merge those first.
+UPDATE users SET email = LOWER(email) WHERE email <> LOWER(email);
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_lower ON users (LOWER(email));
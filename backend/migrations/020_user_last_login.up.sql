@@ -0,0 +1,2 @@
+-- When the user last signed in successfully; set by the login mutation
+ALTER TABLE users ADD COLUMN last_login_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,4 @@
+-- Authorization role carried in each user's tokens. Accounts are promoted to
+-- admin directly in the database.
+ALTER TABLE users ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'user'
+    CHECK (role IN ('user', 'admin'));
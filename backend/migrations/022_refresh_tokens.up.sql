@@ -0,0 +1,10 @@
+-- Hashes of refresh tokens, each tied to the session it renews. A token is
+-- deleted when it is exchanged for a new one, so it can only be used once.
+CREATE TABLE refresh_tokens (
+    token_hash VARCHAR(64) PRIMARY KEY,
+    session_id VARCHAR(255) NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_refresh_tokens_session_id ON refresh_tokens(session_id);
@@ -0,0 +1,10 @@
+// Package migrations embeds the SQL schema migrations so the migrate tool
+// can apply them without the files being present on disk
+package migrations
+
+import "embed"
+
+// FS holds every up and down migration
+//
+//go:embed *.sql
+var FS embed.FS
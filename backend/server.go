@@ -2,11 +2,16 @@ package main
 
 import (
 	"context"
-	"fmt"
+	"encoding/json"
 	"log"
+	"log/slog"
+	"math"
+	"net"
 	"net/http"
+	"net/netip"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -19,35 +24,48 @@ import (
 	"github.com/daedal00/muse/backend/auth"
 	"github.com/daedal00/muse/backend/graph"
 	"github.com/daedal00/muse/backend/internal/config"
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/daedal00/muse/backend/internal/logging"
+	"github.com/google/uuid"
 	"github.com/vektah/gqlparser/v2/ast"
 )
 
-// Request logging middleware
-func loggingMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		start := time.Now()
-
-		// Log incoming request
-		log.Printf("[REQUEST] %s %s from %s - User-Agent: %s",
-			r.Method, r.URL.Path, r.RemoteAddr, r.UserAgent())
+// maxRequestIDLength bounds an X-Request-ID accepted from upstream proxies
+const maxRequestIDLength = 128
+
+// loggingMiddleware writes one structured line per request to logger. Each
+// request gets an ID, taken from an upstream X-Request-ID header or generated,
+// that is echoed in the response header and stored in the context for
+// logging.FromContext.
+func loggingMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			requestID := r.Header.Get("X-Request-ID")
+			if requestID == "" || len(requestID) > maxRequestIDLength {
+				requestID = uuid.NewString()
+			}
+			w.Header().Set("X-Request-ID", requestID)
 
-		// Check for GraphQL query in body for POST requests
-		if r.Method == "POST" && strings.Contains(r.Header.Get("Content-Type"), "application/json") {
-			log.Printf("[GRAPHQL] Processing GraphQL request")
-		}
+			ctx, collected := logging.WithAttrCollector(logging.WithRequestID(r.Context(), requestID))
 
-		// Create a response writer wrapper to capture status code
-		wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			// Create a response writer wrapper to capture status code
+			wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
 
-		// Call next handler
-		next.ServeHTTP(wrapped, r)
+			next.ServeHTTP(wrapped, r.WithContext(ctx))
 
-		// Log response
-		duration := time.Since(start)
-		log.Printf("[RESPONSE] %s %s - Status: %d - Duration: %v",
-			r.Method, r.URL.Path, wrapped.statusCode, duration)
-	})
+			attrs := []slog.Attr{
+				slog.String("request_id", requestID),
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", wrapped.statusCode),
+				slog.Duration("duration", time.Since(start)),
+				slog.String("remote_addr", r.RemoteAddr),
+				slog.String("user_agent", r.UserAgent()),
+			}
+			logger.LogAttrs(ctx, slog.LevelInfo, "request", append(attrs, collected()...)...)
+		})
+	}
 }
 
 // Response writer wrapper to capture status code
@@ -85,7 +103,133 @@ func corsMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// rateLimiter counts request hits in a sliding window
+type rateLimiter interface {
+	AllowSlidingWindow(ctx context.Context, key string, limit int, window time.Duration) (bool, time.Duration, error)
+}
+
+// rateLimitMiddleware allows each client IP requestsPerMinute requests over
+// the trailing minute, answering 429 with Retry-After beyond that. Limiting
+// fails open: if Redis is unavailable requests are served rather than
+// rejected. A non-positive limit disables the middleware.
+func rateLimitMiddleware(limiter rateLimiter, requestsPerMinute int, trustedProxies []netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if requestsPerMinute <= 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := clientIP(r, trustedProxies)
+			allowed, retryAfter, err := limiter.AllowSlidingWindow(r.Context(), "ratelimit:"+ip, requestsPerMinute, time.Minute)
+			if err != nil {
+				log.Printf("[RATELIMIT] Warning: Failed to check rate limit for %s, allowing request: %v", ip, err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !allowed {
+				seconds := max(int(math.Ceil(retryAfter.Seconds())), 1)
+				log.Printf("[RATELIMIT] ❌ Rate limit exceeded for %s, retry after %ds", ip, seconds)
+				w.Header().Set("Retry-After", strconv.Itoa(seconds))
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusTooManyRequests)
+				_, _ = w.Write([]byte(`{"error":"rate limit exceeded"}`))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientIP returns the originating client address. X-Forwarded-For is only
+// honored when the peer is a trusted proxy; the chain is then walked from the
+// right, skipping trusted hops, so a client cannot choose its own address by
+// prepending entries.
+func clientIP(r *http.Request, trustedProxies []netip.Prefix) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if !isTrustedProxy(host, trustedProxies) {
+		return host
+	}
+
+	forwarded := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(forwarded[i])
+		if _, err := netip.ParseAddr(hop); err != nil {
+			break
+		}
+		host = hop
+		if !isTrustedProxy(hop, trustedProxies) {
+			break
+		}
+	}
+	return host
+}
+
+// isTrustedProxy reports whether ip falls in one of the trusted ranges
+func isTrustedProxy(ip string, trustedProxies []netip.Prefix) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientInfoMiddleware records the request's user agent and client IP for
+// resolvers that store where a user signed in from
+func clientInfoMiddleware(trustedProxies []netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := graph.WithClientInfo(r.Context(), graph.ClientInfo{UserAgent: r.UserAgent(), IPAddress: clientIP(r, trustedProxies)})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// readinessHandler reports whether every dependency is reachable, answering
+// 503 with the failing dependencies so load balancers can take the instance
+// out of rotation
+func readinessHandler(check func() map[string]error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := "ok"
+		code := http.StatusOK
+		dependencies := make(map[string]string)
+		for name, err := range check() {
+			if err != nil {
+				log.Printf("[HEALTH] ❌ %s is not ready: %v", name, err)
+				status = "unavailable"
+				code = http.StatusServiceUnavailable
+				dependencies[name] = err.Error()
+				continue
+			}
+			dependencies[name] = "ok"
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":       status,
+			"dependencies": dependencies,
+		})
+	})
+}
+
 func main() {
+	// Log JSON lines; slog.SetDefault also routes the standard log package
+	// through the same handler
+	logger := logging.NewLogger(os.Stdout)
+	slog.SetDefault(logger)
+	requestLogging := loggingMiddleware(logger)
+
 	log.Println("🚀 Starting Muse Backend Server...")
 
 	// Load configuration
@@ -102,14 +246,26 @@ func main() {
 	if err != nil {
 		log.Fatalf("[ERROR] Failed to initialize resolver: %v", err)
 	}
-	defer resolver.Close()
+	defer func() {
+		if err := resolver.Close(); err != nil {
+			log.Printf("[SHUTDOWN] Resources did not close cleanly: %v", err)
+			return
+		}
+		log.Println("[SHUTDOWN] ✅ All connections closed")
+	}()
 	log.Println("[INIT] ✅ Database and Redis connections established")
 
+	// Run periodic maintenance until shutdown
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	jobsDone := make(chan struct{})
+	go func() {
+		defer close(jobsDone)
+		resolver.BackgroundJobs().Run(jobsCtx)
+	}()
+
 	// Create GraphQL server
 	log.Println("[GRAPHQL] Setting up GraphQL server...")
-	srv := handler.New(graph.NewExecutableSchema(
-		graph.Config{Resolvers: resolver},
-	))
+	srv := handler.New(graph.NewExecutableSchema(graph.NewConfig(resolver)))
 
 	srv.AddTransport(transport.Options{})
 	srv.AddTransport(transport.GET{})
@@ -119,8 +275,11 @@ func main() {
 	})
 
 	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
+	srv.SetRecoverFunc(graph.RecoverFunc)
+	srv.SetErrorPresenter(graph.ErrorPresenter)
 
 	srv.Use(extension.Introspection{})
+	srv.Use(extension.FixedComplexityLimit(cfg.GraphQLComplexityLimit))
 	srv.Use(extension.AutomaticPersistedQuery{
 		Cache: lru.New[string](100),
 	})
@@ -130,66 +289,22 @@ func main() {
 	log.Println("[ROUTES] Setting up HTTP routes...")
 	http.Handle("/", playground.Handler("GraphQL playground", "/query"))
 
-	// Wrap query with CORS, logging, and auth middleware
-	http.Handle("/query", corsMiddleware(loggingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Extract raw authorization header
-		authHeader := r.Header.Get("Authorization")
-		baseCtx := r.Context()
-		newCtx := baseCtx
-
-		var userID string
-		var authStatus string
-
-		// Extract "Bearer <jwtToken>"
-		if strings.HasPrefix(authHeader, "Bearer ") {
-			tokStr := strings.TrimSpace(strings.TrimPrefix(authHeader, "Bearer "))
-			log.Printf("[AUTH] Processing JWT token (length: %d)", len(tokStr))
-
-			// Parse and validate
-			token, err := jwt.ParseWithClaims(tokStr, &auth.CustomClaims{}, func(t *jwt.Token) (interface{}, error) {
-				// Ensure HMAC is used
-				if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-					return nil, fmt.Errorf("unexpected signing method")
-				}
-				return []byte(cfg.JWTSecret), nil
-			})
-
-			if err != nil {
-				log.Printf("[AUTH] ❌ JWT validation failed: %v", err)
-				authStatus = "invalid"
-			} else if token.Valid {
-				claims := token.Claims.(*auth.CustomClaims)
-				userID = claims.UserID
-				// Put user ID into GraphQL context
-				newCtx = context.WithValue(baseCtx, graph.UserIDKey, claims.UserID)
-				log.Printf("[AUTH] ✅ User authenticated: %s", userID)
-				authStatus = "authenticated"
-			} else {
-				log.Printf("[AUTH] ❌ Invalid token")
-				authStatus = "invalid"
-			}
-		} else if authHeader != "" {
-			log.Printf("[AUTH] ❌ Invalid authorization header format")
-			authStatus = "malformed"
-		} else {
-			log.Printf("[AUTH] No authorization header - anonymous request")
-			authStatus = "anonymous"
-		}
-
-		log.Printf("[AUTH] Request status: %s, UserID: %s", authStatus, userID)
-
-		// Call gqlgen server using r.WithContext(ctx) so resolvers can see it
-		srv.ServeHTTP(w, r.WithContext(newCtx))
-	}))))
+	// Wrap query with CORS, logging, rate limiting, dataloader, client info, and auth middleware
+	http.Handle("/query", corsMiddleware(requestLogging(rateLimitMiddleware(resolver.RateLimiter(), cfg.RateLimitRequestsPerMinute, cfg.TrustedProxies)(
+		resolver.LoaderMiddleware(clientInfoMiddleware(cfg.TrustedProxies)(auth.JWTMiddlewareWithSessions(cfg.JWTSecret, resolver.SessionActive)(resolver.ActivityMiddleware(srv)))),
+	))))
 
 	// Add health check endpoint with CORS and logging
-	http.Handle("/health", corsMiddleware(loggingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	http.Handle("/health", corsMiddleware(requestLogging(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		log.Printf("[HEALTH] Health check requested")
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte(`{"status":"ok"}`))
 	}))))
 
+	// Readiness checks Postgres and Redis; /health above stays a liveness check
+	http.Handle("/health/ready", corsMiddleware(requestLogging(readinessHandler(resolver.CheckHealth))))
+
 	log.Println("[ROUTES] ✅ HTTP routes configured")
 
 	// Set up graceful shutdown
@@ -206,6 +321,7 @@ func main() {
 		log.Printf("🚀 Server ready at http://localhost:%s/", cfg.Port)
 		log.Printf("🕹  GraphQL playground at http://localhost:%s/", cfg.Port)
 		log.Printf("💚 Health check at http://localhost:%s/health", cfg.Port)
+		log.Printf("💚 Readiness check at http://localhost:%s/health/ready", cfg.Port)
 		log.Printf("📊 Accepting requests from http://localhost:3000 (CORS enabled)")
 
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -227,5 +343,13 @@ func main() {
 		log.Printf("[ERROR] Server forced to shutdown: %v", err)
 	}
 
+	// Let running jobs finish before the deferred close of their connections
+	stopJobs()
+	select {
+	case <-jobsDone:
+	case <-ctx.Done():
+		log.Printf("[ERROR] Background jobs did not stop in time")
+	}
+
 	log.Println("✅ Server exited")
 }